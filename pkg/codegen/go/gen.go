@@ -99,9 +99,16 @@ type pkgContext struct {
 	tool          string
 	packages      map[string]*pkgContext
 
+	// functionOutputVersions is true if the Output-returning variants of functions should be generated.
+	functionOutputVersions bool
+
 	// Name overrides set in GoPackageInfo
 	modToPkg         map[string]string // Module name -> package name
 	pkgImportAliases map[string]string // Package name -> import alias
+
+	// externalImportAliases maps the import paths of external packages whose default names collide to the aliases
+	// under which they are imported instead.
+	externalImportAliases map[string]string
 }
 
 func (pkg *pkgContext) detailsForType(t schema.Type) *typeDetails {
@@ -361,23 +368,12 @@ func (pkg *pkgContext) resolveResourceType(t *schema.ResourceType) string {
 		return pkg.tokenToResource(t.Token)
 	}
 	extPkg := t.Resource.Package
-	var goInfo GoPackageInfo
-
-	contract.AssertNoError(extPkg.ImportLanguages(map[string]schema.Language{"go": Importer}))
-	if info, ok := extPkg.Language["go"].(GoPackageInfo); ok {
-		goInfo = info
-	}
-	extPkgCtx := &pkgContext{
-		pkg:              extPkg,
-		importBasePath:   goInfo.ImportBasePath,
-		pkgImportAliases: goInfo.PackageImportAliases,
-		modToPkg:         goInfo.ModuleToPackage,
-	}
+	extPkgCtx, _ := externalPackageContext(extPkg)
 	resType := extPkgCtx.tokenToResource(t.Token)
 	if !strings.Contains(resType, ".") {
 		resType = fmt.Sprintf("%s.%s", extPkg.Name, resType)
 	}
-	return resType
+	return pkg.externalQualifiedName(extPkg, t.Token, resType)
 }
 
 // resolveObjectType resolves resource references in properties while
@@ -388,20 +384,54 @@ func (pkg *pkgContext) resolveObjectType(t *schema.ObjectType) string {
 	if !pkg.isExternalReference(t) {
 		return pkg.tokenToType(t.Token)
 	}
-	extPkg := t.Package
+	extPkgCtx, _ := externalPackageContext(t.Package)
+	return pkg.externalQualifiedName(t.Package, t.Token, extPkgCtx.plainType(t, false))
+}
+
+// externalPackageContext returns a package context for resolving references to members of the given external package,
+// along with the package's Go language info.
+func externalPackageContext(extPkg *schema.Package) (*pkgContext, GoPackageInfo) {
 	var goInfo GoPackageInfo
 
 	contract.AssertNoError(extPkg.ImportLanguages(map[string]schema.Language{"go": Importer}))
 	if info, ok := extPkg.Language["go"].(GoPackageInfo); ok {
 		goInfo = info
+	} else {
+		// tests don't include ImportBasePath
+		goInfo.ImportBasePath = extractImportBasePath(extPkg)
 	}
-	extPkgCtx := &pkgContext{
+	return &pkgContext{
 		pkg:              extPkg,
 		importBasePath:   goInfo.ImportBasePath,
 		pkgImportAliases: goInfo.PackageImportAliases,
 		modToPkg:         goInfo.ModuleToPackage,
+	}, goInfo
+}
+
+// externalImport returns the path of the Go package that defines the member of the given external package with the
+// given token, along with the alias under which that package is imported, if any.
+func (pkg *pkgContext) externalImport(extPkg *schema.Package, tok string) (string, string) {
+	extPkgCtx, goInfo := externalPackageContext(extPkg)
+	imp := path.Join(goInfo.ImportBasePath, extPkgCtx.tokenToPackage(tok))
+	if alias, ok := pkg.externalImportAliases[imp]; ok {
+		return imp, alias
+	}
+	return imp, goInfo.PackageImportAliases[imp]
+}
+
+// externalQualifiedName replaces the package qualifier of the given type name, which refers to the member of the given
+// external package with the given token, with the alias that was assigned to the member's package, if any.
+func (pkg *pkgContext) externalQualifiedName(extPkg *schema.Package, tok, name string) string {
+	if len(pkg.externalImportAliases) == 0 {
+		return name
 	}
-	return extPkgCtx.plainType(t, false)
+	imp, _ := pkg.externalImport(extPkg, tok)
+	if alias, ok := pkg.externalImportAliases[imp]; ok {
+		if dot := strings.Index(name, "."); dot != -1 {
+			return alias + name[dot:]
+		}
+	}
+	return name
 }
 
 func (pkg *pkgContext) outputType(t schema.Type, optional bool) string {
@@ -470,7 +500,7 @@ func (pkg *pkgContext) outputType(t schema.Type, optional bool) string {
 }
 
 func printComment(w io.Writer, comment string, indent bool) int {
-	comment = codegen.FilterExamples(comment, "go")
+	comment = codegen.FilterExamples(strings.Replace(comment, "\r\n", "\n", -1), "go")
 
 	lines := strings.Split(comment, "\n")
 	for len(lines) > 0 && lines[len(lines)-1] == "" {
@@ -679,6 +709,17 @@ func (pkg *pkgContext) genEnumType(w io.Writer, name string, enumType *schema.En
 		}
 	}
 	fmt.Fprintln(w, ")")
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "// EnumValues returns the known values of %s.\n", name)
+	fmt.Fprintf(w, "func (%s) EnumValues() []interface{} {\n", name)
+	fmt.Fprintf(w, "return []interface{}{\n")
+	for _, e := range enumType.Elements {
+		fmt.Fprintf(w, "%s,\n", e.Name)
+	}
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "}")
+
 	inputType := pkg.inputType(enumType, false)
 	contract.Assertf(name == inputType,
 		"expect inputType (%s) for enums to be the same as enum type (%s)", inputType, enumType)
@@ -864,6 +905,32 @@ func genOutputMethods(w io.Writer, name, elementType string, resourceType bool)
 	fmt.Fprintf(w, "}\n\n")
 }
 
+// genResourceOutputAccessors emits the URN and, for custom resources, ID accessors for the output type of a resource.
+// These allow the URN and ID of a resource that is only available as an output (e.g. a resource reference within an
+// object output) to be used as outputs that depend on the resource. The inner ApplyT resolves to the resource's own
+// URN or ID output, which the outer ApplyT awaits in turn.
+func genResourceOutputAccessors(w io.Writer, name string, custom bool) {
+	genAccessor := func(method, typ string) {
+		fmt.Fprintf(w, "func (o %sOutput) %s() pulumi.%sOutput {\n", name, method, typ)
+		fmt.Fprintf(w, "\treturn o.ApplyT(func(v *%s) pulumi.%sOutput {\n", name, typ)
+		fmt.Fprintf(w, "\t\tif v == nil {\n")
+		fmt.Fprintf(w, "\t\t\treturn pulumi.%[1]s(\"\").To%[1]sOutput()\n", typ)
+		fmt.Fprintf(w, "\t\t}\n")
+		fmt.Fprintf(w, "\t\treturn v.%s()\n", method)
+		fmt.Fprintf(w, "\t}).ApplyT(func(v interface{}) pulumi.%[1]s {\n", typ)
+		fmt.Fprintf(w, "\t\treturn v.(pulumi.%s)\n", typ)
+		fmt.Fprintf(w, "\t}).(pulumi.%sOutput)\n", typ)
+		fmt.Fprintf(w, "}\n\n")
+	}
+
+	fmt.Fprintf(w, "// URN is the URN of the resource.\n")
+	genAccessor("URN", "URN")
+	if custom {
+		fmt.Fprintf(w, "// ID is the provider-assigned unique ID of the resource.\n")
+		genAccessor("ID", "ID")
+	}
+}
+
 func (pkg *pkgContext) genOutputTypes(w io.Writer, t *schema.ObjectType, details *typeDetails) {
 	name := pkg.tokenToType(t.Token)
 
@@ -1299,6 +1366,7 @@ func (pkg *pkgContext) genResource(w io.Writer, r *schema.Resource, generateReso
 	fmt.Fprintf(w, "\t*pulumi.OutputState\n")
 	fmt.Fprintf(w, "}\n\n")
 	genOutputMethods(w, name, name, true)
+	genResourceOutputAccessors(w, name, !r.IsComponent)
 	fmt.Fprintf(w, "\n")
 	if generateResourceContainerTypes {
 		fmt.Fprintf(w, "func (o %[1]sOutput) To%[2]sPtrOutput() %[1]sPtrOutput {\n", name, Title(name))
@@ -1372,6 +1440,28 @@ func (pkg *pkgContext) genFunction(w io.Writer, f *schema.Function) {
 	}
 	fmt.Fprintf(w, "func %s(%s, opts ...pulumi.InvokeOption) %s {\n", name, argsig, retty)
 
+	// Ensure required arguments are present before the invoke is dispatched, so that a missing argument is reported
+	// here rather than by the provider.
+	if pkg.functionHasRequiredInputs(f) {
+		failure := "nil, "
+		if f.Outputs == nil {
+			failure = ""
+		}
+
+		fmt.Fprintf(w, "\tif args == nil {\n")
+		fmt.Fprintf(w, "\t\treturn %serrors.New(\"missing one or more required arguments\")\n", failure)
+		fmt.Fprintf(w, "\t}\n")
+		for _, p := range f.Inputs.Properties {
+			if p.IsRequired && isNilableType(pkg.plainType(p.Type, false)) {
+				fmt.Fprintf(w, "\tif args.%s == nil {\n", Title(p.Name))
+				fmt.Fprintf(w, "\t\treturn %serrors.New(\"invalid value for required argument '%s'\")\n", failure,
+					Title(p.Name))
+				fmt.Fprintf(w, "\t}\n")
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Make a map of inputs to pass to the runtime function.
 	var inputsVar string
 	if f.Inputs == nil {
@@ -1412,6 +1502,98 @@ func (pkg *pkgContext) genFunction(w io.Writer, f *schema.Function) {
 		fmt.Fprintf(w, "\n")
 		pkg.genPlainType(w, fmt.Sprintf("%sResult", name), f.Outputs.Comment, "", f.Outputs.Properties)
 	}
+
+	if pkg.needsOutputVersion(f) {
+		pkg.genFunctionOutputVersion(w, f)
+	}
+}
+
+// functionHasRequiredInputs returns true if the given function has any required arguments.
+func (pkg *pkgContext) functionHasRequiredInputs(f *schema.Function) bool {
+	if f.Inputs == nil {
+		return false
+	}
+	for _, p := range f.Inputs.Properties {
+		if p.IsRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// isNilableType returns true if the given plain Go type has nil as a value, in which case a nil value of a required
+// property means that the property is missing.
+func isNilableType(typ string) bool {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return true
+	case typ == "interface{}", typ == "pulumi.Archive", typ == "pulumi.AssetOrArchive":
+		return true
+	default:
+		return false
+	}
+}
+
+// needsOutputVersion returns true if an Output-returning variant of the given function should be generated. Functions
+// without arguments or results do not need one, as there is nothing to await or nothing to chain.
+func (pkg *pkgContext) needsOutputVersion(f *schema.Function) bool {
+	return pkg.functionOutputVersions && f.Inputs != nil && f.Outputs != nil
+}
+
+// genFunctionOutputVersion emits the Output-returning variant of a function, along with its input arguments type and
+// its result output type. The variant awaits its arguments, invokes the function, and resolves to its result, so that
+// an invoke can be chained with the rest of a program's outputs.
+func (pkg *pkgContext) genFunctionOutputVersion(w io.Writer, f *schema.Function) {
+	name := pkg.functionNames[f]
+
+	printCommentWithDeprecationMessage(w, "", f.DeprecationMessage, false)
+	fmt.Fprintf(w, "func %[1]sOutput(ctx *pulumi.Context, args %[1]sOutputArgs, opts ...pulumi.InvokeOption) %[1]sResultOutput {\n", name)
+	fmt.Fprintf(w, "\treturn pulumi.ToOutputWithContext(context.Background(), args).\n")
+	fmt.Fprintf(w, "\t\tApplyT(func(v interface{}) (%sResult, error) {\n", name)
+	fmt.Fprintf(w, "\t\t\targs := v.(%sArgs)\n", name)
+	fmt.Fprintf(w, "\t\t\tr, err := %s(ctx, &args, opts...)\n", name)
+	fmt.Fprintf(w, "\t\t\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\t\t\treturn %sResult{}, err\n", name)
+	fmt.Fprintf(w, "\t\t\t}\n")
+	fmt.Fprintf(w, "\t\t\treturn *r, nil\n")
+	fmt.Fprintf(w, "\t\t}).(%sResultOutput)\n", name)
+	fmt.Fprintf(w, "}\n\n")
+
+	printComment(w, f.Inputs.Comment, false)
+	fmt.Fprintf(w, "type %sOutputArgs struct {\n", name)
+	for _, p := range f.Inputs.Properties {
+		printCommentWithDeprecationMessage(w, p.Comment, p.DeprecationMessage, true)
+		fmt.Fprintf(w, "\t%s %s `pulumi:\"%s\"`\n", Title(p.Name), pkg.inputType(p.Type, !p.IsRequired), p.Name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "func (%sOutputArgs) ElementType() reflect.Type {\n", name)
+	fmt.Fprintf(w, "\treturn reflect.TypeOf((*%sArgs)(nil)).Elem()\n", name)
+	fmt.Fprintf(w, "}\n\n")
+
+	printComment(w, f.Outputs.Comment, false)
+	fmt.Fprintf(w, "type %sResultOutput struct{ *pulumi.OutputState }\n\n", name)
+
+	genOutputMethods(w, name+"Result", name+"Result", false)
+
+	for _, p := range f.Outputs.Properties {
+		printCommentWithDeprecationMessage(w, p.Comment, p.DeprecationMessage, false)
+		outputType, applyType := pkg.outputType(p.Type, !p.IsRequired), pkg.plainType(p.Type, !p.IsRequired)
+
+		propName := Title(p.Name)
+		switch strings.ToLower(p.Name) {
+		case "elementtype", "issecret":
+			propName = "Get" + propName
+		}
+		fmt.Fprintf(w, "func (o %sResultOutput) %s() %s {\n", name, propName, outputType)
+		fmt.Fprintf(w, "\treturn o.ApplyT(func(v %sResult) %s { return v.%s }).(%s)\n", name, applyType, Title(p.Name),
+			outputType)
+		fmt.Fprintf(w, "}\n\n")
+	}
+
+	fmt.Fprintf(w, "func init() {\n")
+	fmt.Fprintf(w, "\tpulumi.RegisterOutputType(%sResultOutput{})\n", name)
+	fmt.Fprintf(w, "}\n")
 }
 
 func (pkg *pkgContext) genType(w io.Writer, obj *schema.ObjectType) {
@@ -1579,26 +1761,9 @@ func (pkg *pkgContext) getTypeImports(t schema.Type, recurse bool, importsAndAli
 	case *schema.MapType:
 		pkg.getTypeImports(t.ElementType, recurse, importsAndAliases, seen)
 	case *schema.ObjectType:
-		if t.Package != nil && pkg.pkg != nil && t.Package != pkg.pkg {
-			extPkg := t.Package
-			var goInfo GoPackageInfo
-
-			contract.AssertNoError(extPkg.ImportLanguages(map[string]schema.Language{"go": Importer}))
-			if info, ok := extPkg.Language["go"].(GoPackageInfo); ok {
-				goInfo = info
-			} else {
-				// tests don't include ImportBasePath
-				goInfo.ImportBasePath = extractImportBasePath(extPkg)
-			}
-			extPkgCtx := &pkgContext{
-				pkg:              extPkg,
-				importBasePath:   goInfo.ImportBasePath,
-				pkgImportAliases: goInfo.PackageImportAliases,
-				modToPkg:         goInfo.ModuleToPackage,
-			}
-			mod := extPkgCtx.tokenToPackage(t.Token)
-			imp := path.Join(goInfo.ImportBasePath, mod)
-			importsAndAliases[imp] = goInfo.PackageImportAliases[imp]
+		if pkg.isExternalReference(t) {
+			imp, alias := pkg.externalImport(t.Package, t.Token)
+			importsAndAliases[imp] = alias
 			break
 		}
 		mod := pkg.tokenToPackage(t.Token)
@@ -1613,26 +1778,9 @@ func (pkg *pkgContext) getTypeImports(t schema.Type, recurse bool, importsAndAli
 			}
 		}
 	case *schema.ResourceType:
-		if t.Resource != nil && pkg.pkg != nil && t.Resource.Package != pkg.pkg {
-			extPkg := t.Resource.Package
-			var goInfo GoPackageInfo
-
-			contract.AssertNoError(extPkg.ImportLanguages(map[string]schema.Language{"go": Importer}))
-			if info, ok := extPkg.Language["go"].(GoPackageInfo); ok {
-				goInfo = info
-			} else {
-				// tests don't include ImportBasePath
-				goInfo.ImportBasePath = extractImportBasePath(extPkg)
-			}
-			extPkgCtx := &pkgContext{
-				pkg:              extPkg,
-				importBasePath:   goInfo.ImportBasePath,
-				pkgImportAliases: goInfo.PackageImportAliases,
-				modToPkg:         goInfo.ModuleToPackage,
-			}
-			mod := extPkgCtx.tokenToPackage(t.Token)
-			imp := path.Join(goInfo.ImportBasePath, mod)
-			importsAndAliases[imp] = goInfo.PackageImportAliases[imp]
+		if pkg.isExternalReference(t) {
+			imp, alias := pkg.externalImport(t.Resource.Package, t.Token)
+			importsAndAliases[imp] = alias
 			break
 		}
 		mod := pkg.tokenToPackage(t.Token)
@@ -1678,6 +1826,9 @@ func (pkg *pkgContext) getImports(member interface{}, importsAndAliases map[stri
 		if member.Inputs != nil {
 			pkg.getTypeImports(member.Inputs, true, importsAndAliases, seen)
 		}
+		if pkg.functionHasRequiredInputs(member) {
+			importsAndAliases["github.com/pkg/errors"] = ""
+		}
 		if member.Outputs != nil {
 			pkg.getTypeImports(member.Outputs, true, importsAndAliases, seen)
 		}
@@ -1779,7 +1930,7 @@ func (pkg *pkgContext) genConfig(w io.Writer, variables []*schema.Property) erro
 			fmt.Fprintf(w, "\tif err == nil {\n")
 			fmt.Fprintf(w, "\t\treturn v\n")
 			fmt.Fprintf(w, "\t}\n")
-			fmt.Fprintf(w, "\treturn %s", defaultValue)
+			fmt.Fprintf(w, "\treturn %s\n", defaultValue)
 		} else {
 			fmt.Fprintf(w, "\treturn config.%s%s(ctx, %s)\n", getfunc, funcType, configKey)
 		}
@@ -1916,6 +2067,8 @@ func generatePackageContextMap(tool string, pkg *schema.Package, goInfo GoPackag
 				modToPkg:         goInfo.ModuleToPackage,
 				pkgImportAliases: goInfo.PackageImportAliases,
 				packages:         packages,
+
+				functionOutputVersions: !goInfo.DisableFunctionOutputVersions,
 			}
 			packages[mod] = pack
 		}
@@ -2063,11 +2216,145 @@ func generatePackageContextMap(tool string, pkg *schema.Package, goInfo GoPackag
 		if f.Outputs != nil {
 			pkg.names.Add(name + "Result")
 		}
+		if pkg.needsOutputVersion(f) {
+			pkg.names.Add(name + "Output")
+			pkg.names.Add(name + "OutputArgs")
+			pkg.names.Add(name + "ResultOutput")
+
+			// The Output-returning variant uses the input and output types of the function's properties, so those
+			// need the same details as the properties of resources.
+			populateDetailsForPropertyTypes(seenMap, f.Inputs.Properties, false)
+			populateDetailsForPropertyTypes(seenMap, f.Outputs.Properties, false)
+		}
+	}
+
+	if aliases := computeExternalImportAliases(pkg, packages); len(aliases) > 0 {
+		for _, pack := range packages {
+			pack.externalImportAliases = aliases
+		}
 	}
 
 	return packages
 }
 
+// computeExternalImportAliases assigns aliases to the external packages referenced by pkg whose default names would
+// collide with each other or with the names of the packages generated for pkg. Every package involved in a collision
+// is aliased, and aliases are assigned in import path order, so the result does not depend on the order in which the
+// references appear in the schema.
+func computeExternalImportAliases(pkg *schema.Package, packages map[string]*pkgContext) map[string]string {
+	scope := &pkgContext{pkg: pkg}
+
+	// Find the default qualifier of each referenced external package.
+	qualifiers, impQualifiers := map[string]codegen.StringSet{}, map[string]string{}
+	extPkgs := map[string]*schema.Package{}
+	addReference := func(extPkg *schema.Package, tok, name string) {
+		dot := strings.Index(name, ".")
+		if dot == -1 {
+			return
+		}
+		imp, _ := scope.externalImport(extPkg, tok)
+		qualifier := name[:dot]
+		if _, ok := qualifiers[qualifier]; !ok {
+			qualifiers[qualifier] = codegen.NewStringSet()
+		}
+		qualifiers[qualifier].Add(imp)
+		impQualifiers[imp], extPkgs[imp] = qualifier, extPkg
+	}
+
+	seen := map[schema.Type]bool{}
+	var visitType func(t schema.Type)
+	visitProperties := func(props []*schema.Property) {
+		for _, p := range props {
+			visitType(p.Type)
+		}
+	}
+	visitType = func(t schema.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		switch t := t.(type) {
+		case *schema.ArrayType:
+			visitType(t.ElementType)
+		case *schema.MapType:
+			visitType(t.ElementType)
+		case *schema.UnionType:
+			for _, e := range t.ElementTypes {
+				visitType(e)
+			}
+		case *schema.ObjectType:
+			if scope.isExternalReference(t) {
+				addReference(t.Package, t.Token, scope.resolveObjectType(t))
+				return
+			}
+			visitProperties(t.Properties)
+		case *schema.ResourceType:
+			if scope.isExternalReference(t) {
+				addReference(t.Resource.Package, t.Token, scope.resolveResourceType(t))
+			}
+		}
+	}
+
+	for _, t := range pkg.Types {
+		visitType(t)
+	}
+	for _, r := range append([]*schema.Resource{pkg.Provider}, pkg.Resources...) {
+		if r != nil {
+			visitProperties(r.InputProperties)
+			visitProperties(r.Properties)
+		}
+	}
+	for _, f := range pkg.Functions {
+		if f.Inputs != nil {
+			visitType(f.Inputs)
+		}
+		if f.Outputs != nil {
+			visitType(f.Outputs)
+		}
+	}
+
+	// The names of the packages generated for pkg are always taken.
+	taken := codegen.NewStringSet()
+	for mod := range packages {
+		if mod == "" {
+			mod = pkg.Name
+		}
+		taken.Add(strings.Replace(strings.Replace(mod, "/", "", -1), "-provider", "", -1))
+	}
+
+	var conflicts []string
+	for qualifier, imps := range qualifiers {
+		if len(imps) > 1 || taken.Has(qualifier) {
+			conflicts = append(conflicts, imps.SortedValues()...)
+		} else {
+			taken.Add(qualifier)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+
+	aliases := map[string]string{}
+	for _, imp := range conflicts {
+		name, alias := goPackage(extPkgs[imp].Name), impQualifiers[imp]
+		if alias != name {
+			alias = name + alias
+		}
+		for i, candidate := 2, alias; ; i++ {
+			if !taken.Has(candidate) {
+				alias = candidate
+				break
+			}
+			candidate = fmt.Sprintf("%s%d", alias, i)
+		}
+		taken.Add(alias)
+		aliases[imp] = alias
+	}
+	return aliases
+}
+
 // LanguageResource is derived from the schema and can be used by downstream codegen.
 type LanguageResource struct {
 	*schema.Resource
@@ -2204,8 +2491,13 @@ func GeneratePackage(tool string, pkg *schema.Package) (map[string][]byte, error
 			importsAndAliases := map[string]string{}
 			pkg.getImports(f, importsAndAliases)
 
+			var goImports []string
+			if pkg.needsOutputVersion(f) {
+				goImports = []string{"context", "reflect"}
+			}
+
 			buffer := &bytes.Buffer{}
-			pkg.genHeader(buffer, nil, importsAndAliases)
+			pkg.genHeader(buffer, goImports, importsAndAliases)
 
 			pkg.genFunction(buffer, f)
 
@@ -1,7 +1,9 @@
 package gen
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -151,6 +153,58 @@ func (mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
 	return args.Args, nil
 }
 
+func TestGenerateConfig(t *testing.T) {
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "example",
+		Config: schema.ConfigSpec{
+			Variables: map[string]schema.PropertySpec{
+				"region": {
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Description: "The region to deploy to.",
+					Default:     "us-west-2",
+					DefaultInfo: &schema.DefaultSpec{Environment: []string{"EXAMPLE_REGION"}},
+				},
+				"skipChecks": {
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+					DefaultInfo: &schema.DefaultSpec{Environment: []string{"EXAMPLE_SKIP_CHECKS"}},
+				},
+				"maxRetries": {
+					TypeSpec: schema.TypeSpec{Type: "integer"},
+				},
+			},
+		},
+	}, map[string]schema.Language{"go": Importer})
+	require.NoError(t, err)
+
+	files, err := GeneratePackage("test", pkg)
+	require.NoError(t, err)
+	config, ok := files[filepath.Join("example", "config", "config.go")]
+	require.True(t, ok)
+	_, ok = files[filepath.Join("example", "config", "pulumiUtilities.go")]
+	assert.True(t, ok)
+
+	// Each variable gets a typed getter that falls back to its schema default and environment variables.
+	source := string(config)
+	assert.Contains(t, source, "package config")
+	assert.Contains(t, source, strings.Join([]string{
+		"// The region to deploy to.",
+		"func GetRegion(ctx *pulumi.Context) string {",
+		"\tv, err := config.Try(ctx, \"example:region\")",
+		"\tif err == nil {",
+		"\t\treturn v",
+		"\t}",
+		"\treturn getEnvOrDefault(\"us-west-2\", nil, \"EXAMPLE_REGION\").(string)",
+		"}",
+	}, "\n"))
+	assert.Contains(t, source,
+		"\treturn getEnvOrDefault(false, parseEnvBool, \"EXAMPLE_SKIP_CHECKS\").(bool)\n")
+	assert.Contains(t, source, strings.Join([]string{
+		"func GetMaxRetries(ctx *pulumi.Context) int {",
+		"\treturn config.GetInt(ctx, \"example:maxRetries\")",
+		"}",
+	}, "\n"))
+}
+
 func TestEnumUsage(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		require.NoError(t, pulumi.RunErr(func(ctx *pulumi.Context) error {
@@ -264,3 +318,263 @@ func TestEnumUsage(t *testing.T) {
 		}, pulumi.WithMocks("project", "stack", mocks(1))))
 	})
 }
+
+func TestGenerateFunctionOutputVersions(t *testing.T) {
+	generate := func(language json.RawMessage) string {
+		pkg, err := schema.ImportSpec(schema.PackageSpec{
+			Name: "example",
+			Functions: map[string]schema.FunctionSpec{
+				"example::getWidget": {
+					Inputs: &schema.ObjectTypeSpec{
+						Properties: map[string]schema.PropertySpec{"name": {TypeSpec: schema.TypeSpec{Type: "string"}}},
+						Required:   []string{"name"},
+					},
+					Outputs: &schema.ObjectTypeSpec{
+						Properties: map[string]schema.PropertySpec{"size": {TypeSpec: schema.TypeSpec{Type: "integer"}}},
+					},
+				},
+				"example::reset": {},
+			},
+			Language: map[string]json.RawMessage{"go": language},
+		}, map[string]schema.Language{"go": Importer})
+		require.NoError(t, err)
+
+		files, err := GeneratePackage("test", pkg)
+		require.NoError(t, err)
+		_, ok := files[filepath.Join("example", "reset.go")]
+		require.True(t, ok)
+		return string(files[filepath.Join("example", "getWidget.go")])
+	}
+
+	// By default, functions with arguments and results get an Output-returning variant.
+	source := generate(json.RawMessage(`{}`))
+	assert.Contains(t, source, "func GetWidgetOutput(ctx *pulumi.Context, args GetWidgetOutputArgs, "+
+		"opts ...pulumi.InvokeOption) GetWidgetResultOutput {\n")
+	assert.Contains(t, source, "\tName pulumi.StringInput `pulumi:\"name\"`\n")
+	assert.Contains(t, source, "func (o GetWidgetResultOutput) Size() pulumi.IntPtrOutput {\n")
+	assert.Contains(t, source, "\tpulumi.RegisterOutputType(GetWidgetResultOutput{})\n")
+
+	source = generate(json.RawMessage(`{"disableFunctionOutputVersions": true}`))
+	assert.NotContains(t, source, "GetWidgetOutput")
+	assert.NotContains(t, source, "\"reflect\"")
+}
+
+func TestGenerateFunctionRequiredProperties(t *testing.T) {
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "example",
+		Functions: map[string]schema.FunctionSpec{
+			"example::getWidget": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"name":   {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"filter": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+					Required: []string{"name"},
+				},
+				Outputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"id":   {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"size": {TypeSpec: schema.TypeSpec{Type: "integer"}},
+					},
+					Required: []string{"id"},
+				},
+			},
+		},
+	}, map[string]schema.Language{"go": Importer})
+	require.NoError(t, err)
+
+	files, err := GeneratePackage("test", pkg)
+	require.NoError(t, err)
+	source := string(files[filepath.Join("example", "getWidget.go")])
+
+	// Required properties have value types, and optional properties have pointer types.
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetWidgetArgs struct {",
+		"\tFilter *string `pulumi:\"filter\"`",
+		"\tName   string  `pulumi:\"name\"`",
+		"}",
+	}, "\n"))
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetWidgetResult struct {",
+		"\tId   string `pulumi:\"id\"`",
+		"\tSize *int   `pulumi:\"size\"`",
+		"}",
+	}, "\n"))
+
+	// The same distinction carries over to the Output-returning variant.
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetWidgetOutputArgs struct {",
+		"\tFilter pulumi.StringPtrInput `pulumi:\"filter\"`",
+		"\tName   pulumi.StringInput    `pulumi:\"name\"`",
+		"}",
+	}, "\n"))
+	assert.Contains(t, source, "func (o GetWidgetResultOutput) Id() pulumi.StringOutput {\n")
+	assert.Contains(t, source, "func (o GetWidgetResultOutput) Size() pulumi.IntPtrOutput {\n")
+}
+
+func TestGenerateFunctionRequiredArgumentChecks(t *testing.T) {
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "example",
+		Functions: map[string]schema.FunctionSpec{
+			"example::getWidget": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"name":   {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"tags":   {TypeSpec: schema.TypeSpec{Type: "array", Items: &schema.TypeSpec{Type: "string"}}},
+						"filter": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+					Required: []string{"name", "tags"},
+				},
+			},
+			"example::listWidgets": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{"filter": {TypeSpec: schema.TypeSpec{Type: "string"}}},
+				},
+			},
+		},
+	}, map[string]schema.Language{"go": Importer})
+	require.NoError(t, err)
+
+	files, err := GeneratePackage("test", pkg)
+	require.NoError(t, err)
+
+	// Missing required arguments are reported before the function is invoked. Arguments whose types cannot be nil
+	// are only checked by requiring that the arguments themselves are present.
+	source := string(files[filepath.Join("example", "getWidget.go")])
+	assert.Contains(t, source, "\t\"github.com/pkg/errors\"\n")
+	assert.Contains(t, source, strings.Join([]string{
+		"func GetWidget(ctx *pulumi.Context, args *GetWidgetArgs, opts ...pulumi.InvokeOption) error {",
+		"\tif args == nil {",
+		"\t\treturn errors.New(\"missing one or more required arguments\")",
+		"\t}",
+		"\tif args.Tags == nil {",
+		"\t\treturn errors.New(\"invalid value for required argument 'Tags'\")",
+		"\t}",
+		"",
+		"\tvar rv struct{}",
+	}, "\n"))
+
+	// Functions without required arguments are unchanged.
+	source = string(files[filepath.Join("example", "listWidgets.go")])
+	assert.NotContains(t, source, "errors")
+}
+
+func TestGenerateCollidingExternalImports(t *testing.T) {
+	importExternal := func(name, mod string) *schema.Package {
+		pkg, err := schema.ImportSpec(schema.PackageSpec{
+			Name: name,
+			Resources: map[string]schema.ResourceSpec{
+				name + ":" + mod + ":Bucket": {},
+			},
+			Language: map[string]json.RawMessage{
+				"go": json.RawMessage(`{"importBasePath": "example.com/` + name + `/sdk/go/` + name + `"}`),
+			},
+		}, map[string]schema.Language{"go": Importer})
+		require.NoError(t, err)
+		return pkg
+	}
+	externals := map[string]*schema.Package{
+		"alpha":   importExternal("alpha", "storage"),
+		"beta":    importExternal("beta", "storage"),
+		"gamma":   importExternal("gamma", "compute"),
+		"example": importExternal("delta", "example"),
+	}
+
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "example",
+		Functions: map[string]schema.FunctionSpec{
+			"example::getBuckets": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"alpha":   {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"beta":    {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"gamma":   {TypeSpec: schema.TypeSpec{Type: "string"}},
+						"example": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+				},
+			},
+		},
+	}, map[string]schema.Language{"go": Importer})
+	require.NoError(t, err)
+
+	// Point each property at a resource of the corresponding external package.
+	for _, p := range pkg.Functions[0].Inputs.Properties {
+		res := externals[p.Name].Resources[0]
+		p.Type = &schema.ResourceType{Token: res.Token, Resource: res}
+	}
+
+	files, err := GeneratePackage("test", pkg)
+	require.NoError(t, err)
+
+	// Packages whose default names collide with each other or with the generated package are aliased, and the
+	// remaining packages keep their default names.
+	source := string(files[filepath.Join("example", "getBuckets.go")])
+	assert.Contains(t, source, "\talphastorage \"example.com/alpha/sdk/go/alpha/storage\"\n")
+	assert.Contains(t, source, "\tbetastorage \"example.com/beta/sdk/go/beta/storage\"\n")
+	assert.Contains(t, source, "\tdeltaexample \"example.com/delta/sdk/go/delta/example\"\n")
+	assert.Contains(t, source, "\t\"example.com/gamma/sdk/go/gamma/compute\"\n")
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetBucketsArgs struct {",
+		"\tAlpha   *alphastorage.Bucket `pulumi:\"alpha\"`",
+		"\tBeta    *betastorage.Bucket  `pulumi:\"beta\"`",
+		"\tExample *deltaexample.Bucket `pulumi:\"example\"`",
+		"\tGamma   *compute.Bucket      `pulumi:\"gamma\"`",
+		"}",
+	}, "\n"))
+}
+
+func TestGenerateFunctionDocComments(t *testing.T) {
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "example",
+		Functions: map[string]schema.FunctionSpec{
+			"example::getWidget": {
+				Description: "Gets a widget.\r\n\r\nWidgets are looked up by name.",
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"name": {
+							TypeSpec:           schema.TypeSpec{Type: "string"},
+							Description:        "The name of the widget.",
+							DeprecationMessage: "Use id instead.",
+						},
+					},
+				},
+				Outputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"size": {TypeSpec: schema.TypeSpec{Type: "integer"}, Description: "The size of the widget."},
+					},
+				},
+			},
+		},
+	}, map[string]schema.Language{"go": Importer})
+	require.NoError(t, err)
+
+	files, err := GeneratePackage("test", pkg)
+	require.NoError(t, err)
+
+	source := string(files[filepath.Join("example", "getWidget.go")])
+	assert.NotContains(t, source, "\r")
+	assert.Contains(t, source, strings.Join([]string{
+		"// Gets a widget.",
+		"//",
+		"// Widgets are looked up by name.",
+		"func GetWidget(",
+	}, "\n"))
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetWidgetArgs struct {",
+		"\t// The name of the widget.",
+		"\t//",
+		"\t// Deprecated: Use id instead.",
+		"\tName *string `pulumi:\"name\"`",
+		"}",
+	}, "\n"))
+	assert.Contains(t, source, strings.Join([]string{
+		"type GetWidgetResult struct {",
+		"\t// The size of the widget.",
+		"\tSize *int `pulumi:\"size\"`",
+		"}",
+	}, "\n"))
+	assert.Contains(t, source, strings.Join([]string{
+		"// The size of the widget.",
+		"func (o GetWidgetResultOutput) Size() pulumi.IntPtrOutput {",
+	}, "\n"))
+}
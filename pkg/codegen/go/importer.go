@@ -42,6 +42,10 @@ type GoPackageInfo struct {
 	// Generate container types (arrays, maps, pointer output types etc.) for each resource.
 	// These are typically used to support external references.
 	GenerateResourceContainerTypes bool `json:"generateResourceContainerTypes,omitempty"`
+
+	// Do not generate the Output-returning variants of functions (e.g. `GetFooOutput`), which accept their arguments
+	// as inputs and return their results as outputs.
+	DisableFunctionOutputVersions bool `json:"disableFunctionOutputVersions,omitempty"`
 }
 
 // Importer implements schema.Language for Go.
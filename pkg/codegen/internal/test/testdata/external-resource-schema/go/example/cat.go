@@ -185,6 +185,30 @@ func (o CatOutput) ToCatOutputWithContext(ctx context.Context) CatOutput {
 	return o
 }
 
+// URN is the URN of the resource.
+func (o CatOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Cat) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o CatOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Cat) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func (o CatOutput) ToCatPtrOutput() CatPtrOutput {
 	return o.ToCatPtrOutputWithContext(context.Background())
 }
@@ -193,6 +193,30 @@ func (o ComponentOutput) ToComponentOutputWithContext(ctx context.Context) Compo
 	return o
 }
 
+// URN is the URN of the resource.
+func (o ComponentOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Component) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o ComponentOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Component) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func (o ComponentOutput) ToComponentPtrOutput() ComponentPtrOutput {
 	return o.ToComponentPtrOutputWithContext(context.Background())
 }
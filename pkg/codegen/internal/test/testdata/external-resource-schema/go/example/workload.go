@@ -182,6 +182,30 @@ func (o WorkloadOutput) ToWorkloadOutputWithContext(ctx context.Context) Workloa
 	return o
 }
 
+// URN is the URN of the resource.
+func (o WorkloadOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Workload) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o WorkloadOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Workload) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func (o WorkloadOutput) ToWorkloadPtrOutput() WorkloadPtrOutput {
 	return o.ToWorkloadPtrOutputWithContext(context.Background())
 }
@@ -75,6 +75,30 @@ func (o ProviderOutput) ToProviderOutputWithContext(ctx context.Context) Provide
 	return o
 }
 
+// URN is the URN of the resource.
+func (o ProviderOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Provider) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o ProviderOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Provider) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(ProviderOutput{})
 }
@@ -17,6 +17,14 @@ const (
 	ContainerBrightnessOne          = ContainerBrightness(1)
 )
 
+// EnumValues returns the known values of ContainerBrightness.
+func (ContainerBrightness) EnumValues() []interface{} {
+	return []interface{}{
+		ContainerBrightnessZeroPointOne,
+		ContainerBrightnessOne,
+	}
+}
+
 func (ContainerBrightness) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.Float64)(nil)).Elem()
 }
@@ -46,6 +54,15 @@ const (
 	ContainerColorYellow = ContainerColor("yellow")
 )
 
+// EnumValues returns the known values of ContainerColor.
+func (ContainerColor) EnumValues() []interface{} {
+	return []interface{}{
+		ContainerColorRed,
+		ContainerColorBlue,
+		ContainerColorYellow,
+	}
+}
+
 func (ContainerColor) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.String)(nil)).Elem()
 }
@@ -76,6 +93,15 @@ const (
 	ContainerSizeEightInch = ContainerSize(8)
 )
 
+// EnumValues returns the known values of ContainerSize.
+func (ContainerSize) EnumValues() []interface{} {
+	return []interface{}{
+		ContainerSizeFourInch,
+		ContainerSizeSixInch,
+		ContainerSizeEightInch,
+	}
+}
+
 func (ContainerSize) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.Int)(nil)).Elem()
 }
@@ -110,6 +110,30 @@ func (o NurseryOutput) ToNurseryOutputWithContext(ctx context.Context) NurseryOu
 	return o
 }
 
+// URN is the URN of the resource.
+func (o NurseryOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Nursery) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o NurseryOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Nursery) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(NurseryOutput{})
 }
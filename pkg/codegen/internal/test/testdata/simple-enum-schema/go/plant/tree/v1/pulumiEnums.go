@@ -17,6 +17,14 @@ const (
 	DiameterTwelveinch = Diameter(12)
 )
 
+// EnumValues returns the known values of Diameter.
+func (Diameter) EnumValues() []interface{} {
+	return []interface{}{
+		DiameterSixinch,
+		DiameterTwelveinch,
+	}
+}
+
 func (Diameter) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.Float64)(nil)).Elem()
 }
@@ -44,6 +52,14 @@ const (
 	Farm_Plants_R_Us          = Farm("Plants'R'Us")
 )
 
+// EnumValues returns the known values of Farm.
+func (Farm) EnumValues() []interface{} {
+	return []interface{}{
+		Farm_Pulumi_Planters_Inc_,
+		Farm_Plants_R_Us,
+	}
+}
+
 func (Farm) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.String)(nil)).Elem()
 }
@@ -76,6 +92,15 @@ const (
 	RubberTreeVarietyTineke = RubberTreeVariety("Tineke")
 )
 
+// EnumValues returns the known values of RubberTreeVariety.
+func (RubberTreeVariety) EnumValues() []interface{} {
+	return []interface{}{
+		RubberTreeVarietyBurgundy,
+		RubberTreeVarietyRuby,
+		RubberTreeVarietyTineke,
+	}
+}
+
 func (RubberTreeVariety) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.String)(nil)).Elem()
 }
@@ -149,6 +174,15 @@ const (
 	TreeSizeLarge  = TreeSize("large")
 )
 
+// EnumValues returns the known values of TreeSize.
+func (TreeSize) EnumValues() []interface{} {
+	return []interface{}{
+		TreeSizeSmall,
+		TreeSizeMedium,
+		TreeSizeLarge,
+	}
+}
+
 func (TreeSize) ElementType() reflect.Type {
 	return reflect.TypeOf((*pulumi.String)(nil)).Elem()
 }
@@ -139,6 +139,30 @@ func (o RubberTreeOutput) ToRubberTreeOutputWithContext(ctx context.Context) Rub
 	return o
 }
 
+// URN is the URN of the resource.
+func (o RubberTreeOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *RubberTree) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o RubberTreeOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *RubberTree) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(RubberTreeOutput{})
 }
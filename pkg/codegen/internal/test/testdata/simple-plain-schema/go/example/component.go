@@ -98,6 +98,18 @@ func (o ComponentOutput) ToComponentOutputWithContext(ctx context.Context) Compo
 	return o
 }
 
+// URN is the URN of the resource.
+func (o ComponentOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Component) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(ComponentOutput{})
 }
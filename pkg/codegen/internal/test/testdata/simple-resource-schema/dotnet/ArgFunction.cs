@@ -9,8 +9,14 @@ using Pulumi.Serialization;
 
 namespace Pulumi.Example
 {
+    [Obsolete(@"Use the related resource's outputs instead.")]
     public static class ArgFunction
     {
+        /// <summary>
+        /// Looks up the resource that is related to the given resource.
+        /// 
+        /// The lookup fails if no resource is related to `arg1`.
+        /// </summary>
         public static Task<ArgFunctionResult> InvokeAsync(ArgFunctionArgs? args = null, InvokeOptions? options = null)
             => Pulumi.Deployment.Instance.InvokeAsync<ArgFunctionResult>("example::argFunction", args ?? new ArgFunctionArgs(), options.WithVersion());
     }
@@ -18,6 +24,9 @@ namespace Pulumi.Example
 
     public sealed class ArgFunctionArgs : Pulumi.InvokeArgs
     {
+        /// <summary>
+        /// The resource to look up the related resource of.
+        /// </summary>
         [Input("arg1")]
         public Pulumi.Example.Resource? Arg1 { get; set; }
 
@@ -30,6 +39,9 @@ namespace Pulumi.Example
     [OutputType]
     public sealed class ArgFunctionResult
     {
+        /// <summary>
+        /// The related resource.
+        /// </summary>
         public readonly Pulumi.Example.Resource? Result;
 
         [OutputConstructor]
@@ -4,9 +4,17 @@
 package example
 
 import (
+	"context"
+	"reflect"
+
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// Looks up the resource that is related to the given resource.
+//
+// The lookup fails if no resource is related to `arg1`.
+//
+// Deprecated: Use the related resource's outputs instead.
 func ArgFunction(ctx *pulumi.Context, args *ArgFunctionArgs, opts ...pulumi.InvokeOption) (*ArgFunctionResult, error) {
 	var rv ArgFunctionResult
 	err := ctx.Invoke("example::argFunction", args, &rv, opts...)
@@ -17,9 +25,60 @@ func ArgFunction(ctx *pulumi.Context, args *ArgFunctionArgs, opts ...pulumi.Invo
 }
 
 type ArgFunctionArgs struct {
+	// The resource to look up the related resource of.
 	Arg1 *Resource `pulumi:"arg1"`
 }
 
 type ArgFunctionResult struct {
+	// The related resource.
+	//
+	// Deprecated: Use `related` instead.
 	Result *Resource `pulumi:"result"`
 }
+
+// Deprecated: Use the related resource's outputs instead.
+func ArgFunctionOutput(ctx *pulumi.Context, args ArgFunctionOutputArgs, opts ...pulumi.InvokeOption) ArgFunctionResultOutput {
+	return pulumi.ToOutputWithContext(context.Background(), args).
+		ApplyT(func(v interface{}) (ArgFunctionResult, error) {
+			args := v.(ArgFunctionArgs)
+			r, err := ArgFunction(ctx, &args, opts...)
+			if err != nil {
+				return ArgFunctionResult{}, err
+			}
+			return *r, nil
+		}).(ArgFunctionResultOutput)
+}
+
+type ArgFunctionOutputArgs struct {
+	// The resource to look up the related resource of.
+	Arg1 ResourceInput `pulumi:"arg1"`
+}
+
+func (ArgFunctionOutputArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*ArgFunctionArgs)(nil)).Elem()
+}
+
+type ArgFunctionResultOutput struct{ *pulumi.OutputState }
+
+func (ArgFunctionResultOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ArgFunctionResult)(nil)).Elem()
+}
+
+func (o ArgFunctionResultOutput) ToArgFunctionResultOutput() ArgFunctionResultOutput {
+	return o
+}
+
+func (o ArgFunctionResultOutput) ToArgFunctionResultOutputWithContext(ctx context.Context) ArgFunctionResultOutput {
+	return o
+}
+
+// The related resource.
+//
+// Deprecated: Use `related` instead.
+func (o ArgFunctionResultOutput) Result() ResourceOutput {
+	return o.ApplyT(func(v ArgFunctionResult) *Resource { return v.Result }).(ResourceOutput)
+}
+
+func init() {
+	pulumi.RegisterOutputType(ArgFunctionResultOutput{})
+}
@@ -79,6 +79,18 @@ func (o OtherResourceOutput) ToOtherResourceOutputWithContext(ctx context.Contex
 	return o
 }
 
+// URN is the URN of the resource.
+func (o OtherResourceOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *OtherResource) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(OtherResourceOutput{})
 }
@@ -104,6 +104,30 @@ func (o ResourceOutput) ToResourceOutputWithContext(ctx context.Context) Resourc
 	return o
 }
 
+// URN is the URN of the resource.
+func (o ResourceOutput) URN() pulumi.URNOutput {
+	return o.ApplyT(func(v *Resource) pulumi.URNOutput {
+		if v == nil {
+			return pulumi.URN("").ToURNOutput()
+		}
+		return v.URN()
+	}).ApplyT(func(v interface{}) pulumi.URN {
+		return v.(pulumi.URN)
+	}).(pulumi.URNOutput)
+}
+
+// ID is the provider-assigned unique ID of the resource.
+func (o ResourceOutput) ID() pulumi.IDOutput {
+	return o.ApplyT(func(v *Resource) pulumi.IDOutput {
+		if v == nil {
+			return pulumi.ID("").ToIDOutput()
+		}
+		return v.ID()
+	}).ApplyT(func(v interface{}) pulumi.ID {
+		return v.(pulumi.ID)
+	}).(pulumi.IDOutput)
+}
+
 func init() {
 	pulumi.RegisterOutputType(ResourceOutput{})
 }
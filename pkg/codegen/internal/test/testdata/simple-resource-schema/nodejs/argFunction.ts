@@ -7,7 +7,14 @@ import * as utilities from "./utilities";
 
 import {Resource} from "./index";
 
+/**
+ * Looks up the resource that is related to the given resource.
+ *
+ * The lookup fails if no resource is related to `arg1`.
+ */
+/** @deprecated Use the related resource's outputs instead. */
 export function argFunction(args?: ArgFunctionArgs, opts?: pulumi.InvokeOptions): Promise<ArgFunctionResult> {
+    pulumi.log.warn("argFunction is deprecated: Use the related resource's outputs instead.")
     args = args || {};
     if (!opts) {
         opts = {}
@@ -22,9 +29,17 @@ export function argFunction(args?: ArgFunctionArgs, opts?: pulumi.InvokeOptions)
 }
 
 export interface ArgFunctionArgs {
+    /**
+     * The resource to look up the related resource of.
+     */
     readonly arg1?: Resource;
 }
 
 export interface ArgFunctionResult {
+    /**
+     * The related resource.
+     *
+     * @deprecated Use `related` instead.
+     */
     readonly result?: Resource;
 }
@@ -15,16 +15,25 @@ __all__ = [
     'arg_function',
 ]
 
+warnings.warn("""Use the related resource's outputs instead.""", DeprecationWarning)
+
 @pulumi.output_type
 class ArgFunctionResult:
     def __init__(__self__, result=None):
         if result and not isinstance(result, Resource):
             raise TypeError("Expected argument 'result' to be a Resource")
+        if result is not None:
+            warnings.warn("""Use `related` instead.""", DeprecationWarning)
+            pulumi.log.warn("""result is deprecated: Use `related` instead.""")
+
         pulumi.set(__self__, "result", result)
 
     @property
     @pulumi.getter
     def result(self) -> Optional['Resource']:
+        """
+        The related resource.
+        """
         return pulumi.get(self, "result")
 
 
@@ -40,8 +49,14 @@ class AwaitableArgFunctionResult(ArgFunctionResult):
 def arg_function(arg1: Optional['Resource'] = None,
                  opts: Optional[pulumi.InvokeOptions] = None) -> AwaitableArgFunctionResult:
     """
-    Use this data source to access information about an existing resource.
+    Looks up the resource that is related to the given resource.
+
+    The lookup fails if no resource is related to `arg1`.
+
+
+    :param 'Resource' arg1: The resource to look up the related resource of.
     """
+    pulumi.log.warn("""arg_function is deprecated: Use the related resource's outputs instead.""")
     __args__ = dict()
     __args__['arg1'] = arg1
     if opts is None:
@@ -3,6 +3,7 @@ package lifecycletest
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -19,6 +20,8 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 )
 
 type testResource struct {
@@ -722,3 +725,132 @@ func TestProviderInheritanceGolangLifecycle(t *testing.T) {
 	}
 	p.Run(t, nil)
 }
+
+// goComponentProvider is a test provider that constructs components using the Go SDK.
+type goComponentProvider struct {
+	deploytest.Provider
+
+	construct provider.ConstructFunc
+}
+
+func (prov *goComponentProvider) Construct(info plugin.ConstructInfo, typ tokens.Type, name tokens.QName,
+	parent resource.URN, inputs resource.PropertyMap, options plugin.ConstructOptions) (plugin.ConstructResult, error) {
+
+	aliases := make([]string, len(options.Aliases))
+	for i, alias := range options.Aliases {
+		aliases[i] = string(alias)
+	}
+
+	resp, err := provider.Construct(context.Background(), &pulumirpc.ConstructRequest{
+		Project:         info.Project,
+		Stack:           info.Stack,
+		DryRun:          info.DryRun,
+		Parallel:        int32(info.Parallel),
+		MonitorEndpoint: info.MonitorAddress,
+		Type:            string(typ),
+		Name:            string(name),
+		Parent:          string(parent),
+		Aliases:         aliases,
+	}, nil, prov.construct)
+	if err != nil {
+		return plugin.ConstructResult{}, err
+	}
+	return plugin.ConstructResult{URN: resource.URN(resp.GetUrn())}, nil
+}
+
+type testComponent struct {
+	pulumi.ResourceState
+}
+
+func TestComponentTypeAliasGolangLifecycle(t *testing.T) {
+	var ids int
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			construct := func(ctx *pulumi.Context, typ, name string, inputs provider.ConstructInputs,
+				options pulumi.ResourceOption) (*provider.ConstructResult, error) {
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, err
+				}
+
+				var child testResource
+				err := ctx.RegisterResource("pkgA:m:typB", "child", &testResourceInputs{
+					Foo: pulumi.String("bar"),
+				}, &child, pulumi.Parent(&component))
+				if err != nil {
+					return nil, err
+				}
+
+				if err := ctx.RegisterResourceOutputs(&component, pulumi.Map{}); err != nil {
+					return nil, err
+				}
+				return provider.NewConstructResult(&component)
+			}
+
+			return &goComponentProvider{
+				Provider: deploytest.Provider{
+					CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+						preview bool) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+						ids++
+						return resource.ID(fmt.Sprintf("created-id-%d", ids)), news, resource.StatusOK, nil
+					},
+				},
+				construct: construct,
+			}, nil
+		}),
+	}
+
+	componentType := "pkgA:index:OldComponent"
+	var aliases []resource.URN
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource(tokens.Type(componentType), "comp", false, deploytest.ResourceOptions{
+			Remote:  true,
+			Aliases: aliases,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+	}
+	project := p.GetProject()
+
+	childID := func(snap *deploy.Snapshot) resource.ID {
+		for _, res := range snap.Resources {
+			if res.URN.Name() == "child" {
+				return res.ID
+			}
+		}
+		return ""
+	}
+
+	snap, res := TestOp(Update).Run(project, p.GetTarget(nil), p.Options, false, p.BackendClient, nil)
+	assert.Nil(t, res)
+	originalID := childID(snap)
+	assert.NotEqual(t, resource.ID(""), originalID)
+
+	// Rename the component's type, aliasing it to its old URN. Neither the component nor its child should be replaced.
+	componentType = "pkgA:index:Component"
+	aliases = []resource.URN{"urn:pulumi:test::test::pkgA:index:OldComponent::comp"}
+	snap, res = TestOp(Update).Run(project, p.GetTarget(snap), p.Options, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, entries JournalEntries, _ []Event,
+			res result.Result) result.Result {
+
+			for _, entry := range entries {
+				if entry.Step.Type() == "pulumi:providers:pkgA" {
+					continue
+				}
+				assert.Equal(t, deploy.OpSame, entry.Step.Op(), "unexpected %v of %v", entry.Step.Op(), entry.Step.URN())
+			}
+			return res
+		})
+	assert.Nil(t, res)
+	assert.Equal(t, originalID, childID(snap))
+	for _, res := range snap.Resources {
+		assert.NotContains(t, string(res.URN), "OldComponent")
+	}
+}
@@ -1446,6 +1446,50 @@ func TestCustomTimeouts(t *testing.T) {
 	assert.Equal(t, snap.Resources[1].CustomTimeouts.Delete, float64(60))
 }
 
+func TestResourceTimestamps(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+
+	// Creating the resource records both timestamps.
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	created := snap.Resources[1].Created
+	if assert.NotNil(t, created) && assert.NotNil(t, snap.Resources[1].Modified) {
+		assert.Equal(t, *created, *snap.Resources[1].Modified)
+	}
+
+	// An update that does not change the resource retains its timestamps.
+	snap = p.Run(t, snap)
+	assert.Equal(t, created, snap.Resources[1].Created)
+	assert.Equal(t, created, snap.Resources[1].Modified)
+
+	// An update that changes the resource only bumps its modification time.
+	inputs = resource.PropertyMap{"foo": resource.NewStringProperty("baz")}
+	snap = p.Run(t, snap)
+	assert.Equal(t, created, snap.Resources[1].Created)
+	if assert.NotNil(t, snap.Resources[1].Modified) {
+		assert.False(t, snap.Resources[1].Modified.Before(*created))
+	}
+}
+
 func TestProviderDiffMissingOldOutputs(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
@@ -1978,6 +2022,71 @@ func TestSingleComponentGetResourceDefaultProviderLifecycle(t *testing.T) {
 	p.Run(t, nil)
 }
 
+// constructInfoProvider is a test provider that reports the construct info passed to each call to Construct.
+type constructInfoProvider struct {
+	deploytest.Provider
+
+	onConstruct func(info plugin.ConstructInfo)
+}
+
+func (prov *constructInfoProvider) Construct(info plugin.ConstructInfo, typ tokens.Type, name tokens.QName,
+	parent resource.URN, inputs resource.PropertyMap, options plugin.ConstructOptions) (plugin.ConstructResult, error) {
+
+	prov.onConstruct(info)
+	return prov.Provider.Construct(info, typ, name, parent, inputs, options)
+}
+
+func TestSingleComponentRefresh(t *testing.T) {
+	var refreshes []bool
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			construct := func(monitor *deploytest.ResourceMonitor, typ, name string, parent resource.URN,
+				inputs resource.PropertyMap, options plugin.ConstructOptions) (plugin.ConstructResult, error) {
+
+				urn, _, _, err := monitor.RegisterResource(tokens.Type(typ), name, false, deploytest.ResourceOptions{
+					Parent: parent,
+				})
+				assert.NoError(t, err)
+
+				return plugin.ConstructResult{URN: urn}, nil
+			}
+
+			return &constructInfoProvider{
+				Provider: deploytest.Provider{ConstructF: construct},
+				onConstruct: func(info plugin.ConstructInfo) {
+					refreshes = append(refreshes, info.Refresh)
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", false, deploytest.ResourceOptions{
+			Remote: true,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+	}
+
+	// An ordinary update should not report a refresh to the component provider.
+	project := p.GetProject()
+	snap, res := TestOp(Update).Run(project, p.GetTarget(nil), p.Options, false, p.BackendClient, nil)
+	assert.Nil(t, res)
+
+	// An update that refreshes first should.
+	p.Options.Refresh = true
+	_, res = TestOp(Update).Run(project, p.GetTarget(snap), p.Options, false, p.BackendClient, nil)
+	assert.Nil(t, res)
+
+	assert.Equal(t, []bool{false, true}, refreshes)
+}
+
 func TestConfigSecrets(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
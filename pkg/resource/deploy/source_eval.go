@@ -448,6 +448,7 @@ func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *reg
 		Stack:          string(src.runinfo.Target.Name),
 		Config:         config,
 		DryRun:         src.dryRun,
+		Refresh:        opts.Refresh,
 		Parallel:       opts.Parallel,
 		MonitorAddress: fmt.Sprintf("127.0.0.1:%d", port),
 	}
@@ -892,16 +893,25 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 
 		// Invoke the provider's Construct RPC method.
 		options := plugin.ConstructOptions{
-			Aliases:              aliases,
-			Protect:              protect,
-			PropertyDependencies: propertyDependencies,
-			Providers:            providerRefs,
+			Aliases:                 aliases,
+			Protect:                 protect,
+			PropertyDependencies:    propertyDependencies,
+			Providers:               providerRefs,
+			AdditionalSecretOutputs: additionalSecretOutputs,
+			DeleteBeforeReplace:     deleteBeforeReplaceValue,
+		}
+		if timeouts.IsNotEmpty() {
+			options.CustomTimeouts = &timeouts
 		}
 		constructResult, err := provider.Construct(rm.constructInfo, t, name, parent, props, options)
 		if err != nil {
 			return nil, err
 		}
-		result = &RegisterResult{State: &resource.State{URN: constructResult.URN, Outputs: constructResult.Outputs}}
+		result = &RegisterResult{State: &resource.State{
+			URN:     constructResult.URN,
+			ID:      constructResult.ID,
+			Outputs: constructResult.Outputs,
+		}}
 
 		outputDeps = map[string]*pulumirpc.RegisterResourceResponse_PropertyDependencies{}
 		for k, deps := range constructResult.OutputDependencies {
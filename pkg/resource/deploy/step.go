@@ -17,6 +17,7 @@ package deploy
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -121,9 +122,11 @@ func (s *SameStep) Res() *resource.State    { return s.new }
 func (s *SameStep) Logical() bool           { return true }
 
 func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Retain the ID, and outputs:
+	// Retain the ID, outputs, and timestamps:
 	s.new.ID = s.old.ID
 	s.new.Outputs = s.old.Outputs
+	s.new.Created = s.old.Created
+	s.new.Modified = s.old.Modified
 	complete := func() { s.reg.Done(&RegisterResult{State: s.new}) }
 	return resource.StatusOK, complete, nil
 }
@@ -240,6 +243,13 @@ func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 		s.new.Outputs = outs
 	}
 
+	// Previews do not create anything, so they do not record timestamps.
+	if !preview {
+		now := time.Now().UTC()
+		s.new.Created = &now
+		s.new.Modified = &now
+	}
+
 	// Mark the old resource as pending deletion if necessary.
 	if s.replacing && s.pendingDelete {
 		s.old.Delete = true
@@ -435,6 +445,15 @@ func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 	// Always propagate the ID, even in previews and refreshes.
 	s.new.ID = s.old.ID
 
+	// The creation time is always carried over, but previews do not modify anything, so they leave the old
+	// modification time in place.
+	s.new.Created = s.old.Created
+	s.new.Modified = s.old.Modified
+	if !preview {
+		now := time.Now().UTC()
+		s.new.Modified = &now
+	}
+
 	var resourceError error
 	resourceStatus := resource.StatusOK
 	if s.new.Custom {
@@ -758,7 +777,7 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 		s.new = resource.NewState(s.old.Type, s.old.URN, s.old.Custom, s.old.Delete, resourceID, inputs, outputs,
 			s.old.Parent, s.old.Protect, s.old.External, s.old.Dependencies, initErrors, s.old.Provider,
 			s.old.PropertyDependencies, s.old.PendingReplacement, s.old.AdditionalSecretOutputs, s.old.Aliases,
-			&s.old.CustomTimeouts, s.old.ImportID)
+			&s.old.CustomTimeouts, s.old.ImportID, s.old.Created, s.old.Modified)
 	} else {
 		s.new = nil
 	}
@@ -895,12 +914,18 @@ func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 	}
 	s.new.Outputs = read.Outputs
 
+	if !preview {
+		now := time.Now().UTC()
+		s.new.Created = &now
+		s.new.Modified = &now
+	}
+
 	// Magic up an old state so the frontend can display a proper diff. This state is the output of the just-executed
 	// `Read` combined with the resource identity and metadata from the desired state. This ensures that the only
 	// differences between the old and new states are between the inputs and outputs.
 	s.old = resource.NewState(s.new.Type, s.new.URN, s.new.Custom, false, s.new.ID, read.Inputs, read.Outputs,
 		s.new.Parent, s.new.Protect, false, s.new.Dependencies, s.new.InitErrors, s.new.Provider,
-		s.new.PropertyDependencies, false, nil, nil, &s.new.CustomTimeouts, s.new.ImportID)
+		s.new.PropertyDependencies, false, nil, nil, &s.new.CustomTimeouts, s.new.ImportID, nil, nil)
 
 	// If this step came from an import deployment, we need to fetch any required inputs from the state.
 	if s.planned {
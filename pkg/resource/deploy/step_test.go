@@ -0,0 +1,120 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+func newComponentState(modified *time.Time) *resource.State {
+	return &resource.State{
+		Type:     "pkgA:m:typA",
+		URN:      "urn:pulumi:stack::project::pkgA:m:typA::resA",
+		Inputs:   resource.PropertyMap{},
+		Created:  modified,
+		Modified: modified,
+	}
+}
+
+func TestCreateStepTimestamps(t *testing.T) {
+	// Previews do not record timestamps.
+	new := newComponentState(nil)
+	_, _, err := NewCreateStep(nil, &registerResourceEvent{}, new).Apply(true)
+	assert.NoError(t, err)
+	assert.Nil(t, new.Created)
+	assert.Nil(t, new.Modified)
+
+	// Creates record the creation time as both timestamps.
+	new = newComponentState(nil)
+	_, _, err = NewCreateStep(nil, &registerResourceEvent{}, new).Apply(false)
+	assert.NoError(t, err)
+	if assert.NotNil(t, new.Created) && assert.NotNil(t, new.Modified) {
+		assert.Equal(t, *new.Created, *new.Modified)
+	}
+}
+
+func TestUpdateStepTimestamps(t *testing.T) {
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := newComponentState(&created)
+
+	// Previews keep both of the old timestamps.
+	new := newComponentState(nil)
+	_, _, err := NewUpdateStep(nil, &registerResourceEvent{}, old, new, nil, nil, nil, nil).Apply(true)
+	assert.NoError(t, err)
+	assert.Equal(t, &created, new.Created)
+	assert.Equal(t, &created, new.Modified)
+
+	// Updates keep the creation time and bump the modification time.
+	new = newComponentState(nil)
+	_, _, err = NewUpdateStep(nil, &registerResourceEvent{}, old, new, nil, nil, nil, nil).Apply(false)
+	assert.NoError(t, err)
+	assert.Equal(t, &created, new.Created)
+	if assert.NotNil(t, new.Modified) {
+		assert.True(t, new.Modified.After(created))
+	}
+}
+
+func TestImportStepTimestamps(t *testing.T) {
+	loader := deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+		return &deploytest.Provider{}, nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, nil, loader)
+
+	provider := &resource.State{
+		Type:   "pulumi:providers:pkgA",
+		URN:    "urn:pulumi:stack::project::pulumi:providers:pkgA::default",
+		Custom: true,
+		ID:     "id",
+		Inputs: resource.PropertyMap{},
+	}
+	registry, err := providers.NewRegistry(host, []*resource.State{provider}, false, nil)
+	assert.NoError(t, err)
+	deployment := &Deployment{providers: registry}
+
+	newImportState := func() *resource.State {
+		return &resource.State{
+			Type:     "pkgA:m:typA",
+			URN:      "urn:pulumi:stack::project::pkgA:m:typA::resA",
+			Custom:   true,
+			ID:       "resA",
+			Inputs:   resource.PropertyMap{},
+			Provider: string(provider.URN) + "::" + string(provider.ID),
+		}
+	}
+
+	// Previews do not record timestamps.
+	new := newImportState()
+	_, _, err = NewImportStep(deployment, &registerResourceEvent{}, new, nil).Apply(true)
+	assert.NoError(t, err)
+	assert.Nil(t, new.Created)
+	assert.Nil(t, new.Modified)
+
+	// Imports record the import time as both timestamps.
+	new = newImportState()
+	_, _, err = NewImportStep(deployment, &registerResourceEvent{}, new, nil).Apply(false)
+	assert.NoError(t, err)
+	if assert.NotNil(t, new.Created) && assert.NotNil(t, new.Modified) {
+		assert.Equal(t, *new.Created, *new.Modified)
+	}
+}
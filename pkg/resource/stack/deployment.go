@@ -15,18 +15,27 @@
 package stack
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype/migrate"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -43,6 +52,10 @@ const (
 	// is not known.) This allows us to persist engine events and resource states that
 	// indicate a value will changed... but is unknown what it will change to.
 	computedValuePlaceholder = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+	// secretEnvelopeVersionCurrent is the version of the envelope format that we emit for secret values. Envelopes
+	// that do not carry a version are treated as version 1.
+	secretEnvelopeVersionCurrent = 1
 )
 
 var (
@@ -57,8 +70,182 @@ var (
 
 // SerializeDeployment serializes an entire snapshot as a deploy record.
 func SerializeDeployment(snap *deploy.Snapshot, sm secrets.Manager, showSecrets bool) (*apitype.DeploymentV3, error) {
+	return SerializeDeploymentWithOptions(snap, sm, SerializeOptions{ShowSecrets: showSecrets})
+}
+
+// SerializeOptions controls the behavior of SerializeDeploymentWithOptions.
+type SerializeOptions struct {
+	// ShowSecrets causes secret values to be serialized in plaintext rather than encrypted.
+	ShowSecrets bool
+	// ResourceTransform, if non-nil, is called with each serialized resource before it is added to the deployment.
+	// It returns the resource to add in its place, e.g. with additional metadata or with fields redacted. If it
+	// returns an error, serialization fails. Resources that are part of pending operations are not transformed.
+	ResourceTransform func(res apitype.ResourceV3) (apitype.ResourceV3, error)
+	// ExactIntegers causes integral numbers to be serialized as JSON integers (json.Number values) so that large values,
+	// e.g. numeric IDs, keep their exact digits rather than being encoded with an exponent. By default, numbers are
+	// serialized as float64 values.
+	ExactIntegers bool
+}
+
+// SerializeDeploymentWithOptions serializes an entire snapshot as a deploy record using the given options.
+func SerializeDeploymentWithOptions(snap *deploy.Snapshot, sm secrets.Manager,
+	opts SerializeOptions) (*apitype.DeploymentV3, error) {
+	contract.Require(snap != nil, "snap")
+
+	s, err := newDeploymentSerializer(snap, sm, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serialize all vertices and only include a vertex section if non-empty.
+	var resources []apitype.ResourceV3
+	for _, res := range snap.Resources {
+		sres, err := s.resource(res)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, sres)
+	}
+
+	var operations []apitype.OperationV2
+	for _, op := range snap.PendingOperations {
+		sop, err := serializeOperation(op, s.enc, opts)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, sop)
+	}
+
+	return &apitype.DeploymentV3{
+		Manifest:          s.manifest,
+		Resources:         resources,
+		SecretsProviders:  s.secretsProvider,
+		PendingOperations: operations,
+	}, nil
+}
+
+// SerializeDeploymentTo serializes an entire snapshot as a deploy record using the given options and writes its JSON
+// encoding to w. The output is identical to the JSON encoding of the result of SerializeDeploymentWithOptions, but
+// the resources are serialized and written one at a time, so only a single serialized resource is held in memory at
+// once. This makes SerializeDeploymentTo suitable for very large snapshots.
+func SerializeDeploymentTo(w io.Writer, snap *deploy.Snapshot, sm secrets.Manager, opts SerializeOptions) error {
+	contract.Require(w != nil, "w")
 	contract.Require(snap != nil, "snap")
 
+	s, err := newDeploymentSerializer(snap, sm, opts)
+	if err != nil {
+		return err
+	}
+
+	// write writes the JSON encoding of v to w, preceded by the given prefix.
+	write := func(prefix string, v interface{}) error {
+		if _, err := io.WriteString(w, prefix); err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+
+	// The fields are written in the order of apitype.DeploymentV3's fields, and empty sections are omitted.
+	if err := write(`{"manifest":`, s.manifest); err != nil {
+		return err
+	}
+	if s.secretsProvider != nil {
+		if err := write(`,"secrets_providers":`, s.secretsProvider); err != nil {
+			return err
+		}
+	}
+	for i, res := range snap.Resources {
+		sres, err := s.resource(res)
+		if err != nil {
+			return err
+		}
+		prefix := ","
+		if i == 0 {
+			prefix = `,"resources":[`
+		}
+		if err := write(prefix, sres); err != nil {
+			return err
+		}
+	}
+	if len(snap.Resources) != 0 {
+		if _, err := io.WriteString(w, "]"); err != nil {
+			return err
+		}
+	}
+	for i, op := range snap.PendingOperations {
+		sop, err := serializeOperation(op, s.enc, opts)
+		if err != nil {
+			return err
+		}
+		prefix := ","
+		if i == 0 {
+			prefix = `,"pending_operations":[`
+		}
+		if err := write(prefix, sop); err != nil {
+			return err
+		}
+	}
+	if len(snap.PendingOperations) != 0 {
+		if _, err := io.WriteString(w, "]"); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "}")
+	return err
+}
+
+// ExportOptions controls the formatting of ExportDeployment.
+type ExportOptions struct {
+	// Indent is the string used to indent each level of the JSON output. If empty, four spaces are used.
+	Indent string
+}
+
+// ExportDeployment writes the JSON encoding of a deployment to w in a deterministic format that is suitable for
+// committing to source control. The keys of every object, including those of struct-typed values, are sorted, each
+// level is indented with opts.Indent, and the output ends with a newline. Two equivalent deployments therefore always
+// produce identical bytes.
+func ExportDeployment(w io.Writer, d *apitype.DeploymentV3, opts ExportOptions) error {
+	contract.Require(w != nil, "w")
+	contract.Require(d != nil, "d")
+
+	// Round-trip the deployment through an untyped value so that the fields of structs are sorted along with the keys
+	// of maps. Numbers are decoded as json.Numbers so that they are written back exactly as they were encoded.
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	indent := opts.Indent
+	if indent == "" {
+		indent = "    "
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", indent)
+	return enc.Encode(v)
+}
+
+// deploymentSerializer holds the state that is shared by the serialization of each part of a snapshot.
+type deploymentSerializer struct {
+	opts            SerializeOptions
+	enc             config.Encrypter
+	manifest        apitype.ManifestV1
+	secretsProvider *apitype.SecretsProvidersV1
+}
+
+func newDeploymentSerializer(snap *deploy.Snapshot, sm secrets.Manager,
+	opts SerializeOptions) (*deploymentSerializer, error) {
+
 	// Capture the version information into a manifest.
 	manifest := apitype.ManifestV1{
 		Time:    snap.Manifest.Time,
@@ -94,25 +281,6 @@ func SerializeDeployment(snap *deploy.Snapshot, sm secrets.Manager, showSecrets
 		enc = config.NewPanicCrypter()
 	}
 
-	// Serialize all vertices and only include a vertex section if non-empty.
-	var resources []apitype.ResourceV3
-	for _, res := range snap.Resources {
-		sres, err := SerializeResource(res, enc, showSecrets)
-		if err != nil {
-			return nil, errors.Wrap(err, "serializing resources")
-		}
-		resources = append(resources, sres)
-	}
-
-	var operations []apitype.OperationV2
-	for _, op := range snap.PendingOperations {
-		sop, err := SerializeOperation(op, enc, showSecrets)
-		if err != nil {
-			return nil, err
-		}
-		operations = append(operations, sop)
-	}
-
 	var secretsProvider *apitype.SecretsProvidersV1
 	if sm != nil {
 		secretsProvider = &apitype.SecretsProvidersV1{
@@ -127,19 +295,58 @@ func SerializeDeployment(snap *deploy.Snapshot, sm secrets.Manager, showSecrets
 		}
 	}
 
-	return &apitype.DeploymentV3{
-		Manifest:          manifest,
-		Resources:         resources,
-		SecretsProviders:  secretsProvider,
-		PendingOperations: operations,
+	return &deploymentSerializer{
+		opts:            opts,
+		enc:             enc,
+		manifest:        manifest,
+		secretsProvider: secretsProvider,
 	}, nil
 }
 
+// resource serializes a single resource and applies the serializer's resource transform, if any.
+func (s *deploymentSerializer) resource(res *resource.State) (apitype.ResourceV3, error) {
+	sres, err := serializeResource(res, s.enc, s.opts)
+	if err != nil {
+		return apitype.ResourceV3{}, errors.Wrap(err, "serializing resources")
+	}
+	if s.opts.ResourceTransform != nil {
+		if sres, err = s.opts.ResourceTransform(sres); err != nil {
+			return apitype.ResourceV3{}, errors.Wrapf(err, "transforming resource %v", res.URN)
+		}
+	}
+	return sres, nil
+}
+
 // DeserializeUntypedDeployment deserializes an untyped deployment and produces a `deploy.Snapshot`
 // from it. DeserializeDeployment will return an error if the untyped deployment's version is
 // not within the range `DeploymentSchemaVersionCurrent` and `DeploymentSchemaVersionOldestSupported`.
 func DeserializeUntypedDeployment(
 	deployment *apitype.UntypedDeployment, secretsProv SecretsProvider) (*deploy.Snapshot, error) {
+	return DeserializeUntypedDeploymentWithOptions(deployment, secretsProv, DeserializeOptions{})
+}
+
+// DeserializeOptions controls the behavior of DeserializeUntypedDeploymentWithOptions.
+type DeserializeOptions struct {
+	// Strict causes deserialization to fail if the deployment contains fields (e.g. resource fields written by a
+	// newer version of the engine) that are not recognized by this version of Pulumi. By default, such fields are
+	// ignored.
+	Strict bool
+	// AllErrors causes deserialization to report every resource and pending operation that cannot be deserialized,
+	// rather than stopping at the first one. The errors are combined into a single error, each identifying the URN of
+	// the affected resource. By default, deserialization stops at the first error.
+	AllErrors bool
+	// Validate causes each resource's URN, type, and provider reference to be checked for well-formedness, and the
+	// deserialized snapshot to be checked with ValidateSnapshot, so that a checkpoint whose resources are malformed or
+	// refer to missing or misplaced parents, providers, or dependencies is rejected when it is loaded rather than when
+	// the engine first trips over it. Every problem found by ValidateSnapshot is reported. Deployments whose references
+	// form a cycle are always rejected, regardless of this option.
+	Validate bool
+}
+
+// DeserializeUntypedDeploymentWithOptions is like DeserializeUntypedDeployment, but accepts options that control
+// how the deployment is decoded.
+func DeserializeUntypedDeploymentWithOptions(deployment *apitype.UntypedDeployment, secretsProv SecretsProvider,
+	opts DeserializeOptions) (*deploy.Snapshot, error) {
 
 	contract.Require(deployment != nil, "deployment")
 	switch {
@@ -153,30 +360,58 @@ func DeserializeUntypedDeployment(
 	switch deployment.Version {
 	case 1:
 		var v1deployment apitype.DeploymentV1
-		if err := json.Unmarshal([]byte(deployment.Deployment), &v1deployment); err != nil {
+		if err := unmarshalDeployment(deployment.Deployment, &v1deployment, opts.Strict); err != nil {
 			return nil, err
 		}
 		v2deployment := migrate.UpToDeploymentV2(v1deployment)
 		v3deployment = migrate.UpToDeploymentV3(v2deployment)
 	case 2:
 		var v2deployment apitype.DeploymentV2
-		if err := json.Unmarshal([]byte(deployment.Deployment), &v2deployment); err != nil {
+		if err := unmarshalDeployment(deployment.Deployment, &v2deployment, opts.Strict); err != nil {
 			return nil, err
 		}
 		v3deployment = migrate.UpToDeploymentV3(v2deployment)
 	case 3:
-		if err := json.Unmarshal([]byte(deployment.Deployment), &v3deployment); err != nil {
+		if err := unmarshalDeployment(deployment.Deployment, &v3deployment, opts.Strict); err != nil {
 			return nil, err
 		}
 	default:
 		contract.Failf("unrecognized version: %d", deployment.Version)
 	}
 
-	return DeserializeDeploymentV3(v3deployment, secretsProv)
+	return DeserializeDeploymentV3WithOptions(v3deployment, secretsProv, opts)
+}
+
+// unmarshalDeployment decodes the JSON-encoded deployment into v. If strict is true, unrecognized fields are an error.
+func unmarshalDeployment(data json.RawMessage, v interface{}, strict bool) error {
+	if !strict {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if strings.HasPrefix(err.Error(), "json: unknown field ") {
+			return errors.Errorf("deployment contains a field that is not recognized by this version of Pulumi: %v",
+				strings.TrimPrefix(err.Error(), "json: unknown field "))
+		}
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the end of the deployment")
+	}
+	return nil
 }
 
 // DeserializeDeploymentV3 deserializes a typed DeploymentV3 into a `deploy.Snapshot`.
 func DeserializeDeploymentV3(deployment apitype.DeploymentV3, secretsProv SecretsProvider) (*deploy.Snapshot, error) {
+	return DeserializeDeploymentV3WithOptions(deployment, secretsProv, DeserializeOptions{})
+}
+
+// DeserializeDeploymentV3WithOptions is like DeserializeDeploymentV3, but accepts options that control how the
+// deployment is deserialized. The Strict option has no effect, as the deployment has already been decoded.
+func DeserializeDeploymentV3WithOptions(deployment apitype.DeploymentV3, secretsProv SecretsProvider,
+	opts DeserializeOptions) (*deploy.Snapshot, error) {
 	// Unpack the versions.
 	manifest := deploy.Manifest{
 		Time:    deployment.Manifest.Time,
@@ -231,37 +466,89 @@ func DeserializeDeploymentV3(deployment apitype.DeploymentV3, secretsProv Secret
 		enc = e
 	}
 
-	// For every serialized resource vertex, create a ResourceDeployment out of it.
+	// Reject deployments whose resources refer to one another in a cycle. Such deployments can only be the result of
+	// corruption (e.g. a botched manual edit), and would otherwise cause later processing to hang or fail.
+	if cycle := referenceCycle(deployment.Resources); cycle != nil {
+		strs := make([]string, len(cycle))
+		for i, urn := range cycle {
+			strs[i] = string(urn)
+		}
+		return nil, errors.Errorf("the references between resources form a cycle: %v", strings.Join(strs, " -> "))
+	}
+
+	// For every serialized resource vertex, create a ResourceDeployment out of it. If all errors are requested, keep
+	// going after a failure so that every problem is reported at once.
+	var result error
+	fail := func(err error) bool {
+		result = multierror.Append(result, err)
+		return !opts.AllErrors
+	}
+
 	var resources []*resource.State
 	for _, res := range deployment.Resources {
+		if opts.Validate {
+			if err := validateResource(res); err != nil {
+				if fail(err) {
+					return nil, err
+				}
+				continue
+			}
+		}
 		desres, err := DeserializeResource(res, dec, enc)
 		if err != nil {
-			return nil, err
+			if fail(err) {
+				return nil, err
+			}
+			continue
 		}
 		resources = append(resources, desres)
 	}
 
 	var ops []resource.Operation
 	for _, op := range deployment.PendingOperations {
+		if opts.Validate {
+			if err := validateResource(op.Resource); err != nil {
+				if fail(errors.Wrapf(err, "deserializing pending operation on %s", op.Resource.URN)) {
+					return nil, err
+				}
+				continue
+			}
+		}
 		desop, err := DeserializeOperation(op, dec, enc)
 		if err != nil {
-			return nil, err
+			if fail(errors.Wrapf(err, "deserializing pending operation on %s", op.Resource.URN)) {
+				return nil, err
+			}
+			continue
 		}
 		ops = append(ops, desop)
 	}
 
-	return deploy.NewSnapshot(manifest, secretsManager, resources, ops), nil
+	if result != nil {
+		return nil, result
+	}
+	snap := deploy.NewSnapshot(manifest, secretsManager, resources, ops)
+	if opts.Validate {
+		if err := ValidateSnapshot(snap); err != nil {
+			return nil, errors.Wrap(err, "validating deployment")
+		}
+	}
+	return snap, nil
 }
 
 // SerializeResource turns a resource into a structure suitable for serialization.
 func SerializeResource(res *resource.State, enc config.Encrypter, showSecrets bool) (apitype.ResourceV3, error) {
+	return serializeResource(res, enc, SerializeOptions{ShowSecrets: showSecrets})
+}
+
+func serializeResource(res *resource.State, enc config.Encrypter, opts SerializeOptions) (apitype.ResourceV3, error) {
 	contract.Assert(res != nil)
 	contract.Assertf(string(res.URN) != "", "Unexpected empty resource resource.URN")
 
 	// Serialize all input and output properties recursively, and add them if non-empty.
 	var inputs map[string]interface{}
 	if inp := res.Inputs; inp != nil {
-		sinp, err := SerializeProperties(inp, enc, showSecrets)
+		sinp, err := serializeProperties(inp, enc, opts)
 		if err != nil {
 			return apitype.ResourceV3{}, err
 		}
@@ -269,7 +556,7 @@ func SerializeResource(res *resource.State, enc config.Encrypter, showSecrets bo
 	}
 	var outputs map[string]interface{}
 	if outp := res.Outputs; outp != nil {
-		soutp, err := SerializeProperties(outp, enc, showSecrets)
+		soutp, err := serializeProperties(outp, enc, opts)
 		if err != nil {
 			return apitype.ResourceV3{}, err
 		}
@@ -295,17 +582,27 @@ func SerializeResource(res *resource.State, enc config.Encrypter, showSecrets bo
 		AdditionalSecretOutputs: res.AdditionalSecretOutputs,
 		Aliases:                 res.Aliases,
 		ImportID:                res.ImportID,
+		Created:                 res.Created,
+		Modified:                res.Modified,
 	}
 
+	// Copy the timeouts so that the serialized resource does not alias the state's. Timeouts are omitted entirely if
+	// none are set.
 	if res.CustomTimeouts.IsNotEmpty() {
-		v3Resource.CustomTimeouts = &res.CustomTimeouts
+		timeouts := res.CustomTimeouts
+		v3Resource.CustomTimeouts = &timeouts
 	}
 
 	return v3Resource, nil
 }
 
 func SerializeOperation(op resource.Operation, enc config.Encrypter, showSecrets bool) (apitype.OperationV2, error) {
-	res, err := SerializeResource(op.Resource, enc, showSecrets)
+	return serializeOperation(op, enc, SerializeOptions{ShowSecrets: showSecrets})
+}
+
+func serializeOperation(op resource.Operation, enc config.Encrypter,
+	opts SerializeOptions) (apitype.OperationV2, error) {
+	res, err := serializeResource(op.Resource, enc, opts)
 	if err != nil {
 		return apitype.OperationV2{}, errors.Wrap(err, "serializing resource")
 	}
@@ -315,12 +612,19 @@ func SerializeOperation(op resource.Operation, enc config.Encrypter, showSecrets
 	}, nil
 }
 
-// SerializeProperties serializes a resource property bag so that it's suitable for serialization.
+// SerializeProperties serializes a resource property bag so that it's suitable for serialization. Every key in the
+// property bag is present in the result: null values serialize to nil, and secret values (including null ones)
+// serialize to a secret envelope.
 func SerializeProperties(props resource.PropertyMap, enc config.Encrypter,
 	showSecrets bool) (map[string]interface{}, error) {
+	return serializeProperties(props, enc, SerializeOptions{ShowSecrets: showSecrets})
+}
+
+func serializeProperties(props resource.PropertyMap, enc config.Encrypter,
+	opts SerializeOptions) (map[string]interface{}, error) {
 	dst := make(map[string]interface{})
 	for _, k := range props.StableKeys() {
-		v, err := SerializePropertyValue(props[k], enc, showSecrets)
+		v, err := serializePropertyValue(props[k], enc, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -332,6 +636,11 @@ func SerializeProperties(props resource.PropertyMap, enc config.Encrypter,
 // SerializePropertyValue serializes a resource property value so that it's suitable for serialization.
 func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 	showSecrets bool) (interface{}, error) {
+	return serializePropertyValue(prop, enc, SerializeOptions{ShowSecrets: showSecrets})
+}
+
+func serializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
+	opts SerializeOptions) (interface{}, error) {
 	// Serialize nulls as nil.
 	if prop.IsNull() {
 		return nil, nil
@@ -339,7 +648,7 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 
 	// A computed value marks something that will be determined at a later time. (e.g. the result of
 	// a computation that we don't perform during a preview operation.) We serialize a magic constant
-	// to record its existence.
+	// to record its existence, which DeserializePropertyValue turns back into a computed value.
 	if prop.IsComputed() || prop.IsOutput() {
 		return computedValuePlaceholder, nil
 	}
@@ -349,7 +658,7 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 		srcarr := prop.ArrayValue()
 		dstarr := make([]interface{}, len(srcarr))
 		for i, elem := range prop.ArrayValue() {
-			selem, err := SerializePropertyValue(elem, enc, showSecrets)
+			selem, err := serializePropertyValue(elem, enc, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -360,7 +669,7 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 
 	// Also for objects, recurse and use naked properties.
 	if prop.IsObject() {
-		return SerializeProperties(prop.ObjectValue(), enc, showSecrets)
+		return serializeProperties(prop.ObjectValue(), enc, opts)
 	}
 
 	// For assets, we need to serialize them a little carefully, so we can recover them afterwards.
@@ -388,7 +697,7 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 		// Since we are going to encrypt property value, we can elide encrypting sub-elements. We'll mark them as
 		// "secret" so we retain that information when deserializaing the overall structure, but there is no
 		// need to double encrypt everything.
-		value, err := SerializePropertyValue(prop.SecretValue().Element, config.NopEncrypter, showSecrets)
+		value, err := serializePropertyValue(prop.SecretValue().Element, config.NopEncrypter, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -412,10 +721,11 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 		contract.AssertNoErrorf(err, "marshalling underlying secret value to JSON")
 
 		secret := apitype.SecretV1{
-			Sig: resource.SecretSig,
+			Sig:     resource.SecretSig,
+			Version: secretEnvelopeVersionCurrent,
 		}
 
-		if showSecrets {
+		if opts.ShowSecrets {
 			secret.Plaintext = plaintext
 		} else {
 			secret.Ciphertext = ciphertext
@@ -424,27 +734,116 @@ func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter,
 		return secret, nil
 	}
 
-	// All others are returned as-is.
+	// If requested, integral numbers are serialized as JSON integers so that large values (e.g. numeric IDs) keep their
+	// exact digits rather than being encoded with an exponent.
+	if opts.ExactIntegers && prop.IsNumber() {
+		if n := prop.NumberValue(); n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return json.Number(strconv.FormatInt(int64(n), 10)), nil
+		}
+	}
+
+	// The remaining property values should be booleans, numbers, or strings, which are returned as-is. Values of other
+	// Go types can end up here if a property value was constructed directly rather than with NewPropertyValue.
+	// Timestamps are recorded as RFC 3339 strings; anything that JSON cannot represent is rejected here, rather than
+	// being left for the JSON encoder to fail on with less context.
+	switch v := prop.V.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.Errorf("cannot serialize non-finite number %v", v)
+		}
+	default:
+		switch reflect.ValueOf(prop.V).Kind() {
+		case reflect.Bool, reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32:
+		default:
+			return nil, errors.Errorf("cannot serialize property value of type %T", prop.V)
+		}
+	}
 	return prop.V, nil
 }
 
-// DeserializeResource turns a serialized resource back into its usual form.
+// DeserializeResource turns a serialized resource back into its usual form. It returns an error if any of the
+// resource's property values cannot be deserialized. The resource's URN, type, and provider reference are not checked,
+// so that checkpoints written by older versions of Pulumi continue to load; use DeserializeOptions.Validate to check
+// them when deserializing a deployment.
 func DeserializeResource(res apitype.ResourceV3, dec config.Decrypter, enc config.Encrypter) (*resource.State, error) {
 	// Deserialize the resource properties, if they exist.
 	inputs, err := DeserializeProperties(res.Inputs, dec, enc)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "deserializing inputs of resource %s", res.URN)
 	}
 	outputs, err := DeserializeProperties(res.Outputs, dec, enc)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "deserializing outputs of resource %s", res.URN)
 	}
 
 	return resource.NewState(
 		res.Type, res.URN, res.Custom, res.Delete, res.ID,
 		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider,
 		res.PropertyDependencies, res.PendingReplacement, res.AdditionalSecretOutputs, res.Aliases, res.CustomTimeouts,
-		res.ImportID), nil
+		res.ImportID, res.Created, res.Modified), nil
+}
+
+// validateResource returns an error if the serialized resource's URN, type, or provider reference is malformed.
+func validateResource(res apitype.ResourceV3) error {
+	if res.URN == "" {
+		return errors.New("resource is missing a URN")
+	}
+	if !res.URN.IsValid() {
+		return errors.Errorf("resource has a malformed URN %q", res.URN)
+	}
+	if _, err := tokens.ParseTypeToken(string(res.Type)); err != nil {
+		return errors.Wrapf(err, "resource %s has a malformed type", res.URN)
+	}
+	if res.Provider != "" {
+		if _, err := providers.ParseReference(res.Provider); err != nil {
+			return errors.Wrapf(err, "resource %s has a malformed provider reference", res.URN)
+		}
+	}
+	return nil
+}
+
+// MustDeserializeResource is like DeserializeResource, but panics if the resource cannot be deserialized.
+func MustDeserializeResource(res apitype.ResourceV3, dec config.Decrypter, enc config.Encrypter) *resource.State {
+	state, err := DeserializeResource(res, dec, enc)
+	contract.AssertNoErrorf(err, "deserializing resource %s", res.URN)
+	return state
+}
+
+// DeserializeResourceShallow turns a serialized resource back into its usual form without deserializing its inputs
+// or outputs, which are left nil. This is much cheaper than DeserializeResource for large resources, and is suitable
+// for callers that only need a resource's identity and its relationships to other resources. Because its property
+// maps are nil, the result must not be used in places that expect a fully-formed resource (e.g. a snapshot).
+func DeserializeResourceShallow(res apitype.ResourceV3) *resource.State {
+	var timeouts resource.CustomTimeouts
+	if res.CustomTimeouts != nil {
+		timeouts = *res.CustomTimeouts
+	}
+
+	return &resource.State{
+		Type:                    res.Type,
+		URN:                     res.URN,
+		Custom:                  res.Custom,
+		Delete:                  res.Delete,
+		ID:                      res.ID,
+		Parent:                  res.Parent,
+		Protect:                 res.Protect,
+		External:                res.External,
+		Dependencies:            res.Dependencies,
+		InitErrors:              res.InitErrors,
+		Provider:                res.Provider,
+		PropertyDependencies:    res.PropertyDependencies,
+		PendingReplacement:      res.PendingReplacement,
+		AdditionalSecretOutputs: res.AdditionalSecretOutputs,
+		Aliases:                 res.Aliases,
+		CustomTimeouts:          timeouts,
+		ImportID:                res.ImportID,
+		Created:                 res.Created,
+		Modified:                res.Modified,
+	}
 }
 
 func DeserializeOperation(op apitype.OperationV2, dec config.Decrypter,
@@ -453,7 +852,15 @@ func DeserializeOperation(op apitype.OperationV2, dec config.Decrypter,
 	if err != nil {
 		return resource.Operation{}, err
 	}
-	return resource.NewOperation(res, resource.OperationType(op.Type)), nil
+
+	switch op.Type {
+	case apitype.OperationTypeCreating, apitype.OperationTypeUpdating, apitype.OperationTypeDeleting,
+		apitype.OperationTypeReading, apitype.OperationTypeImporting:
+		return resource.NewOperation(res, resource.OperationType(op.Type)), nil
+	default:
+		return resource.Operation{}, errors.Errorf("unknown operation type '%v' for pending operation on %v",
+			op.Type, op.Resource.URN)
+	}
 }
 
 // DeserializeProperties deserializes an entire map of deploy properties into a resource property map.
@@ -470,7 +877,8 @@ func DeserializeProperties(props map[string]interface{}, dec config.Decrypter,
 	return result, nil
 }
 
-// DeserializePropertyValue deserializes a single deploy property into a resource property value.
+// DeserializePropertyValue deserializes a single deploy property into a resource property value. If dec is nil,
+// only secret values that were written without encryption can be deserialized; encrypted secrets produce an error.
 func DeserializePropertyValue(v interface{}, dec config.Decrypter,
 	enc config.Encrypter) (resource.PropertyValue, error) {
 	if v != nil {
@@ -479,13 +887,23 @@ func DeserializePropertyValue(v interface{}, dec config.Decrypter,
 			return resource.NewBoolProperty(w), nil
 		case float64:
 			return resource.NewNumberProperty(w), nil
+		case int:
+			return resource.NewNumberProperty(float64(w)), nil
+		case int64:
+			return resource.NewNumberProperty(float64(w)), nil
+		case json.Number:
+			n, err := w.Float64()
+			if err != nil {
+				return resource.PropertyValue{}, errors.Wrapf(err, "malformed number %q", w)
+			}
+			return resource.NewNumberProperty(n), nil
 		case string:
 			if w == computedValuePlaceholder {
 				return resource.MakeComputed(resource.NewStringProperty("")), nil
 			}
 			return resource.NewStringProperty(w), nil
 		case []interface{}:
-			var arr []resource.PropertyValue
+			arr := make([]resource.PropertyValue, 0, len(w))
 			for _, elem := range w {
 				ev, err := DeserializePropertyValue(elem, dec, enc)
 				if err != nil {
@@ -519,6 +937,14 @@ func DeserializePropertyValue(v interface{}, dec config.Decrypter,
 					contract.Assert(isarchive)
 					return resource.NewArchiveProperty(archive), nil
 				case resource.SecretSig:
+					version, err := secretEnvelopeVersion(objmap)
+					if err != nil {
+						return resource.PropertyValue{}, err
+					}
+					if version > secretEnvelopeVersionCurrent {
+						return resource.PropertyValue{}, errors.Errorf("unsupported secret envelope version %d", version)
+					}
+
 					ciphertext, cipherOk := objmap["ciphertext"].(string)
 					plaintext, plainOk := objmap["plaintext"].(string)
 					if (!cipherOk && !plainOk) || (plainOk && cipherOk) {
@@ -526,14 +952,20 @@ func DeserializePropertyValue(v interface{}, dec config.Decrypter,
 							"malformed secret value: one of `ciphertext` or `plaintext` must be supplied")
 					}
 
-					if plainOk {
+					switch {
+					case plainOk && enc != nil:
 						encryptedText, err := enc.EncryptValue(plaintext)
 						if err != nil {
 							return resource.PropertyValue{}, errors.Wrap(err, "encrypting secret value")
 						}
 						ciphertext = encryptedText
-
-					} else {
+					case plainOk:
+						// Without an encrypter there is no ciphertext to record.
+					case dec == nil:
+						// Only secrets written without encryption (i.e. with a `plaintext` field) can be read without
+						// a decrypter; treating ciphertext as plaintext would silently corrupt the value.
+						return resource.PropertyValue{}, errors.New("cannot decrypt secret value: no decrypter configured")
+					default:
 						unencryptedText, err := dec.DecryptValue(ciphertext)
 						if err != nil {
 							return resource.PropertyValue{}, errors.Wrap(err, "decrypting secret value")
@@ -618,9 +1050,37 @@ func DeserializePropertyValue(v interface{}, dec config.Decrypter,
 			// Otherwise, it's just a weakly typed object map.
 			return resource.NewObjectProperty(obj), nil
 		default:
-			contract.Failf("Unrecognized property type: %v", reflect.ValueOf(v))
+			return resource.PropertyValue{}, errors.Errorf("unrecognized property type %T", v)
 		}
 	}
 
 	return resource.NewNullProperty(), nil
 }
+
+// secretEnvelopeVersion returns the version of the given serialized secret envelope.
+func secretEnvelopeVersion(objmap map[string]interface{}) (int, error) {
+	v, has := objmap["version"]
+	if !has {
+		return 1, nil
+	}
+
+	var version float64
+	switch v := v.(type) {
+	case float64:
+		version = v
+	case int:
+		version = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, errors.Errorf("malformed secret envelope version %q", v)
+		}
+		version = f
+	default:
+		return 0, errors.Errorf("malformed secret envelope version: expected a number, got %T", v)
+	}
+	if version != math.Trunc(version) || version < 1 {
+		return 0, errors.Errorf("malformed secret envelope version %v", version)
+	}
+	return int(version), nil
+}
@@ -15,12 +15,21 @@
 package stack
 
 import (
+	"bytes"
 	"encoding/json"
+	"math"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
@@ -89,6 +98,8 @@ func TestDeploymentSerialization(t *testing.T) {
 		nil,
 		nil,
 		"",
+		nil,
+		nil,
 	)
 
 	dep, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
@@ -200,6 +211,78 @@ func TestLoadTooOldDeployment(t *testing.T) {
 	assert.Equal(t, ErrDeploymentSchemaVersionTooOld, err)
 }
 
+func TestLoadLegacyDeploymentWithDefaults(t *testing.T) {
+	untypedDeployment := &apitype.UntypedDeployment{
+		Version: 1,
+		Deployment: json.RawMessage(`{
+			"manifest": {"time": "2018-01-01T00:00:00Z", "magic": "", "version": ""},
+			"resources": [{
+				"urn": "urn:pulumi:stack::project::pkgA:m:typA::resA",
+				"custom": true,
+				"id": "id",
+				"type": "pkgA:m:typA",
+				"inputs": {"foo": "input", "bar": "input"},
+				"defaults": {"bar": "default", "baz": "default"},
+				"outputs": {"foo": "output"}
+			}]
+		}`),
+	}
+
+	snap, err := DeserializeUntypedDeployment(untypedDeployment, DefaultSecretsProvider)
+	assert.NoError(t, err)
+	if assert.Len(t, snap.Resources, 1) {
+		assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+			"foo": "input",
+			"bar": "input",
+			"baz": "default",
+		}), snap.Resources[0].Inputs)
+	}
+
+	// The defaults are not emitted when the snapshot is serialized again.
+	deployment, err := SerializeDeployment(snap, nil, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"defaults"`)
+}
+
+func TestLoadDeploymentWithUnknownFields(t *testing.T) {
+	untypedDeployment := &apitype.UntypedDeployment{
+		Version: 3,
+		Deployment: json.RawMessage(`{
+			"manifest": {"time": "2018-01-01T00:00:00Z", "magic": "", "version": ""},
+			"resources": [{
+				"urn": "urn:pulumi:stack::project::pkgA:m:typA::resA",
+				"custom": true,
+				"id": "id",
+				"type": "pkgA:m:typA",
+				"newField": true
+			}]
+		}`),
+	}
+
+	// By default, unknown fields are ignored.
+	snap, err := DeserializeUntypedDeployment(untypedDeployment, DefaultSecretsProvider)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 1)
+
+	// In strict mode, they are reported by name.
+	_, err = DeserializeUntypedDeploymentWithOptions(untypedDeployment, DefaultSecretsProvider,
+		DeserializeOptions{Strict: true})
+	assert.EqualError(t, err,
+		`deployment contains a field that is not recognized by this version of Pulumi: "newField"`)
+
+	// Deployments without unknown fields load in strict mode.
+	untypedDeployment.Deployment = json.RawMessage(strings.Replace(string(untypedDeployment.Deployment),
+		`"newField": true`, `"protect": true`, 1))
+	snap, err = DeserializeUntypedDeploymentWithOptions(untypedDeployment, DefaultSecretsProvider,
+		DeserializeOptions{Strict: true})
+	assert.NoError(t, err)
+	if assert.Len(t, snap.Resources, 1) {
+		assert.True(t, snap.Resources[0].Protect)
+	}
+}
+
 func TestUnsupportedSecret(t *testing.T) {
 	rawProp := map[string]interface{}{
 		resource.SigKey: resource.SecretSig,
@@ -208,6 +291,58 @@ func TestUnsupportedSecret(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestPlaintextSecretWithoutDecrypter(t *testing.T) {
+	rawProp := map[string]interface{}{
+		resource.SigKey: resource.SecretSig,
+		"plaintext":     `"hunter2"`,
+	}
+	prop, err := DeserializePropertyValue(rawProp, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("hunter2")), prop)
+
+	// The value must remain a secret when it is serialized again.
+	serialized, err := SerializePropertyValue(prop, config.NopEncrypter, true /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, apitype.SecretV1{Sig: resource.SecretSig, Plaintext: `"hunter2"`, Version: 1}, serialized)
+}
+
+func TestEncryptedSecretWithoutDecrypter(t *testing.T) {
+	rawProp := map[string]interface{}{
+		resource.SigKey: resource.SecretSig,
+		"ciphertext":    `"hunter2"`,
+	}
+	_, err := DeserializePropertyValue(rawProp, nil, nil)
+	assert.EqualError(t, err, "cannot decrypt secret value: no decrypter configured")
+}
+
+func TestSecretEnvelopeVersion(t *testing.T) {
+	for _, version := range []interface{}{nil, float64(1), json.Number("1")} {
+		rawProp := map[string]interface{}{
+			resource.SigKey: resource.SecretSig,
+			"plaintext":     `"hunter2"`,
+		}
+		if version != nil {
+			rawProp["version"] = version
+		}
+		prop, err := DeserializePropertyValue(rawProp, config.NopDecrypter, config.NopEncrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("hunter2")), prop)
+	}
+
+	// Future versions are rejected before any attempt is made to decrypt them.
+	rawProp := map[string]interface{}{
+		resource.SigKey: resource.SecretSig,
+		"ciphertext":    "ciphertext",
+		"version":       float64(2),
+	}
+	_, err := DeserializePropertyValue(rawProp, config.NewPanicCrypter(), config.NewPanicCrypter())
+	assert.EqualError(t, err, "unsupported secret envelope version 2")
+
+	rawProp["version"] = "one"
+	_, err = DeserializePropertyValue(rawProp, config.NewPanicCrypter(), config.NewPanicCrypter())
+	assert.Error(t, err)
+}
+
 func TestUnknownSig(t *testing.T) {
 	rawProp := map[string]interface{}{
 		resource.SigKey: "foobar",
@@ -253,6 +388,35 @@ func TestDeserializeResourceReferencePropertyValueID(t *testing.T) {
 	}), deserialized)
 }
 
+func TestResourceReferenceRoundTrip(t *testing.T) {
+	component := resource.MakeComponentResourceReference(testURN("component"), "1.2.3")
+	custom := resource.MakeCustomResourceReference(testURN("custom"), "id", "2.3.4")
+	unknownID := resource.MakeCustomResourceReference(testURN("pending"), "", "3.4.5")
+	props := resource.PropertyMap{
+		"component": component,
+		"custom":    custom,
+		"unknownID": unknownID,
+		"array":     resource.NewArrayProperty([]resource.PropertyValue{component, custom}),
+		"secret":    resource.MakeSecret(custom),
+	}
+
+	serialized, err := SerializeProperties(props, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err := json.Marshal(serialized)
+	assert.NoError(t, err)
+	var raw map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &raw))
+
+	// References without an ID omit it, and those with an ID (even an unknown one) keep it.
+	assert.NotContains(t, raw["component"], "id")
+	assert.Equal(t, "id", raw["custom"].(map[string]interface{})["id"])
+	assert.Equal(t, "", raw["unknownID"].(map[string]interface{})["id"])
+
+	deserialized, err := DeserializeProperties(raw, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, props, deserialized)
+}
+
 func TestCustomSerialization(t *testing.T) {
 	textAsset, err := resource.NewTextAsset("alpha beta gamma")
 	assert.NoError(t, err)
@@ -384,7 +548,7 @@ func TestCustomSerialization(t *testing.T) {
 
 			// Secrets are serialized with the special sig key, and their underlying cipher text.
 			// Since we passed in a config.BlindingCrypter the cipher text isn't super-useful.
-			`"secret":{"4dabf18193072939515e22adb298388d":"1b47061264138c4ac30d75fd1eb44270","ciphertext":"[secret]"}`,
+			`"secret":{"4dabf18193072939515e22adb298388d":"1b47061264138c4ac30d75fd1eb44270","ciphertext":"[secret]","version":1}`,
 		}
 		for _, want := range tests {
 			if !strings.Contains(json, want) {
@@ -397,3 +561,673 @@ func TestCustomSerialization(t *testing.T) {
 		}
 	})
 }
+
+func TestImportIDRoundTrip(t *testing.T) {
+	res := &resource.State{
+		Type:     tokens.Type("pkgA:m:typA"),
+		URN:      resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Custom:   true,
+		ID:       resource.ID("current-id"),
+		ImportID: resource.ID("imported-id"),
+	}
+
+	sres, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.ID("imported-id"), sres.ImportID)
+
+	des, err := DeserializeResource(sres, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.ID("current-id"), des.ID)
+	assert.Equal(t, resource.ID("imported-id"), des.ImportID)
+}
+
+func TestPendingReplacementRoundTrip(t *testing.T) {
+	res := &resource.State{
+		Type:               tokens.Type("pkgA:m:typA"),
+		URN:                resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Custom:             true,
+		ID:                 resource.ID("id"),
+		PendingReplacement: true,
+	}
+
+	sres, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err := json.Marshal(sres)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"pendingReplacement":true`)
+
+	var unmarshaled apitype.ResourceV3
+	assert.NoError(t, json.Unmarshal(b, &unmarshaled))
+	des, err := DeserializeResource(unmarshaled, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.True(t, des.PendingReplacement)
+	assert.False(t, des.Delete)
+
+	// Resources that are not awaiting replacement omit the marker entirely.
+	res.PendingReplacement = false
+	sres, err = SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err = json.Marshal(sres)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "pendingReplacement")
+}
+
+func TestDeserializeResourceShallow(t *testing.T) {
+	res := testResourceV3("b", "a")
+	res.ID = "b-id"
+	res.Parent = testURN("a")
+	res.Inputs = map[string]interface{}{"foo": "bar"}
+	res.Outputs = map[string]interface{}{
+		"secret": map[string]interface{}{
+			resource.SigKey: resource.SecretSig,
+			"ciphertext":    "not-decryptable",
+		},
+	}
+
+	// Properties are not deserialized, so there is no need for a decrypter.
+	state := DeserializeResourceShallow(res)
+	assert.Equal(t, res.URN, state.URN)
+	assert.Equal(t, res.Type, state.Type)
+	assert.Equal(t, res.ID, state.ID)
+	assert.Equal(t, testURN("a"), state.Parent)
+	assert.Equal(t, []resource.URN{testURN("a")}, state.Dependencies)
+	assert.Nil(t, state.Inputs)
+	assert.Nil(t, state.Outputs)
+}
+
+func TestIntegerSerialization(t *testing.T) {
+	props := resource.PropertyMap{
+		"id":    resource.NewNumberProperty(1234567890123456),
+		"float": resource.NewNumberProperty(1.5),
+	}
+
+	// By default, integral numbers are serialized as float64 values.
+	serialized, err := SerializeProperties(props, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1234567890123456), serialized["id"])
+
+	opts := SerializeOptions{ExactIntegers: true}
+	serialized, err = serializeProperties(props, config.NopEncrypter, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("1234567890123456"), serialized["id"])
+	b, err := json.Marshal(serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"float":1.5,"id":1234567890123456}`, string(b))
+
+	// Decoding the JSON with numbers preserved and serializing it again must produce identical bytes.
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var raw map[string]interface{}
+	assert.NoError(t, decoder.Decode(&raw))
+
+	deserialized, err := DeserializeProperties(raw, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, props, deserialized)
+
+	reserialized, err := serializeProperties(deserialized, config.NopEncrypter, opts)
+	assert.NoError(t, err)
+	b2, err := json.Marshal(reserialized)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(b2))
+}
+
+func TestDeserializeIntegerPropertyValues(t *testing.T) {
+	for _, v := range []interface{}{int(42), int64(42), json.Number("42"), float64(42)} {
+		prop, err := DeserializePropertyValue(v, config.NopDecrypter, config.NopEncrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, resource.NewNumberProperty(42), prop)
+	}
+
+	_, err := DeserializePropertyValue(json.Number("forty-two"), config.NopDecrypter, config.NopEncrypter)
+	assert.Error(t, err)
+
+	// Values of unrecognized types are errors rather than panics, including when they are nested.
+	_, err = DeserializeProperties(map[string]interface{}{
+		"nested": map[string]interface{}{"values": []interface{}{uint8(1)}},
+	}, config.NopDecrypter, config.NopEncrypter)
+	assert.EqualError(t, err, "unrecognized property type uint8")
+}
+
+func TestDeserializeResourceErrors(t *testing.T) {
+	valid := apitype.ResourceV3{
+		URN:  resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Type: tokens.Type("pkgA:m:typA"),
+	}
+	des, err := DeserializeResource(valid, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, des, MustDeserializeResource(valid, config.NopDecrypter, config.NopEncrypter))
+
+	malformedProperty := valid
+	malformedProperty.Outputs = map[string]interface{}{"foo": uint8(1)}
+	_, err = DeserializeResource(malformedProperty, config.NopDecrypter, config.NopEncrypter)
+	assert.EqualError(t, err, "deserializing outputs of resource urn:pulumi:stack::project::pkgA:m:typA::resA: "+
+		"unrecognized property type uint8")
+	assert.Panics(t, func() {
+		MustDeserializeResource(malformedProperty, config.NopDecrypter, config.NopEncrypter)
+	})
+}
+
+func TestDeserializeDeploymentMalformedResources(t *testing.T) {
+	valid := apitype.ResourceV3{
+		URN:  resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Type: tokens.Type("pkgA:m:typA"),
+	}
+	missingURN := valid
+	missingURN.URN = ""
+	malformedURN := valid
+	malformedURN.URN = "resA"
+	malformedType := valid
+	malformedType.Type = "typA"
+	malformedProvider := valid
+	malformedProvider.Provider = "prov"
+
+	cases := []struct {
+		res apitype.ResourceV3
+		err string
+	}{
+		{missingURN, "resource is missing a URN"},
+		{malformedURN, `resource has a malformed URN "resA"`},
+		{malformedType, "resource urn:pulumi:stack::project::pkgA:m:typA::resA has a malformed type: " +
+			"Type 'typA' is not a valid type token (must have format '*:*:*')"},
+		{malformedProvider, "resource urn:pulumi:stack::project::pkgA:m:typA::resA has a malformed provider " +
+			"reference: expected '::' in provider reference 'prov'"},
+	}
+	for _, c := range cases {
+		// Legacy checkpoints with malformed resources still load by default.
+		deployment := apitype.DeploymentV3{Resources: []apitype.ResourceV3{c.res}}
+		snap, err := DeserializeDeploymentV3(deployment, nil)
+		if assert.NoError(t, err) && assert.Len(t, snap.Resources, 1) {
+			assert.Equal(t, c.res.URN, snap.Resources[0].URN)
+		}
+
+		// When validation is requested, they are rejected.
+		_, err = DeserializeDeploymentV3WithOptions(deployment, nil, DeserializeOptions{Validate: true})
+		assert.EqualError(t, err, c.err)
+	}
+}
+
+func TestProviderRoundTrip(t *testing.T) {
+	providerURN := resource.NewURN("stack", "project", "", "pulumi:providers:pkgA", "prov")
+	ref, err := providers.NewReference(providerURN, "prov-id")
+	assert.NoError(t, err)
+
+	res := testResourceState("resA")
+	res.Provider = ref.String()
+
+	ser, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, ref.String(), ser.Provider)
+
+	des, err := DeserializeResource(ser, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, ref.String(), des.Provider)
+
+	parsed, err := providers.ParseReference(des.Provider)
+	assert.NoError(t, err)
+	assert.Equal(t, providerURN, parsed.URN())
+	assert.Equal(t, resource.ID("prov-id"), parsed.ID())
+}
+
+func TestDependenciesRoundTrip(t *testing.T) {
+	parent := testResourceState("parent")
+	dep := testResourceState("dep")
+	child := testResourceState("child", "parent", "dep")
+	child.Parent = parent.URN
+	child.PropertyDependencies = map[resource.PropertyKey][]resource.URN{"foo": {dep.URN}}
+
+	snap := deploy.NewSnapshot(deploy.Manifest{}, nil, []*resource.State{parent, dep, child}, nil)
+	deployment, err := SerializeDeployment(snap, nil, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, parent.URN, deployment.Resources[2].Parent)
+	assert.Equal(t, []resource.URN{parent.URN, dep.URN}, deployment.Resources[2].Dependencies)
+
+	des, err := DeserializeDeploymentV3(*deployment, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, des.Resources, 3) {
+		// Dependencies keep the order in which they were recorded.
+		assert.Equal(t, parent.URN, des.Resources[2].Parent)
+		assert.Equal(t, child.Dependencies, des.Resources[2].Dependencies)
+		assert.Equal(t, child.PropertyDependencies, des.Resources[2].PropertyDependencies)
+	}
+}
+
+func TestProtectRoundTrip(t *testing.T) {
+	for _, protect := range []bool{true, false} {
+		res := testResourceState("resA")
+		res.Protect = protect
+
+		ser, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+		assert.NoError(t, err)
+		b, err := json.Marshal(ser)
+		assert.NoError(t, err)
+		assert.Equal(t, protect, strings.Contains(string(b), `"protect":true`))
+
+		var raw apitype.ResourceV3
+		assert.NoError(t, json.Unmarshal(b, &raw))
+		des, err := DeserializeResource(raw, config.NopDecrypter, config.NopEncrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, protect, des.Protect)
+	}
+}
+
+func TestCustomTimeoutsRoundTrip(t *testing.T) {
+	// Resources without custom timeouts omit the field.
+	ser, err := SerializeResource(testResourceState("resA"), config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Nil(t, ser.CustomTimeouts)
+	b, err := json.Marshal(ser)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "customTimeouts")
+
+	res := testResourceState("resB")
+	res.CustomTimeouts = resource.CustomTimeouts{Create: 60, Delete: 1.5}
+	ser, err = SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err = json.Marshal(ser)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"customTimeouts":{"create":60,"delete":1.5}`)
+
+	// The serialized timeouts are a copy of the resource's.
+	res.CustomTimeouts.Create = 120
+	assert.Equal(t, float64(60), ser.CustomTimeouts.Create)
+
+	var raw apitype.ResourceV3
+	assert.NoError(t, json.Unmarshal(b, &raw))
+	des, err := DeserializeResource(raw, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.CustomTimeouts{Create: 60, Delete: 1.5}, des.CustomTimeouts)
+}
+
+func TestDeserializeDeploymentAllErrors(t *testing.T) {
+	badInputs := testResourceV3("badInputs")
+	badInputs.Inputs = map[string]interface{}{"foo": uint8(1)}
+	badOutputs := testResourceV3("badOutputs")
+	badOutputs.Outputs = map[string]interface{}{"foo": uint8(1)}
+	deployment := apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("good"), badInputs, badOutputs},
+		PendingOperations: []apitype.OperationV2{{
+			Resource: testResourceV3("pending"),
+			Type:     "bogus",
+		}},
+	}
+
+	// By default, deserialization stops at the first error.
+	_, err := DeserializeDeploymentV3(deployment, nil)
+	assert.EqualError(t, err, "deserializing inputs of resource urn:pulumi:stack::project::pkgA:m:typA::badInputs: "+
+		"unrecognized property type uint8")
+
+	// Otherwise, every error is reported.
+	_, err = DeserializeDeploymentV3WithOptions(deployment, nil, DeserializeOptions{AllErrors: true})
+	if merr, ok := err.(*multierror.Error); assert.True(t, ok) && assert.Len(t, merr.Errors, 3) {
+		assert.Contains(t, merr.Errors[0].Error(), "deserializing inputs of resource "+string(testURN("badInputs")))
+		assert.Contains(t, merr.Errors[1].Error(), "deserializing outputs of resource "+string(testURN("badOutputs")))
+		assert.Contains(t, merr.Errors[2].Error(), "deserializing pending operation on "+string(testURN("pending")))
+	}
+
+	// The option is also honored when deserializing an untyped deployment.
+	b, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	_, err = DeserializeUntypedDeploymentWithOptions(&apitype.UntypedDeployment{Version: 3, Deployment: b}, nil,
+		DeserializeOptions{AllErrors: true})
+	assert.IsType(t, &multierror.Error{}, err)
+}
+
+func TestDeserializeDeploymentValidate(t *testing.T) {
+	orphan := testResourceV3("orphan")
+	orphan.Parent = testURN("missing")
+	child := testResourceV3("child")
+	child.Parent = testURN("gone")
+	deployment := apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("good"), orphan, child},
+	}
+
+	// Dangling parent references are not checked by default.
+	_, err := DeserializeDeploymentV3(deployment, nil)
+	assert.NoError(t, err)
+
+	// When validation is requested, every dangling reference is reported.
+	_, err = DeserializeDeploymentV3WithOptions(deployment, nil, DeserializeOptions{Validate: true})
+	var merr *multierror.Error
+	if assert.True(t, errors.As(err, &merr)) && assert.Len(t, merr.Errors, 2) {
+		assert.EqualError(t, merr.Errors[0], "child resource "+string(testURN("orphan"))+" refers to missing parent "+
+			string(testURN("missing")))
+		assert.EqualError(t, merr.Errors[1], "child resource "+string(testURN("child"))+" refers to missing parent "+
+			string(testURN("gone")))
+	}
+
+	// A consistent deployment passes validation.
+	snap, err := DeserializeDeploymentV3WithOptions(apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("good"), testResourceV3("other", "good")},
+	}, nil, DeserializeOptions{Validate: true})
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 2)
+}
+
+func TestComputedRoundTrip(t *testing.T) {
+	unknown := resource.MakeComputed(resource.NewStringProperty(""))
+	props := resource.PropertyMap{
+		"computed": unknown,
+		"array":    resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("a"), unknown}),
+		"object":   resource.NewObjectProperty(resource.PropertyMap{"nested": unknown}),
+		"secret":   resource.MakeSecret(unknown),
+	}
+
+	// Computed values are not dropped, including when they are nested or secret.
+	serialized, err := SerializeProperties(props, config.NopEncrypter, true /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, computedValuePlaceholder, serialized["computed"])
+	assert.Equal(t, []interface{}{"a", computedValuePlaceholder}, serialized["array"])
+	assert.Equal(t, map[string]interface{}{"nested": computedValuePlaceholder}, serialized["object"])
+
+	b, err := json.Marshal(serialized)
+	assert.NoError(t, err)
+	var raw map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &raw))
+
+	deserialized, err := DeserializeProperties(raw, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, props, deserialized)
+}
+
+func TestSerializePropertiesWellKnownTypes(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+	props := resource.PropertyMap{
+		"created": {V: created},
+		"count":   {V: 3},
+	}
+	serialized, err := SerializeProperties(props, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"created": "2021-03-04T05:06:07.000000008Z", "count": 3}, serialized)
+	_, err = json.Marshal(serialized)
+	assert.NoError(t, err)
+
+	// Values that cannot be represented in JSON are rejected.
+	for _, v := range []interface{}{math.NaN(), math.Inf(1), func() {}, make(chan int), struct{}{}} {
+		_, err = SerializeProperties(resource.PropertyMap{"foo": {V: v}}, config.NopEncrypter, false)
+		assert.Error(t, err, "%T", v)
+	}
+	_, err = SerializeProperties(resource.PropertyMap{"foo": {V: func() {}}}, config.NopEncrypter, false)
+	assert.EqualError(t, err, "cannot serialize property value of type func()")
+}
+
+func TestSerializePropertiesSecretKeys(t *testing.T) {
+	props := func(wrap func(resource.PropertyValue) resource.PropertyValue) resource.PropertyMap {
+		return resource.PropertyMap{
+			"null":   wrap(resource.NewNullProperty()),
+			"string": wrap(resource.NewStringProperty("a")),
+			"object": resource.NewObjectProperty(resource.PropertyMap{
+				"null": wrap(resource.NewNullProperty()),
+			}),
+		}
+	}
+	plain := props(func(v resource.PropertyValue) resource.PropertyValue { return v })
+	secret := props(resource.MakeSecret)
+
+	// Secret values, including null ones, serialize to a secret envelope rather than to nil, so the serializations of
+	// the two maps have the same keys at every level.
+	plainSerialized, err := SerializeProperties(plain, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	secretSerialized, err := SerializeProperties(secret, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+
+	keys := func(m map[string]interface{}) []string {
+		var keys []string
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	assert.Equal(t, keys(plainSerialized), keys(secretSerialized))
+	assert.Equal(t, keys(plainSerialized["object"].(map[string]interface{})),
+		keys(secretSerialized["object"].(map[string]interface{})))
+	assert.Nil(t, plainSerialized["null"])
+	assert.IsType(t, apitype.SecretV1{}, secretSerialized["null"])
+
+	// The serialization is stable across runs.
+	again, err := SerializeProperties(secret, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	assert.Equal(t, secretSerialized, again)
+
+	b, err := json.Marshal(secretSerialized)
+	assert.NoError(t, err)
+	var raw map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &raw))
+	deserialized, err := DeserializeProperties(raw, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, deserialized)
+}
+
+func TestDeserializeDeploymentCycle(t *testing.T) {
+	a := testResourceV3("a", "c")
+	b := testResourceV3("b")
+	b.Parent = a.URN
+	c := testResourceV3("c", "b")
+	deployment := apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("root"), a, b, c},
+	}
+
+	_, err := DeserializeDeploymentV3(deployment, nil)
+	if assert.Error(t, err) {
+		// The error must name every resource in the cycle, in order.
+		cycle := strings.Join([]string{string(a.URN), string(c.URN), string(b.URN), string(a.URN)}, " -> ")
+		assert.Contains(t, err.Error(), cycle)
+		assert.NotContains(t, err.Error(), string(testURN("root")))
+	}
+
+	// A resource that refers to itself is also a cycle.
+	self := testResourceV3("self", "self")
+	_, err = DeserializeDeploymentV3(apitype.DeploymentV3{Resources: []apitype.ResourceV3{self}}, nil)
+	assert.Error(t, err)
+}
+
+func TestTimestampsRoundTrip(t *testing.T) {
+	created := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	modified := created.Add(time.Hour)
+	res := &resource.State{
+		Type:     tokens.Type("pkgA:m:typA"),
+		URN:      resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Custom:   true,
+		ID:       resource.ID("id"),
+		Created:  &created,
+		Modified: &modified,
+	}
+
+	sres, err := SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err := json.Marshal(sres)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"created":"2021-03-01T12:00:00Z"`)
+
+	var unmarshaled apitype.ResourceV3
+	assert.NoError(t, json.Unmarshal(b, &unmarshaled))
+	des, err := DeserializeResource(unmarshaled, config.NopDecrypter, config.NopEncrypter)
+	assert.NoError(t, err)
+	assert.True(t, created.Equal(*des.Created))
+	assert.True(t, modified.Equal(*des.Modified))
+
+	// Resources without timestamps omit them entirely.
+	res.Created, res.Modified = nil, nil
+	sres, err = SerializeResource(res, config.NopEncrypter, false /* showSecrets */)
+	assert.NoError(t, err)
+	b, err = json.Marshal(sres)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "created")
+	assert.NotContains(t, string(b), "modified")
+}
+
+func TestDeserializeOperationType(t *testing.T) {
+	res := apitype.ResourceV3{
+		URN:    resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Type:   tokens.Type("pkgA:m:typA"),
+		Custom: true,
+	}
+
+	for _, typ := range []apitype.OperationType{
+		apitype.OperationTypeCreating,
+		apitype.OperationTypeUpdating,
+		apitype.OperationTypeDeleting,
+		apitype.OperationTypeReading,
+		apitype.OperationTypeImporting,
+	} {
+		// Operation types are encoded as their string form.
+		b, err := json.Marshal(apitype.OperationV2{Resource: res, Type: typ})
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `"type":"`+string(typ)+`"`)
+
+		var op apitype.OperationV2
+		assert.NoError(t, json.Unmarshal(b, &op))
+		des, err := DeserializeOperation(op, config.NopDecrypter, config.NopEncrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, resource.OperationType(typ), des.Type)
+	}
+
+	_, err := DeserializeOperation(apitype.OperationV2{Resource: res, Type: "refreshing"},
+		config.NopDecrypter, config.NopEncrypter)
+	assert.EqualError(t, err,
+		"unknown operation type 'refreshing' for pending operation on urn:pulumi:stack::project::pkgA:m:typA::resA")
+}
+
+func TestSerializeDeploymentResourceTransform(t *testing.T) {
+	newResource := func(name string) *resource.State {
+		return &resource.State{
+			Type:   tokens.Type("pkgA:m:typA"),
+			URN:    resource.NewURN("stack", "project", "", "pkgA:m:typA", tokens.QName(name)),
+			Custom: true,
+			ID:     resource.ID(name + "-id"),
+			Inputs: resource.NewPropertyMapFromMap(map[string]interface{}{"password": "hunter2"}),
+		}
+	}
+	snap := deploy.NewSnapshot(deploy.Manifest{}, nil,
+		[]*resource.State{newResource("a"), newResource("b")}, nil)
+
+	// Transforms can redact fields and add metadata.
+	deployment, err := SerializeDeploymentWithOptions(snap, nil, SerializeOptions{
+		ResourceTransform: func(res apitype.ResourceV3) (apitype.ResourceV3, error) {
+			res.Inputs["password"] = "[redacted]"
+			res.Outputs = map[string]interface{}{"compliance": "checked"}
+			return res, nil
+		},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, deployment.Resources, 2) {
+		for _, res := range deployment.Resources {
+			assert.Equal(t, map[string]interface{}{"password": "[redacted]"}, res.Inputs)
+			assert.Equal(t, map[string]interface{}{"compliance": "checked"}, res.Outputs)
+		}
+	}
+
+	// Transforms can reject resources.
+	_, err = SerializeDeploymentWithOptions(snap, nil, SerializeOptions{
+		ResourceTransform: func(res apitype.ResourceV3) (apitype.ResourceV3, error) {
+			if res.ID == "b-id" {
+				return apitype.ResourceV3{}, errors.New("missing owner tag")
+			}
+			return res, nil
+		},
+	})
+	assert.EqualError(t, err, "transforming resource urn:pulumi:stack::project::pkgA:m:typA::b: missing owner tag")
+}
+
+func TestSerializeDeploymentTo(t *testing.T) {
+	newResource := func(name string) *resource.State {
+		return &resource.State{
+			Type:   tokens.Type("pkgA:m:typA"),
+			URN:    resource.NewURN("stack", "project", "", "pkgA:m:typA", tokens.QName(name)),
+			Custom: true,
+			ID:     resource.ID(name + "-id"),
+			Inputs: resource.PropertyMap{
+				"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+				"html":     resource.NewStringProperty("<b>&</b>"),
+			},
+		}
+	}
+	a, b := newResource("a"), newResource("b")
+	ops := []resource.Operation{resource.NewOperation(b, resource.OperationTypeCreating)}
+
+	manifest := deploy.Manifest{Time: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)}
+	sm := b64.NewBase64SecretsManager()
+	snaps := map[string]*deploy.Snapshot{
+		"empty":      deploy.NewSnapshot(manifest, nil, nil, nil),
+		"resources":  deploy.NewSnapshot(manifest, sm, []*resource.State{a, b}, nil),
+		"operations": deploy.NewSnapshot(manifest, sm, []*resource.State{a}, ops),
+	}
+	for name, snap := range snaps {
+		// The streamed encoding is identical to the encoding of the deployment built in memory.
+		expected, err := SerializeDeploymentWithOptions(snap, nil, SerializeOptions{})
+		assert.NoError(t, err)
+		expectedJSON, err := json.Marshal(expected)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, SerializeDeploymentTo(&buf, snap, nil, SerializeOptions{}), name)
+		assert.Equal(t, string(expectedJSON), buf.String(), name)
+	}
+
+	// Transform errors are returned.
+	err := SerializeDeploymentTo(&bytes.Buffer{}, snaps["resources"], nil, SerializeOptions{
+		ResourceTransform: func(res apitype.ResourceV3) (apitype.ResourceV3, error) {
+			return apitype.ResourceV3{}, errors.New("rejected")
+		},
+	})
+	assert.EqualError(t, err, "transforming resource urn:pulumi:stack::project::pkgA:m:typA::a: rejected")
+}
+
+func TestExportDeployment(t *testing.T) {
+	d := &apitype.DeploymentV3{
+		Manifest: apitype.ManifestV1{
+			Time:    time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC),
+			Magic:   "magic",
+			Version: "v3.0.0",
+		},
+		Resources: []apitype.ResourceV3{{
+			URN:    "urn:pulumi:stack::project::pkgA:m:typA::a",
+			Custom: true,
+			ID:     "a-id",
+			Type:   "pkgA:m:typA",
+			Outputs: map[string]interface{}{
+				"zeta":  "z",
+				"alpha": 12345678901234567890.0,
+				"html":  "<b>&</b>",
+			},
+		}},
+	}
+
+	// Keys are sorted at every level, and strings are escaped in the same way as by json.Marshal.
+	var buf bytes.Buffer
+	assert.NoError(t, ExportDeployment(&buf, d, ExportOptions{Indent: "  "}))
+	assert.Equal(t, `{
+  "manifest": {
+    "magic": "magic",
+    "time": "2021-04-01T12:00:00Z",
+    "version": "v3.0.0"
+  },
+  "resources": [
+    {
+      "custom": true,
+      "id": "a-id",
+      "outputs": {
+        "alpha": 12345678901234567000,
+        "html": "\u003cb\u003e\u0026\u003c/b\u003e",
+        "zeta": "z"
+      },
+      "type": "pkgA:m:typA",
+      "urn": "urn:pulumi:stack::project::pkgA:m:typA::a"
+    }
+  ]
+}
+`, buf.String())
+
+	// Exporting the same deployment again produces identical bytes.
+	for i := 0; i < 10; i++ {
+		var again bytes.Buffer
+		assert.NoError(t, ExportDeployment(&again, d, ExportOptions{Indent: "  "}))
+		assert.Equal(t, buf.String(), again.String())
+	}
+
+	// The default indent is four spaces.
+	buf.Reset()
+	assert.NoError(t, ExportDeployment(&buf, &apitype.DeploymentV3{}, ExportOptions{}))
+	assert.Equal(t, "{\n    \"manifest\": {\n        \"magic\": \"\",\n        \"time\": \"0001-01-01T00:00:00Z\",\n"+
+		"        \"version\": \"\"\n    }\n}\n", buf.String())
+}
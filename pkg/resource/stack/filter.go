@@ -0,0 +1,171 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// ExtractSubtree returns a deployment that contains the resource with the given URN, its transitive children, and the
+// providers that they use. References from these resources to resources outside of the extracted deployment are
+// dropped so that the result is self-consistent; the URNs of the dropped references are returned in sorted order.
+// The input deployment is not modified.
+func ExtractSubtree(d *apitype.DeploymentV3, root resource.URN) (*apitype.DeploymentV3, []resource.URN, error) {
+	contract.Require(d != nil, "d")
+
+	// Find the resources that are part of the subtree. Because a resource always appears after its parent in a
+	// deployment, a single pass is sufficient.
+	subtree := make(map[resource.URN]bool)
+	for _, res := range d.Resources {
+		if res.URN == root || subtree[res.Parent] {
+			subtree[res.URN] = true
+		}
+	}
+	if !subtree[root] {
+		return nil, nil, errors.Errorf("resource %v does not exist", root)
+	}
+
+	sub, dangling, err := filterDeployment(d, subtree)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, dangling, nil
+}
+
+// FilterOptions controls the behavior of FilterDeployment.
+type FilterOptions struct {
+	// KeepOrphans retains resources that are selected by the filter even if their parents are not. The parent
+	// references of such resources are dropped. By default, a resource whose parent is excluded is excluded as well.
+	KeepOrphans bool
+}
+
+// FilterDeployment returns a deployment that contains the resources of the given deployment for which pred returns
+// true, along with the providers that they use. Resources are selected by URN, so if pred returns true for any
+// resource with a given URN, every resource with that URN is retained. References from the retained resources to
+// resources outside of the filtered deployment are dropped so that the result is self-consistent. The input
+// deployment is not modified.
+func FilterDeployment(d *apitype.DeploymentV3, pred func(res apitype.ResourceV3) bool,
+	opts FilterOptions) (*apitype.DeploymentV3, error) {
+
+	contract.Require(d != nil, "d")
+	contract.Require(pred != nil, "pred")
+
+	// As with ExtractSubtree, a resource's parent always precedes it, so a single pass is sufficient to exclude
+	// orphans.
+	selected := make(map[resource.URN]bool)
+	for _, res := range d.Resources {
+		if pred(res) && (opts.KeepOrphans || res.Parent == "" || selected[res.Parent]) {
+			selected[res.URN] = true
+		}
+	}
+
+	filtered, _, err := filterDeployment(d, selected)
+	return filtered, err
+}
+
+// FilterByURNPrefix returns a deployment that contains the resources of the given deployment whose URNs begin with
+// the given prefix. It is otherwise the same as FilterDeployment.
+func FilterByURNPrefix(d *apitype.DeploymentV3, prefix string, opts FilterOptions) (*apitype.DeploymentV3, error) {
+	return FilterDeployment(d, func(res apitype.ResourceV3) bool {
+		return strings.HasPrefix(string(res.URN), prefix)
+	}, opts)
+}
+
+// filterDeployment returns a deployment that contains the selected resources of the given deployment and the
+// providers that they use, along with the sorted URNs of the references that were dropped because they refer to
+// resources that were not retained.
+func filterDeployment(d *apitype.DeploymentV3,
+	selected map[resource.URN]bool) (*apitype.DeploymentV3, []resource.URN, error) {
+
+	// Include the providers used by the selected resources.
+	included := make(map[resource.URN]bool)
+	for urn := range selected {
+		included[urn] = true
+	}
+	for _, res := range d.Resources {
+		if !selected[res.URN] || res.Provider == "" {
+			continue
+		}
+		ref, err := providers.ParseReference(res.Provider)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "parsing provider reference for resource %v", res.URN)
+		}
+		included[ref.URN()] = true
+	}
+
+	dangling := make(map[resource.URN]bool)
+	filterURNs := func(urns []resource.URN) []resource.URN {
+		var result []resource.URN
+		for _, urn := range urns {
+			if included[urn] {
+				result = append(result, urn)
+			} else {
+				dangling[urn] = true
+			}
+		}
+		return result
+	}
+
+	var resources []apitype.ResourceV3
+	for _, res := range d.Resources {
+		if !included[res.URN] {
+			continue
+		}
+
+		if res.Parent != "" && !included[res.Parent] {
+			dangling[res.Parent] = true
+			res.Parent = ""
+		}
+		res.Dependencies = filterURNs(res.Dependencies)
+		if res.PropertyDependencies != nil {
+			propertyDependencies := make(map[resource.PropertyKey][]resource.URN, len(res.PropertyDependencies))
+			for k, deps := range res.PropertyDependencies {
+				if filtered := filterURNs(deps); len(filtered) > 0 {
+					propertyDependencies[k] = filtered
+				}
+			}
+			res.PropertyDependencies = propertyDependencies
+		}
+
+		resources = append(resources, res)
+	}
+
+	var operations []apitype.OperationV2
+	for _, op := range d.PendingOperations {
+		if included[op.Resource.URN] {
+			operations = append(operations, op)
+		}
+	}
+
+	danglingURNs := make([]resource.URN, 0, len(dangling))
+	for urn := range dangling {
+		danglingURNs = append(danglingURNs, urn)
+	}
+	sort.Slice(danglingURNs, func(i, j int) bool { return danglingURNs[i] < danglingURNs[j] })
+
+	return &apitype.DeploymentV3{
+		Manifest:          d.Manifest,
+		SecretsProviders:  d.SecretsProviders,
+		Resources:         resources,
+		PendingOperations: operations,
+	}, danglingURNs, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestExtractSubtree(t *testing.T) {
+	provider := testResourceV3("provider")
+	provider.Type = "pulumi:providers:pkgA"
+	provider.ID = "provider-id"
+	provider.URN = resource.NewURN("stack", "project", "", provider.Type, "provider")
+	providerRef := string(provider.URN) + "::provider-id"
+
+	component := testResourceV3("component", "outside")
+	component.Custom = false
+	component.Parent = testURN("stack")
+
+	child := testResourceV3("child", "outside", "sibling")
+	child.Parent = component.URN
+	child.Provider = providerRef
+	child.PropertyDependencies = map[resource.PropertyKey][]resource.URN{
+		"foo": {testURN("outside")},
+		"bar": {testURN("sibling")},
+	}
+
+	sibling := testResourceV3("sibling")
+	sibling.Parent = component.URN
+
+	grandchild := testResourceV3("grandchild")
+	grandchild.Parent = child.URN
+
+	d := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{
+			testResourceV3("stack"),
+			provider,
+			testResourceV3("outside"),
+			component,
+			sibling,
+			child,
+			grandchild,
+		},
+	}
+
+	sub, dangling, err := ExtractSubtree(d, component.URN)
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{provider.URN, component.URN, sibling.URN, child.URN, grandchild.URN},
+		resourceURNs(sub.Resources))
+	assert.Equal(t, []resource.URN{testURN("outside"), testURN("stack")}, dangling)
+
+	// References to resources outside of the subtree are dropped.
+	assert.Equal(t, resource.URN(""), sub.Resources[1].Parent)
+	assert.Nil(t, sub.Resources[1].Dependencies)
+	assert.Equal(t, []resource.URN{sibling.URN}, sub.Resources[3].Dependencies)
+	assert.Equal(t, map[resource.PropertyKey][]resource.URN{"bar": {sibling.URN}}, sub.Resources[3].PropertyDependencies)
+	assert.Equal(t, providerRef, sub.Resources[3].Provider)
+
+	// The input deployment is not modified.
+	assert.Equal(t, testURN("stack"), d.Resources[3].Parent)
+	assert.Len(t, d.Resources[5].PropertyDependencies, 2)
+}
+
+func TestExtractSubtreeMissingRoot(t *testing.T) {
+	_, _, err := ExtractSubtree(&apitype.DeploymentV3{}, testURN("missing"))
+	assert.Error(t, err)
+}
+
+func TestFilterDeployment(t *testing.T) {
+	provider := testResourceV3("provider")
+	provider.Type = "pulumi:providers:pkgA"
+	provider.ID = "provider-id"
+	provider.URN = resource.NewURN("stack", "project", "", provider.Type, "provider")
+
+	component := testResourceV3("component")
+	component.Custom = false
+	child := testResourceV3("child", "component", "other")
+	child.Parent = component.URN
+	child.Provider = string(provider.URN) + "::provider-id"
+	grandchild := testResourceV3("grandchild")
+	grandchild.Parent = child.URN
+
+	d := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{provider, testResourceV3("other"), component, child, grandchild},
+	}
+	excludeComponent := func(res apitype.ResourceV3) bool { return res.URN != component.URN }
+
+	// Excluding a parent excludes its descendants by default. The providers of retained resources are kept.
+	filtered, err := FilterDeployment(d, excludeComponent, FilterOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{provider.URN, testURN("other")}, resourceURNs(filtered.Resources))
+
+	// Orphans may be kept instead, in which case their references to excluded resources are dropped.
+	filtered, err = FilterDeployment(d, excludeComponent, FilterOptions{KeepOrphans: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{provider.URN, testURN("other"), child.URN, grandchild.URN},
+		resourceURNs(filtered.Resources))
+	assert.Equal(t, resource.URN(""), filtered.Resources[2].Parent)
+	assert.Equal(t, []resource.URN{testURN("other")}, filtered.Resources[2].Dependencies)
+	assert.Equal(t, child.URN, filtered.Resources[3].Parent)
+
+	// A provider that is used by a retained resource is kept even if the filter excludes it.
+	filtered, err = FilterDeployment(d, func(res apitype.ResourceV3) bool {
+		return res.URN == child.URN
+	}, FilterOptions{KeepOrphans: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{provider.URN, child.URN}, resourceURNs(filtered.Resources))
+
+	// The input deployment is not modified.
+	assert.Equal(t, component.URN, d.Resources[3].Parent)
+	assert.Len(t, d.Resources[3].Dependencies, 2)
+}
+
+func TestFilterByURNPrefix(t *testing.T) {
+	component := resource.NewURN("stack", "project", "", "pkgA:m:component", "comp")
+	child := resource.NewURN("stack", "project", component.QualifiedType(), "pkgA:m:typA", "child")
+	d := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{
+			testResourceV3("other"),
+			{URN: component, Type: "pkgA:m:component"},
+			{URN: child, Type: "pkgA:m:typA", Parent: component},
+		},
+	}
+
+	filtered, err := FilterByURNPrefix(d, "urn:pulumi:stack::project::pkgA:m:component", FilterOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{component, child}, resourceURNs(filtered.Resources))
+	assert.Equal(t, component, filtered.Resources[1].Parent)
+}
@@ -0,0 +1,49 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build gofuzz
+
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+// FuzzProperties is a go-fuzz target that checks that property maps survive a round trip through checkpoint
+// serialization. The input is interpreted as a serialized property map in JSON form, so that the fuzzer can produce
+// nested objects, arrays, assets, archives, secrets, and resource references by way of their signatures. Build it with
+// `go-fuzz-build -func FuzzProperties` and run it with `go-fuzz`.
+func FuzzProperties(data []byte) int {
+	var serialized map[string]interface{}
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return -1
+	}
+	props, err := DeserializeProperties(serialized, config.NopDecrypter, config.NopEncrypter)
+	if err != nil {
+		return 0
+	}
+
+	roundTripped, err := RoundTripProperties(props)
+	if err != nil {
+		panic(fmt.Sprintf("round-tripping %v: %v", props, err))
+	}
+	if !reflect.DeepEqual(props, roundTripped) {
+		panic(fmt.Sprintf("round-tripping changed %v to %v", props, roundTripped))
+	}
+	return 1
+}
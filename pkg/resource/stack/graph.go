@@ -0,0 +1,214 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// Graph is a graph of the resources in a snapshot. Each resource is a node, and each reference from a resource to its
+// parent, its provider, or one of its dependencies is an edge from the referring resource to the referenced resource.
+type Graph struct {
+	// Nodes holds the graph's nodes in the order of their resources in the snapshot.
+	Nodes []*GraphNode
+
+	byURN map[resource.URN]*GraphNode
+}
+
+// GraphNode is a resource in a Graph.
+type GraphNode struct {
+	// Resource is the resource's state.
+	Resource *resource.State
+	// Out holds the edges from this resource to the resources it refers to.
+	Out []*GraphEdge
+	// In holds the edges to this resource from the resources that refer to it.
+	In []*GraphEdge
+
+	index int
+}
+
+// GraphEdge is a reference from one resource in a Graph to another.
+type GraphEdge struct {
+	// Kind is the kind of the reference.
+	Kind ReferenceKind
+	// From is the resource that holds the reference.
+	From *GraphNode
+	// To is the referenced resource.
+	To *GraphNode
+}
+
+// BuildDependencyGraph builds a Graph from the resources in the given snapshot. It returns a ReferenceError if a
+// resource refers to a resource that is not present in the snapshot. References to a URN that is shared by several
+// resources (e.g. a resource and its pending deletions) refer to the resource that is not pending deletion.
+func BuildDependencyGraph(snap *deploy.Snapshot) (*Graph, error) {
+	contract.Require(snap != nil, "snap")
+
+	g := &Graph{
+		Nodes: make([]*GraphNode, len(snap.Resources)),
+		byURN: make(map[resource.URN]*GraphNode, len(snap.Resources)),
+	}
+	for i, res := range snap.Resources {
+		node := &GraphNode{Resource: res, index: i}
+		g.Nodes[i] = node
+		if existing, has := g.byURN[res.URN]; !has || existing.Resource.Delete {
+			g.byURN[res.URN] = node
+		}
+	}
+
+	for _, node := range g.Nodes {
+		res := node.Resource
+
+		type edgeKey struct {
+			kind ReferenceKind
+			to   *GraphNode
+		}
+		seen := make(map[edgeKey]bool)
+		addEdge := func(kind ReferenceKind, urn resource.URN, target string) error {
+			to, has := g.byURN[urn]
+			if !has {
+				return ReferenceError{
+					URN:    res.URN,
+					Kind:   kind,
+					Target: target,
+					Reason: fmt.Sprintf("the %v is not present in the snapshot", kind),
+				}
+			}
+			if key := (edgeKey{kind, to}); !seen[key] {
+				seen[key] = true
+				edge := &GraphEdge{Kind: kind, From: node, To: to}
+				node.Out, to.In = append(node.Out, edge), append(to.In, edge)
+			}
+			return nil
+		}
+
+		if res.Parent != "" {
+			if err := addEdge(ParentReference, res.Parent, string(res.Parent)); err != nil {
+				return nil, err
+			}
+		}
+		if res.Provider != "" {
+			ref, err := providers.ParseReference(res.Provider)
+			if err != nil {
+				return nil, ReferenceError{
+					URN:    res.URN,
+					Kind:   ProviderReference,
+					Target: res.Provider,
+					Reason: fmt.Sprintf("the reference is malformed: %v", err),
+				}
+			}
+			if err := addEdge(ProviderReference, ref.URN(), res.Provider); err != nil {
+				return nil, err
+			}
+		}
+		for _, dep := range res.Dependencies {
+			if err := addEdge(DependencyReference, dep, string(dep)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// Node returns the node for the resource with the given URN, or nil if there is no such resource. If several
+// resources share the URN, the node for the resource that is not pending deletion is returned.
+func (g *Graph) Node(urn resource.URN) *GraphNode {
+	return g.byURN[urn]
+}
+
+// Roots returns the nodes for the resources that do not refer to any other resource, i.e. those without a parent, a
+// provider, or dependencies. The nodes are returned in snapshot order.
+func (g *Graph) Roots() []*GraphNode {
+	var roots []*GraphNode
+	for _, node := range g.Nodes {
+		if len(node.Out) == 0 {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Leaves returns the nodes for the resources that no other resource refers to. The nodes are returned in snapshot
+// order.
+func (g *Graph) Leaves() []*GraphNode {
+	var leaves []*GraphNode
+	for _, node := range g.Nodes {
+		if len(node.In) == 0 {
+			leaves = append(leaves, node)
+		}
+	}
+	return leaves
+}
+
+// TopologicalOrder returns the graph's nodes ordered such that each resource comes after every resource it refers
+// to. Ties are broken by snapshot order, so a snapshot that is already in a valid order is returned unchanged. An
+// error is returned if the references form a cycle.
+func (g *Graph) TopologicalOrder() ([]*GraphNode, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(g.Nodes))
+	order := make([]*GraphNode, 0, len(g.Nodes))
+
+	// path holds the nodes that are currently being visited.
+	var path []*GraphNode
+	var visit func(node *GraphNode) error
+	visit = func(node *GraphNode) error {
+		switch state[node.index] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for j := len(path) - 1; j >= 0; j-- {
+				if path[j] == node {
+					for _, n := range path[j:] {
+						cycle = append(cycle, string(n.Resource.URN))
+					}
+					break
+				}
+			}
+			cycle = append(cycle, string(node.Resource.URN))
+			return errors.Errorf("the snapshot's references form a cycle: %v", strings.Join(cycle, " -> "))
+		}
+		state[node.index], path = visiting, append(path, node)
+
+		for _, edge := range node.Out {
+			if err := visit(edge.To); err != nil {
+				return err
+			}
+		}
+
+		state[node.index], path = visited, path[:len(path)-1]
+		order = append(order, node)
+		return nil
+	}
+
+	for _, node := range g.Nodes {
+		if err := visit(node); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
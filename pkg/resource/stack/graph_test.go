@@ -0,0 +1,110 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func testResourceState(name string, deps ...string) *resource.State {
+	res := &resource.State{
+		URN:    testURN(name),
+		Custom: true,
+		Type:   "pkgA:m:typA",
+	}
+	for _, dep := range deps {
+		res.Dependencies = append(res.Dependencies, testURN(dep))
+	}
+	return res
+}
+
+func nodeURNs(nodes []*GraphNode) []resource.URN {
+	urns := make([]resource.URN, len(nodes))
+	for i, node := range nodes {
+		urns[i] = node.Resource.URN
+	}
+	return urns
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	providerURN := resource.NewURN("stack", "project", "", "pulumi:providers:pkgA", "prov")
+	provider := &resource.State{URN: providerURN, Custom: true, ID: "prov-id", Type: "pulumi:providers:pkgA"}
+
+	a := testResourceState("a")
+	a.Provider = string(providerURN) + "::prov-id"
+	b := testResourceState("b", "a", "a")
+	b.Parent = testURN("a")
+	c := testResourceState("c", "b")
+	d := testResourceState("d")
+
+	g, err := BuildDependencyGraph(&deploy.Snapshot{Resources: []*resource.State{provider, a, b, c, d}})
+	assert.NoError(t, err)
+
+	// Each kind of reference is an edge, and duplicate references are collapsed.
+	node := g.Node(testURN("b"))
+	if assert.NotNil(t, node) && assert.Len(t, node.Out, 2) {
+		assert.Equal(t, ParentReference, node.Out[0].Kind)
+		assert.Equal(t, DependencyReference, node.Out[1].Kind)
+		assert.Equal(t, testURN("a"), node.Out[1].To.Resource.URN)
+	}
+	if node := g.Node(providerURN); assert.NotNil(t, node) && assert.Len(t, node.In, 1) {
+		assert.Equal(t, ProviderReference, node.In[0].Kind)
+		assert.Equal(t, a, node.In[0].From.Resource)
+	}
+	assert.Nil(t, g.Node(testURN("missing")))
+
+	assert.Equal(t, []resource.URN{providerURN, testURN("d")}, nodeURNs(g.Roots()))
+	assert.Equal(t, []resource.URN{testURN("c"), testURN("d")}, nodeURNs(g.Leaves()))
+
+	// A snapshot that is already in order is unchanged, and an out-of-order snapshot is reordered.
+	order, err := g.TopologicalOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, nodeURNs(g.Nodes), nodeURNs(order))
+
+	g, err = BuildDependencyGraph(&deploy.Snapshot{Resources: []*resource.State{c, d, b, a, provider}})
+	assert.NoError(t, err)
+	order, err = g.TopologicalOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{providerURN, testURN("a"), testURN("b"), testURN("c"), testURN("d")},
+		nodeURNs(order))
+}
+
+func TestBuildDependencyGraphErrors(t *testing.T) {
+	// References to resources that are not in the snapshot are errors.
+	_, err := BuildDependencyGraph(&deploy.Snapshot{Resources: []*resource.State{testResourceState("a", "missing")}})
+	assert.EqualError(t, err, "resource urn:pulumi:stack::project::pkgA:m:typA::a has a dependency reference to "+
+		"urn:pulumi:stack::project::pkgA:m:typA::missing: the dependency is not present in the snapshot")
+
+	bad := testResourceState("a")
+	bad.Provider = "not-a-reference"
+	_, err = BuildDependencyGraph(&deploy.Snapshot{Resources: []*resource.State{bad}})
+	assert.Error(t, err)
+	assert.Equal(t, ProviderReference, err.(ReferenceError).Kind)
+
+	// Cycles are reported when ordering the graph.
+	g, err := BuildDependencyGraph(&deploy.Snapshot{Resources: []*resource.State{
+		testResourceState("a", "b"),
+		testResourceState("b", "a"),
+	}})
+	assert.NoError(t, err)
+	_, err = g.TopologicalOrder()
+	assert.EqualError(t, err, "the snapshot's references form a cycle: urn:pulumi:stack::project::pkgA:m:typA::a -> "+
+		"urn:pulumi:stack::project::pkgA:m:typA::b -> urn:pulumi:stack::project::pkgA:m:typA::a")
+}
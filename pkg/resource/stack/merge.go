@@ -0,0 +1,162 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// MergeOptions controls the behavior of MergeDeployments.
+type MergeOptions struct {
+	// Overwrite allows resources in the overlay deployment to replace resources in the base deployment that have the
+	// same URN. If Overwrite is false, such resources are reported as an error.
+	Overwrite bool
+}
+
+// MergeDeployments merges the resources and pending operations of the overlay deployment into those of the base
+// deployment and returns the result. Neither input is modified.
+//
+// Resources that are pending deletion are always retained, as a deployment may legitimately contain several of them
+// with the same URN. A live resource in the overlay replaces the live resource in the base that has the same URN only
+// if opts.Overwrite is set, and only if both have the same type. The merged resources are reordered where necessary
+// so that every resource appears after its parent, its provider, and its dependencies.
+func MergeDeployments(base, overlay *apitype.DeploymentV3, opts MergeOptions) (*apitype.DeploymentV3, error) {
+	contract.Require(base != nil, "base")
+	contract.Require(overlay != nil, "overlay")
+
+	secretsProviders, err := mergeSecretsProviders(base.SecretsProviders, overlay.SecretsProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the manifest of the most recent deployment.
+	manifest := base.Manifest
+	if overlay.Manifest.Time.After(manifest.Time) {
+		manifest = overlay.Manifest
+	}
+
+	resources := make([]apitype.ResourceV3, 0, len(base.Resources)+len(overlay.Resources))
+	live := make(map[resource.URN]int)
+	for _, res := range base.Resources {
+		if !res.Delete {
+			live[res.URN] = len(resources)
+		}
+		resources = append(resources, res)
+	}
+	for _, res := range overlay.Resources {
+		if !res.Delete {
+			if i, has := live[res.URN]; has {
+				if !opts.Overwrite {
+					return nil, errors.Errorf("resource %v is present in both deployments", res.URN)
+				}
+				if prev := resources[i].Type; prev != res.Type {
+					return nil, errors.Errorf("resource %v has type %v in the base deployment but %v in the overlay",
+						res.URN, prev, res.Type)
+				}
+				resources[i] = res
+				continue
+			}
+			live[res.URN] = len(resources)
+		}
+		resources = append(resources, res)
+	}
+
+	sorted, err := sortResources(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []apitype.OperationV2
+	operations = append(operations, base.PendingOperations...)
+	operations = append(operations, overlay.PendingOperations...)
+
+	return &apitype.DeploymentV3{
+		Manifest:          manifest,
+		SecretsProviders:  secretsProviders,
+		Resources:         sorted,
+		PendingOperations: operations,
+	}, nil
+}
+
+// mergeSecretsProviders returns the secrets provider to use for a merged deployment. Secret values are encrypted
+// by their deployment's secrets provider, so deployments that use different secrets providers cannot be merged.
+func mergeSecretsProviders(base, overlay *apitype.SecretsProvidersV1) (*apitype.SecretsProvidersV1, error) {
+	switch {
+	case base == nil:
+		return overlay, nil
+	case overlay == nil:
+		return base, nil
+	case base.Type != overlay.Type || !bytes.Equal(base.State, overlay.State):
+		return nil, errors.New("cannot merge deployments that use different secrets providers")
+	default:
+		return base, nil
+	}
+}
+
+// sortResources orders the given resources such that each resource appears after the resources it refers to. The
+// relative order of the resources is otherwise preserved. References to resources that are not present are
+// ignored.
+func sortResources(resources []apitype.ResourceV3) ([]apitype.ResourceV3, error) {
+	// Determine the index of the live resource that each URN refers to.
+	live := make(map[resource.URN]int)
+	for i, res := range resources {
+		if !res.Delete {
+			live[res.URN] = i
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(resources))
+	sorted := make([]apitype.ResourceV3, 0, len(resources))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("resource %v participates in a dependency cycle", resources[i].URN)
+		}
+		state[i] = visiting
+
+		for _, urn := range resourceReferences(resources[i]) {
+			if j, has := live[urn]; has && j != i {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[i] = visited
+		sorted = append(sorted, resources[i])
+		return nil
+	}
+
+	for i := range resources {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
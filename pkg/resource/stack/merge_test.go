@@ -0,0 +1,121 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func testURN(name string) resource.URN {
+	return resource.NewURN("stack", "project", "", "pkgA:m:typA", tokens.QName(name))
+}
+
+func testResourceV3(name string, deps ...string) apitype.ResourceV3 {
+	res := apitype.ResourceV3{
+		URN:    testURN(name),
+		Custom: true,
+		Type:   "pkgA:m:typA",
+	}
+	for _, dep := range deps {
+		res.Dependencies = append(res.Dependencies, testURN(dep))
+	}
+	return res
+}
+
+func resourceURNs(resources []apitype.ResourceV3) []resource.URN {
+	urns := make([]resource.URN, len(resources))
+	for i, res := range resources {
+		urns[i] = res.URN
+	}
+	return urns
+}
+
+func TestMergeDeploymentsDisjoint(t *testing.T) {
+	now := time.Now()
+	base := &apitype.DeploymentV3{
+		Manifest:  apitype.ManifestV1{Time: now},
+		Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b", "a")},
+	}
+	overlay := &apitype.DeploymentV3{
+		Manifest:  apitype.ManifestV1{Time: now.Add(time.Minute)},
+		Resources: []apitype.ResourceV3{testResourceV3("c", "b")},
+	}
+
+	merged, err := MergeDeployments(base, overlay, MergeOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{testURN("a"), testURN("b"), testURN("c")}, resourceURNs(merged.Resources))
+	assert.Equal(t, overlay.Manifest, merged.Manifest)
+}
+
+func TestMergeDeploymentsConflict(t *testing.T) {
+	base := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a")}}
+	overlay := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a")}}
+
+	_, err := MergeDeployments(base, overlay, MergeOptions{})
+	assert.Error(t, err)
+}
+
+func TestMergeDeploymentsOverwrite(t *testing.T) {
+	// The overlay's copy of "a" depends on "c", so "c" must be moved ahead of it.
+	base := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b", "a")}}
+	overlay := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("c"), testResourceV3("a", "c")}}
+
+	merged, err := MergeDeployments(base, overlay, MergeOptions{Overwrite: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.URN{testURN("c"), testURN("a"), testURN("b")}, resourceURNs(merged.Resources))
+	assert.Equal(t, []resource.URN{testURN("c")}, merged.Resources[1].Dependencies)
+}
+
+func TestMergeDeploymentsConflictingTypes(t *testing.T) {
+	retyped := testResourceV3("a")
+	retyped.Type = "pkgA:m:typB"
+	base := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a")}}
+	overlay := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{retyped}}
+
+	_, err := MergeDeployments(base, overlay, MergeOptions{Overwrite: true})
+	assert.EqualError(t, err, "resource urn:pulumi:stack::project::pkgA:m:typA::a has type pkgA:m:typA in the base "+
+		"deployment but pkgA:m:typB in the overlay")
+}
+
+func TestMergeDeploymentsPendingDeletes(t *testing.T) {
+	deleted := testResourceV3("a")
+	deleted.Delete = true
+
+	base := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a"), deleted}}
+	overlay := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{deleted}}
+
+	merged, err := MergeDeployments(base, overlay, MergeOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, merged.Resources, 3)
+}
+
+func TestMergeDeploymentsSecretsProviders(t *testing.T) {
+	base := &apitype.DeploymentV3{SecretsProviders: &apitype.SecretsProvidersV1{Type: "b64"}}
+	overlay := &apitype.DeploymentV3{SecretsProviders: &apitype.SecretsProvidersV1{Type: "passphrase"}}
+
+	_, err := MergeDeployments(base, overlay, MergeOptions{})
+	assert.Error(t, err)
+
+	merged, err := MergeDeployments(base, &apitype.DeploymentV3{}, MergeOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, base.SecretsProviders, merged.SecretsProviders)
+}
@@ -0,0 +1,237 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// ResourceKey identifies a resource within a deployment. Live resources are identified by their URN alone. Resources
+// that are pending deletion may share a URN with other resources, so they are additionally identified by their ID.
+type ResourceKey struct {
+	URN    resource.URN `json:"urn" yaml:"urn"`
+	Delete bool         `json:"delete,omitempty" yaml:"delete,omitempty"`
+	ID     resource.ID  `json:"id,omitempty" yaml:"id,omitempty"`
+}
+
+// resourceKey returns the key that identifies the given resource.
+func resourceKey(res apitype.ResourceV3) ResourceKey {
+	if !res.Delete {
+		return ResourceKey{URN: res.URN}
+	}
+	return ResourceKey{URN: res.URN, Delete: true, ID: res.ID}
+}
+
+// AddedResourceV3 is a resource that a patch adds to a deployment.
+type AddedResourceV3 struct {
+	// Index is the position of the resource in the patched deployment.
+	Index int `json:"index" yaml:"index"`
+	// Resource is the state of the added resource.
+	Resource apitype.ResourceV3 `json:"resource" yaml:"resource"`
+}
+
+// DeploymentPatch describes the changes that turn one deployment into another. Resources that are unchanged between
+// the two deployments are not recorded, so a patch is typically much smaller than the deployment it produces.
+type DeploymentPatch struct {
+	// Manifest is the manifest of the patched deployment.
+	Manifest apitype.ManifestV1 `json:"manifest" yaml:"manifest"`
+	// SecretsProviders is the secrets provider configuration of the patched deployment, if any.
+	SecretsProviders *apitype.SecretsProvidersV1 `json:"secrets_providers,omitempty" yaml:"secrets_providers,omitempty"`
+	// Removed lists the resources that are removed from the deployment.
+	Removed []ResourceKey `json:"removed,omitempty" yaml:"removed,omitempty"`
+	// Updated holds the new states of the resources that changed.
+	Updated []apitype.ResourceV3 `json:"updated,omitempty" yaml:"updated,omitempty"`
+	// Added holds the resources that are added to the deployment, ordered by index.
+	Added []AddedResourceV3 `json:"added,omitempty" yaml:"added,omitempty"`
+	// Order, if non-nil, lists every resource of the patched deployment in order. It is only recorded if the relative
+	// order of the resources that are present in both deployments changed.
+	Order []ResourceKey `json:"order,omitempty" yaml:"order,omitempty"`
+	// PendingOperations are the pending operations of the patched deployment.
+	PendingOperations []apitype.OperationV2 `json:"pending_operations,omitempty" yaml:"pending_operations,omitempty"`
+}
+
+// indexResources maps the key of each of the given resources to its index. Keys must be unique.
+func indexResources(resources []apitype.ResourceV3) (map[ResourceKey]int, error) {
+	index := make(map[ResourceKey]int, len(resources))
+	for i, res := range resources {
+		key := resourceKey(res)
+		if _, has := index[key]; has {
+			return nil, errors.Errorf("resource %v appears more than once in the deployment", res.URN)
+		}
+		index[key] = i
+	}
+	return index, nil
+}
+
+// DiffDeployments computes a patch that, when applied to the old deployment using ApplyPatch, produces the new
+// deployment. Neither input is modified.
+func DiffDeployments(old, new *apitype.DeploymentV3) (*DeploymentPatch, error) {
+	contract.Require(old != nil, "old")
+	contract.Require(new != nil, "new")
+
+	oldIndex, err := indexResources(old.Resources)
+	if err != nil {
+		return nil, err
+	}
+	newIndex, err := indexResources(new.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &DeploymentPatch{
+		Manifest:          new.Manifest,
+		SecretsProviders:  new.SecretsProviders,
+		PendingOperations: new.PendingOperations,
+	}
+
+	// Removed resources are those of the old deployment that are not present in the new one.
+	for _, res := range old.Resources {
+		if key := resourceKey(res); !hasKey(newIndex, key) {
+			patch.Removed = append(patch.Removed, key)
+		}
+	}
+
+	// Walk the new deployment, recording added and updated resources. The resources that are present in both
+	// deployments must appear in the same relative order for the added resources' indices to be sufficient.
+	reordered, last := false, -1
+	for i, res := range new.Resources {
+		j, has := oldIndex[resourceKey(res)]
+		if !has {
+			patch.Added = append(patch.Added, AddedResourceV3{Index: i, Resource: res})
+			continue
+		}
+		if j < last {
+			reordered = true
+		}
+		last = j
+
+		if !reflect.DeepEqual(old.Resources[j], res) {
+			patch.Updated = append(patch.Updated, res)
+		}
+	}
+
+	if reordered {
+		patch.Order = make([]ResourceKey, len(new.Resources))
+		for i, res := range new.Resources {
+			patch.Order[i] = resourceKey(res)
+		}
+	}
+
+	return patch, nil
+}
+
+// ApplyPatch applies a patch computed by DiffDeployments to a deployment and returns the result. The patch must have
+// been computed against the given base deployment. Neither input is modified.
+func ApplyPatch(base *apitype.DeploymentV3, patch *DeploymentPatch) (*apitype.DeploymentV3, error) {
+	contract.Require(base != nil, "base")
+	contract.Require(patch != nil, "patch")
+
+	index, err := indexResources(base.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the base resources, dropping any that the patch removes and replacing any that it updates.
+	removed := make(map[ResourceKey]bool, len(patch.Removed))
+	for _, key := range patch.Removed {
+		if !hasKey(index, key) {
+			return nil, errors.Errorf("cannot remove resource %v: it is not present in the deployment", key.URN)
+		}
+		removed[key] = true
+	}
+	updated := make(map[ResourceKey]apitype.ResourceV3, len(patch.Updated))
+	for _, res := range patch.Updated {
+		key := resourceKey(res)
+		if !hasKey(index, key) || removed[key] {
+			return nil, errors.Errorf("cannot update resource %v: it is not present in the deployment", res.URN)
+		}
+		updated[key] = res
+	}
+
+	resources := make([]apitype.ResourceV3, 0, len(base.Resources)-len(removed)+len(patch.Added))
+	for _, res := range base.Resources {
+		key := resourceKey(res)
+		if removed[key] {
+			continue
+		}
+		if u, has := updated[key]; has {
+			res = u
+		}
+		resources = append(resources, res)
+	}
+
+	// Insert the added resources at their final positions. The indices are ascending, so inserting them in order
+	// places each one correctly relative to those that precede it.
+	added := append([]AddedResourceV3(nil), patch.Added...)
+	sort.SliceStable(added, func(i, j int) bool { return added[i].Index < added[j].Index })
+	for _, a := range added {
+		if key := resourceKey(a.Resource); hasKey(index, key) && !removed[key] {
+			return nil, errors.Errorf("cannot add resource %v: it is already present in the deployment", a.Resource.URN)
+		}
+		if a.Index < 0 || a.Index > len(resources) {
+			return nil, errors.Errorf("cannot add resource %v at index %d", a.Resource.URN, a.Index)
+		}
+		resources = append(resources, apitype.ResourceV3{})
+		copy(resources[a.Index+1:], resources[a.Index:])
+		resources[a.Index] = a.Resource
+	}
+
+	if patch.Order != nil {
+		if resources, err = reorderResources(resources, patch.Order); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apitype.DeploymentV3{
+		Manifest:          patch.Manifest,
+		SecretsProviders:  patch.SecretsProviders,
+		Resources:         resources,
+		PendingOperations: patch.PendingOperations,
+	}, nil
+}
+
+// reorderResources returns the given resources in the given order, which must list each resource exactly once.
+func reorderResources(resources []apitype.ResourceV3, order []ResourceKey) ([]apitype.ResourceV3, error) {
+	if len(order) != len(resources) {
+		return nil, errors.Errorf("patch orders %d resources, but the patched deployment has %d",
+			len(order), len(resources))
+	}
+
+	index, err := indexResources(resources)
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]apitype.ResourceV3, len(order))
+	for i, key := range order {
+		j, has := index[key]
+		if !has {
+			return nil, errors.Errorf("patch orders resource %v, which is not present in the patched deployment",
+				key.URN)
+		}
+		ordered[i] = resources[j]
+	}
+	return ordered, nil
+}
+
+func hasKey(index map[ResourceKey]int, key ResourceKey) bool {
+	_, has := index[key]
+	return has
+}
@@ -0,0 +1,124 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func assertPatchRoundTrips(t *testing.T, old, new *apitype.DeploymentV3) *DeploymentPatch {
+	patch, err := DiffDeployments(old, new)
+	assert.NoError(t, err)
+
+	patched, err := ApplyPatch(old, patch)
+	assert.NoError(t, err)
+	assert.Equal(t, new, patched)
+	return patch
+}
+
+func TestDiffDeploymentsUnchanged(t *testing.T) {
+	d := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b", "a")}}
+
+	patch := assertPatchRoundTrips(t, d, d)
+	assert.Empty(t, patch.Removed)
+	assert.Empty(t, patch.Updated)
+	assert.Empty(t, patch.Added)
+	assert.Nil(t, patch.Order)
+}
+
+func TestDiffDeployments(t *testing.T) {
+	now := time.Now()
+	old := &apitype.DeploymentV3{
+		Manifest: apitype.ManifestV1{Time: now},
+		Resources: []apitype.ResourceV3{
+			testResourceV3("a"),
+			testResourceV3("b", "a"),
+			testResourceV3("c", "b"),
+		},
+	}
+
+	updated := testResourceV3("c", "a")
+	updated.Outputs = map[string]interface{}{"foo": "bar"}
+	new := &apitype.DeploymentV3{
+		Manifest: apitype.ManifestV1{Time: now.Add(time.Minute)},
+		Resources: []apitype.ResourceV3{
+			testResourceV3("a"),
+			testResourceV3("d", "a"),
+			updated,
+			testResourceV3("e"),
+		},
+		PendingOperations: []apitype.OperationV2{
+			{Resource: testResourceV3("e"), Type: apitype.OperationTypeCreating},
+		},
+	}
+
+	patch := assertPatchRoundTrips(t, old, new)
+	assert.Equal(t, []ResourceKey{{URN: testURN("b")}}, patch.Removed)
+	assert.Equal(t, []apitype.ResourceV3{updated}, patch.Updated)
+	assert.Equal(t, []AddedResourceV3{
+		{Index: 1, Resource: testResourceV3("d", "a")},
+		{Index: 3, Resource: testResourceV3("e")},
+	}, patch.Added)
+	assert.Nil(t, patch.Order)
+}
+
+func TestDiffDeploymentsReordered(t *testing.T) {
+	old := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b")}}
+	new := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("b"), testResourceV3("c"), testResourceV3("a", "b")},
+	}
+
+	patch := assertPatchRoundTrips(t, old, new)
+	assert.Equal(t, []ResourceKey{{URN: testURN("b")}, {URN: testURN("c")}, {URN: testURN("a")}}, patch.Order)
+}
+
+func TestDiffDeploymentsPendingDeletes(t *testing.T) {
+	deleted1, deleted2 := testResourceV3("a"), testResourceV3("a")
+	deleted1.Delete, deleted1.ID = true, "id1"
+	deleted2.Delete, deleted2.ID = true, "id2"
+
+	old := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{deleted1, testResourceV3("a")}}
+	new := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{deleted1, deleted2, testResourceV3("a")}}
+
+	patch := assertPatchRoundTrips(t, old, new)
+	assert.Equal(t, []AddedResourceV3{{Index: 1, Resource: deleted2}}, patch.Added)
+
+	// Resources are identified by their keys, so duplicates cannot be diffed.
+	_, err := DiffDeployments(old, &apitype.DeploymentV3{Resources: []apitype.ResourceV3{deleted1, deleted1}})
+	assert.Error(t, err)
+}
+
+func TestApplyPatchMismatchedBase(t *testing.T) {
+	old := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b")}}
+	new := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("b", "c"), testResourceV3("c")}}
+
+	patch, err := DiffDeployments(old, new)
+	assert.NoError(t, err)
+
+	// "a" is missing from this base, so it cannot be removed.
+	_, err = ApplyPatch(&apitype.DeploymentV3{Resources: []apitype.ResourceV3{testResourceV3("b")}}, patch)
+	assert.Error(t, err)
+
+	// "c" is already present in this base, so it cannot be added.
+	_, err = ApplyPatch(&apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{testResourceV3("a"), testResourceV3("b"), testResourceV3("c")},
+	}, patch)
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// resourceReferences returns the URNs of the parent, provider, and dependencies of the given resource.
+func resourceReferences(res apitype.ResourceV3) []resource.URN {
+	var refs []resource.URN
+	if res.Parent != "" {
+		refs = append(refs, res.Parent)
+	}
+	if res.Provider != "" {
+		if ref, err := providers.ParseReference(res.Provider); err == nil {
+			refs = append(refs, ref.URN())
+		}
+	}
+	refs = append(refs, res.Dependencies...)
+
+	keys := make([]string, 0, len(res.PropertyDependencies))
+	for k := range res.PropertyDependencies {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		refs = append(refs, res.PropertyDependencies[resource.PropertyKey(k)]...)
+	}
+	return refs
+}
+
+// referenceCycle returns the URNs of a set of resources whose references form a cycle, starting and ending with the
+// same URN. If the references are acyclic, referenceCycle returns nil. References to resources that are not present
+// are ignored.
+func referenceCycle(resources []apitype.ResourceV3) []resource.URN {
+	live := make(map[resource.URN]int)
+	for i, res := range resources {
+		if !res.Delete {
+			live[res.URN] = i
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(resources))
+
+	// path holds the indices of the resources that are currently being visited.
+	var path []int
+	var visit func(i int) []resource.URN
+	visit = func(i int) []resource.URN {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []resource.URN
+			for j := len(path) - 1; j >= 0; j-- {
+				if path[j] == i {
+					for _, k := range path[j:] {
+						cycle = append(cycle, resources[k].URN)
+					}
+					break
+				}
+			}
+			return append(cycle, resources[i].URN)
+		}
+		state[i], path = visiting, append(path, i)
+
+		for _, urn := range resourceReferences(resources[i]) {
+			if j, has := live[urn]; has {
+				if cycle := visit(j); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		state[i], path = visited, path[:len(path)-1]
+		return nil
+	}
+
+	for i := range resources {
+		if cycle := visit(i); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// ReferenceKind describes the way in which one resource refers to another.
+type ReferenceKind string
+
+const (
+	// ParentReference is a reference from a resource to its parent.
+	ParentReference ReferenceKind = "parent"
+	// ProviderReference is a reference from a resource to its provider.
+	ProviderReference ReferenceKind = "provider"
+	// DependencyReference is a reference from a resource to one of its dependencies.
+	DependencyReference ReferenceKind = "dependency"
+)
+
+// ReferenceError describes a reference from a resource to another resource that is not present in its deployment.
+type ReferenceError struct {
+	// URN is the URN of the resource that holds the reference.
+	URN resource.URN
+	// Kind is the kind of the reference.
+	Kind ReferenceKind
+	// Property is the property whose dependencies include the reference, if any.
+	Property resource.PropertyKey
+	// Target is the referenced URN, or for provider references, the provider reference.
+	Target string
+	// Reason describes why the reference is invalid.
+	Reason string
+}
+
+func (e ReferenceError) Error() string {
+	if e.Property != "" {
+		return fmt.Sprintf("resource %v has a %v reference via property %v to %v: %v", e.URN, e.Kind, e.Property,
+			e.Target, e.Reason)
+	}
+	return fmt.Sprintf("resource %v has a %v reference to %v: %v", e.URN, e.Kind, e.Target, e.Reason)
+}
+
+// ValidateReferences returns an error for each reference from a resource in the given deployment to a parent,
+// provider, or dependency that is not present in the deployment. Provider references must additionally refer to a
+// provider resource with the referenced ID. The errors are returned in the order of the resources that hold the
+// references.
+func ValidateReferences(d *apitype.DeploymentV3) []ReferenceError {
+	urns := make(map[resource.URN]bool)
+	providerIDs := make(map[providers.Reference]bool)
+	for _, res := range d.Resources {
+		urns[res.URN] = true
+		if providers.IsProviderType(res.Type) {
+			if ref, err := providers.NewReference(res.URN, res.ID); err == nil {
+				providerIDs[ref] = true
+			}
+		}
+	}
+
+	var errs []ReferenceError
+	for _, res := range d.Resources {
+		if res.Parent != "" && !urns[res.Parent] {
+			errs = append(errs, ReferenceError{
+				URN:    res.URN,
+				Kind:   ParentReference,
+				Target: string(res.Parent),
+				Reason: "the parent is not present in the deployment",
+			})
+		}
+
+		if res.Provider != "" {
+			reason := ""
+			ref, err := providers.ParseReference(res.Provider)
+			switch {
+			case err != nil:
+				reason = fmt.Sprintf("the reference is malformed: %v", err)
+			case !urns[ref.URN()]:
+				reason = "the provider is not present in the deployment"
+			case !providerIDs[ref]:
+				reason = fmt.Sprintf("no provider in the deployment has the ID %v", ref.ID())
+			}
+			if reason != "" {
+				errs = append(errs, ReferenceError{
+					URN:    res.URN,
+					Kind:   ProviderReference,
+					Target: res.Provider,
+					Reason: reason,
+				})
+			}
+		}
+
+		for _, dep := range res.Dependencies {
+			if !urns[dep] {
+				errs = append(errs, ReferenceError{
+					URN:    res.URN,
+					Kind:   DependencyReference,
+					Target: string(dep),
+					Reason: "the dependency is not present in the deployment",
+				})
+			}
+		}
+
+		keys := make([]string, 0, len(res.PropertyDependencies))
+		for k := range res.PropertyDependencies {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, dep := range res.PropertyDependencies[resource.PropertyKey(k)] {
+				if !urns[dep] {
+					errs = append(errs, ReferenceError{
+						URN:      res.URN,
+						Kind:     DependencyReference,
+						Property: resource.PropertyKey(k),
+						Target:   string(dep),
+						Reason:   "the dependency is not present in the deployment",
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
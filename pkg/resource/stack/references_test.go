@@ -0,0 +1,83 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestValidateReferences(t *testing.T) {
+	providerURN := resource.NewURN("stack", "project", "", "pulumi:providers:pkgA", "prov")
+	provider := apitype.ResourceV3{
+		URN:    providerURN,
+		Custom: true,
+		ID:     "prov-id",
+		Type:   "pulumi:providers:pkgA",
+	}
+
+	withProvider := func(name, ref string, deps ...string) apitype.ResourceV3 {
+		res := testResourceV3(name, deps...)
+		res.Provider = ref
+		return res
+	}
+
+	// A consistent deployment has no errors.
+	valid := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{
+		provider,
+		withProvider("a", string(providerURN)+"::prov-id"),
+		withProvider("b", string(providerURN)+"::prov-id", "a"),
+	}}
+	valid.Resources[2].Parent = testURN("a")
+	assert.Empty(t, ValidateReferences(valid))
+
+	// Dangling references of each kind are reported.
+	orphan := testResourceV3("orphan")
+	orphan.Parent = testURN("gone")
+	orphan.PropertyDependencies = map[resource.PropertyKey][]resource.URN{"foo": {testURN("missing")}}
+
+	missingProviderURN := resource.NewURN("stack", "project", "", "pulumi:providers:pkgA", tokens.QName("old"))
+	invalid := &apitype.DeploymentV3{Resources: []apitype.ResourceV3{
+		provider,
+		withProvider("a", string(providerURN)+"::replaced-id"),
+		withProvider("b", string(missingProviderURN)+"::old-id", "missing"),
+		withProvider("c", "not-a-reference"),
+		orphan,
+	}}
+	errs := ValidateReferences(invalid)
+	if assert.Len(t, errs, 6) {
+		assert.Equal(t, ReferenceError{
+			URN:    testURN("a"),
+			Kind:   ProviderReference,
+			Target: string(providerURN) + "::replaced-id",
+			Reason: "no provider in the deployment has the ID replaced-id",
+		}, errs[0])
+		assert.Equal(t, ProviderReference, errs[1].Kind)
+		assert.Equal(t, "the provider is not present in the deployment", errs[1].Reason)
+		assert.Equal(t, DependencyReference, errs[2].Kind)
+		assert.Equal(t, string(testURN("missing")), errs[2].Target)
+		assert.Equal(t, ProviderReference, errs[3].Kind)
+		assert.Contains(t, errs[3].Reason, "malformed")
+		assert.Equal(t, ParentReference, errs[4].Kind)
+		assert.EqualError(t, errs[5],
+			"resource urn:pulumi:stack::project::pkgA:m:typA::orphan has a dependency reference via property foo to "+
+				"urn:pulumi:stack::project::pkgA:m:typA::missing: the dependency is not present in the deployment")
+	}
+}
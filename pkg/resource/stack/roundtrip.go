@@ -0,0 +1,47 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+// RoundTripProperties serializes a property map as it would be written to a checkpoint, encodes and decodes the
+// result as JSON, and then deserializes it again. Secrets are written without encryption. The result should be deeply
+// equal to the input; this is intended for tests and fuzz targets that check that this is the case.
+func RoundTripProperties(props resource.PropertyMap) (resource.PropertyMap, error) {
+	serialized, err := SerializeProperties(props, config.NopEncrypter, false /* showSecrets */)
+	if err != nil {
+		return nil, errors.Wrap(err, "serializing properties")
+	}
+	b, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding properties")
+	}
+	var decoded map[string]interface{}
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		return nil, errors.Wrap(err, "decoding properties")
+	}
+	deserialized, err := DeserializeProperties(decoded, config.NopDecrypter, config.NopEncrypter)
+	if err != nil {
+		return nil, errors.Wrap(err, "deserializing properties")
+	}
+	return deserialized, nil
+}
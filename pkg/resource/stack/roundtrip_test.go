@@ -0,0 +1,67 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestRoundTripProperties(t *testing.T) {
+	text, err := resource.NewTextAsset("hello")
+	assert.NoError(t, err)
+	lib, err := resource.NewAssetArchive(map[string]interface{}{"util.js": text})
+	assert.NoError(t, err)
+	archive, err := resource.NewAssetArchive(map[string]interface{}{"index.js": text, "lib": lib})
+	assert.NoError(t, err)
+
+	cases := map[string]resource.PropertyValue{
+		"null":         resource.NewNullProperty(),
+		"integer":      resource.NewNumberProperty(math.MaxInt32),
+		"largeInteger": resource.NewNumberProperty(1 << 53),
+		"fraction":     resource.NewNumberProperty(-0.5),
+		"emptyString":  resource.NewStringProperty(""),
+		"emptyArray":   resource.NewArrayProperty([]resource.PropertyValue{}),
+		"emptyObject":  resource.NewObjectProperty(resource.PropertyMap{}),
+		"computed":     resource.MakeComputed(resource.NewStringProperty("")),
+		"asset":        resource.NewAssetProperty(text),
+		"archive":      resource.NewArchiveProperty(archive),
+		"secret":       resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"customRef":    resource.MakeCustomResourceReference("urn:pulumi:stack::project::pkgA:m:typA::a", "a-id", ""),
+		"componentRef": resource.MakeComponentResourceReference("urn:pulumi:stack::project::pkgA:m:typB::b", "1.0.0"),
+		"unknownRefID": resource.MakeCustomResourceReference("urn:pulumi:stack::project::pkgA:m:typA::c", "", ""),
+		"nestedSecrets": resource.MakeSecret(resource.NewObjectProperty(resource.PropertyMap{
+			"asset": resource.NewAssetProperty(text),
+		})),
+		"nested": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"array":  resource.NewArrayProperty([]resource.PropertyValue{resource.NewBoolProperty(false)}),
+				"secret": resource.MakeSecret(resource.NewArrayProperty([]resource.PropertyValue{})),
+			}),
+		}),
+	}
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			props := resource.PropertyMap{"value": value}
+			roundTripped, err := RoundTripProperties(props)
+			assert.NoError(t, err)
+			assert.Equal(t, props, roundTripped)
+		})
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -172,3 +176,38 @@ func TestCachingCrypter(t *testing.T) {
 	assert.Equal(t, 3, sm.encryptCalls)
 	assert.Equal(t, barSer, barSer2)
 }
+
+func TestSecretDeploymentRoundTrip(t *testing.T) {
+	secret := resource.MakeSecret(resource.NewStringProperty("hunter2"))
+	res := &resource.State{
+		Type:   tokens.Type("pkgA:m:typA"),
+		URN:    resource.URN("urn:pulumi:stack::project::pkgA:m:typA::resA"),
+		Custom: true,
+		ID:     resource.ID("id"),
+		Outputs: resource.PropertyMap{
+			"password": secret,
+			"nested":   resource.NewObjectProperty(resource.PropertyMap{"password": secret}),
+		},
+	}
+	snap := deploy.NewSnapshot(deploy.Manifest{}, b64.NewBase64SecretsManager(), []*resource.State{res}, nil)
+
+	for _, showSecrets := range []bool{false, true} {
+		dep, err := SerializeDeployment(snap, nil, showSecrets)
+		assert.NoError(t, err)
+
+		b, err := json.Marshal(dep)
+		assert.NoError(t, err)
+		assert.Equal(t, showSecrets, strings.Contains(string(b), "hunter2"))
+
+		var unmarshaled apitype.DeploymentV3
+		assert.NoError(t, json.Unmarshal(b, &unmarshaled))
+		des, err := DeserializeDeploymentV3(unmarshaled, DefaultSecretsProvider)
+		assert.NoError(t, err)
+
+		// Secret values remain secret, whether or not they were serialized in plaintext.
+		outputs := des.Resources[0].Outputs
+		assert.True(t, outputs["password"].IsSecret())
+		assert.True(t, outputs["password"].DeepEquals(secret))
+		assert.True(t, outputs["nested"].ObjectValue()["password"].DeepEquals(secret))
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ValidateSnapshot checks the resources in the given snapshot for internal consistency, e.g. before the snapshot is
+// serialized as a checkpoint. Unlike Snapshot.VerifyIntegrity, which stops at the first problem, ValidateSnapshot
+// reports every problem that it finds, in the order of the resources that have them. It checks that:
+//
+//   - at most one resource with a given URN is not pending deletion;
+//   - each resource's parent, provider, and dependencies are present and precede the resource;
+//   - each resource's provider reference refers to a provider resource with the referenced ID; and
+//   - each resource that is not pending deletion has a parent that is not pending deletion, in the same stack and
+//     project as the resource.
+//
+// If the snapshot is consistent, ValidateSnapshot returns nil.
+func ValidateSnapshot(snap *deploy.Snapshot) error {
+	if snap == nil {
+		return nil
+	}
+
+	// Index every resource by URN so that references to later resources can be told apart from references to missing
+	// resources.
+	all := make(map[resource.URN]bool, len(snap.Resources))
+	for _, res := range snap.Resources {
+		all[res.URN] = true
+	}
+
+	var result error
+	fail := func(format string, args ...interface{}) {
+		result = multierror.Append(result, errors.Errorf(format, args...))
+	}
+
+	// seen maps the URNs of the resources visited so far to whether any of them is not pending deletion.
+	seen := make(map[resource.URN]bool, len(snap.Resources))
+	provs := make(map[providers.Reference]bool)
+	for _, res := range snap.Resources {
+		urn := res.URN
+
+		if live, has := seen[urn]; has && live && !res.Delete {
+			fail("duplicate resource %s (not marked for deletion)", urn)
+		}
+
+		if res.Parent != "" {
+			switch live, has := seen[res.Parent]; {
+			case res.Parent == urn:
+				fail("resource %s is its own parent", urn)
+			case !has && all[res.Parent]:
+				fail("child resource %s's parent %s comes after it", urn, res.Parent)
+			case !has:
+				fail("child resource %s refers to missing parent %s", urn, res.Parent)
+			case !res.Delete && !live:
+				fail("child resource %s's parent %s is pending deletion", urn, res.Parent)
+			case res.Parent.Stack() != urn.Stack() || res.Parent.Project() != urn.Project():
+				fail("child resource %s's parent %s belongs to a different stack or project", urn, res.Parent)
+			}
+		}
+
+		if res.Provider != "" {
+			if ref, err := providers.ParseReference(res.Provider); err != nil {
+				fail("failed to parse provider reference for resource %s: %v", urn, err)
+			} else {
+				_, has := seen[ref.URN()]
+				switch {
+				case !has && all[ref.URN()]:
+					fail("resource %s's provider %s comes after it", urn, ref.URN())
+				case !has:
+					fail("resource %s refers to missing provider %s", urn, ref.URN())
+				case !provs[ref]:
+					fail("resource %s refers to unknown provider %s", urn, ref)
+				}
+			}
+		}
+
+		for _, dep := range res.Dependencies {
+			if _, has := seen[dep]; !has {
+				if all[dep] {
+					fail("resource %s's dependency %s comes after it", urn, dep)
+				} else {
+					fail("resource %s dependency %s refers to missing resource", urn, dep)
+				}
+			}
+		}
+
+		if providers.IsProviderType(res.Type) {
+			if ref, err := providers.NewReference(urn, res.ID); err == nil {
+				provs[ref] = true
+			} else {
+				fail("provider %s is not referenceable: %v", urn, err)
+			}
+		}
+		seen[urn] = seen[urn] || !res.Delete
+	}
+
+	return result
+}
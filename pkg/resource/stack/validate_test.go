@@ -0,0 +1,85 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestValidateSnapshot(t *testing.T) {
+	providerURN := resource.NewURN("stack", "project", "", "pulumi:providers:pkgA", "prov")
+	provider := &resource.State{URN: providerURN, Custom: true, ID: "prov-id", Type: "pulumi:providers:pkgA"}
+
+	withProvider := func(res *resource.State, id string) *resource.State {
+		res.Provider = string(providerURN) + "::" + id
+		return res
+	}
+	withParent := func(res *resource.State, parent string) *resource.State {
+		res.Parent = testURN(parent)
+		return res
+	}
+	pendingDelete := func(res *resource.State) *resource.State {
+		res.Delete = true
+		return res
+	}
+
+	// A consistent snapshot, including a resource with pending deletions, is valid.
+	assert.NoError(t, ValidateSnapshot(&deploy.Snapshot{Resources: []*resource.State{
+		provider,
+		withProvider(testResourceState("a"), "prov-id"),
+		pendingDelete(testResourceState("b")),
+		pendingDelete(testResourceState("b")),
+		withParent(testResourceState("b", "a"), "a"),
+	}}))
+	assert.NoError(t, ValidateSnapshot(nil))
+
+	// Every problem is reported.
+	otherStackURN := resource.NewURN("other", "project", "", "pkgA:m:typA", "x")
+	err := ValidateSnapshot(&deploy.Snapshot{Resources: []*resource.State{
+		provider,
+		testResourceState("a"),
+		testResourceState("a"),
+		withProvider(testResourceState("b", "later"), "old-id"),
+		withParent(testResourceState("c"), "missing"),
+		pendingDelete(testResourceState("deleted")),
+		withParent(testResourceState("d"), "deleted"),
+		{URN: otherStackURN, Type: "pkgA:m:typA"},
+		{URN: testURN("e"), Type: "pkgA:m:typA", Parent: otherStackURN},
+		testResourceState("later"),
+	}})
+	if assert.Error(t, err) {
+		merr, ok := err.(*multierror.Error)
+		if assert.True(t, ok) && assert.Len(t, merr.Errors, 6) {
+			assert.EqualError(t, merr.Errors[0],
+				"duplicate resource urn:pulumi:stack::project::pkgA:m:typA::a (not marked for deletion)")
+			assert.EqualError(t, merr.Errors[1], "resource urn:pulumi:stack::project::pkgA:m:typA::b refers to "+
+				"unknown provider urn:pulumi:stack::project::pulumi:providers:pkgA::prov::old-id")
+			assert.EqualError(t, merr.Errors[2], "resource urn:pulumi:stack::project::pkgA:m:typA::b's dependency "+
+				"urn:pulumi:stack::project::pkgA:m:typA::later comes after it")
+			assert.EqualError(t, merr.Errors[3], "child resource urn:pulumi:stack::project::pkgA:m:typA::c refers to "+
+				"missing parent urn:pulumi:stack::project::pkgA:m:typA::missing")
+			assert.EqualError(t, merr.Errors[4], "child resource urn:pulumi:stack::project::pkgA:m:typA::d's parent "+
+				"urn:pulumi:stack::project::pkgA:m:typA::deleted is pending deletion")
+			assert.EqualError(t, merr.Errors[5], "child resource urn:pulumi:stack::project::pkgA:m:typA::e's parent "+
+				"urn:pulumi:other::project::pkgA:m:typA::x belongs to a different stack or project")
+		}
+	}
+}
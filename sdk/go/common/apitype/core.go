@@ -134,6 +134,8 @@ const (
 	OperationTypeDeleting OperationType = "deleting"
 	// OperationTypeReading is the state of resources that are being read.
 	OperationTypeReading OperationType = "reading"
+	// OperationTypeImporting is the state of resources that are being imported.
+	OperationTypeImporting OperationType = "importing"
 )
 
 // OperationV1 represents an operation that the engine is performing. It consists of a Resource, which is the state
@@ -204,7 +206,7 @@ type ResourceV1 struct {
 //      associated with this resource.
 //
 // Migrating from ResourceV1 to ResourceV2 involves:
-//  1. Dropping the `Defaults` field (it should be empty anyway)
+//  1. Merging the `Defaults` field into the `Inputs` field, with the inputs taking precedence
 //  2. Setting the `External` field to "false", since a ResourceV1 existing for a resource
 //     implies that it is owned by Pulumi. Note that since this is the default value for
 //     booleans in Go, no explicit assignment needs to be made.
@@ -289,6 +291,10 @@ type ResourceV3 struct {
 	CustomTimeouts *resource.CustomTimeouts `json:"customTimeouts,omitempty" yaml:"customTimeouts,omitempty"`
 	// ImportID is the import input used for imported resources.
 	ImportID resource.ID `json:"importID,omitempty" yaml:"importID,omitempty"`
+	// Created tracks when the resource was created, if known.
+	Created *time.Time `json:"created,omitempty" yaml:"created,omitempty"`
+	// Modified tracks when the resource was last modified, if known.
+	Modified *time.Time `json:"modified,omitempty" yaml:"modified,omitempty"`
 }
 
 // ManifestV1 captures meta-information about this checkpoint file, such as versions of binaries, etc.
@@ -318,6 +324,8 @@ type SecretV1 struct {
 	Sig        string `json:"4dabf18193072939515e22adb298388d" yaml:"4dabf18193072939515e22adb298388d"`
 	Ciphertext string `json:"ciphertext,omitempty" yaml:"ciphertext,omitempty"`
 	Plaintext  string `json:"plaintext,omitempty" yaml:"plaintext,omitempty"`
+	// Version is the version of the envelope format. Envelopes without a version are treated as version 1.
+	Version int `json:"version,omitempty" yaml:"version,omitempty"`
 }
 
 // ConfigValue describes a single (possibly secret) configuration value.
@@ -28,10 +28,15 @@ func UpToResourceV2(v1 apitype.ResourceV1) apitype.ResourceV2 {
 	v2.ID = v1.ID
 	v2.Type = v1.Type
 	v2.Inputs = make(map[string]interface{})
+	// v1.Defaults was deprecated in v2. Older versions of Pulumi recorded the provider's default values separately
+	// from the program's inputs; later versions merge the two, so fold any defaults into the inputs, giving
+	// precedence to the inputs.
+	for key, value := range v1.Defaults {
+		v2.Inputs[key] = value
+	}
 	for key, value := range v1.Inputs {
 		v2.Inputs[key] = value
 	}
-	// v1.Defaults was deprecated in v2.
 	v2.Outputs = make(map[string]interface{})
 	for key, value := range v1.Outputs {
 		v2.Outputs[key] = value
@@ -31,10 +31,12 @@ func TestV1ToV2(t *testing.T) {
 		ID:     resource.ID("bar"),
 		Type:   tokens.Type("special"),
 		Inputs: map[string]interface{}{
-			"foo_in": "baz",
+			"foo_in":   "baz",
+			"foo_both": "input",
 		},
 		Defaults: map[string]interface{}{
 			"foo_default": "stuff",
+			"foo_both":    "default",
 		},
 		Outputs: map[string]interface{}{
 			"foo_out": "out",
@@ -54,7 +56,9 @@ func TestV1ToV2(t *testing.T) {
 	assert.Equal(t, resource.ID("bar"), v2.ID)
 	assert.Equal(t, tokens.Type("special"), v2.Type)
 	assert.Equal(t, map[string]interface{}{
-		"foo_in": "baz",
+		"foo_in":      "baz",
+		"foo_default": "stuff",
+		"foo_both":    "input",
 	}, v2.Inputs)
 	assert.Equal(t, map[string]interface{}{
 		"foo_out": "out",
@@ -32,7 +32,9 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 
@@ -284,7 +286,7 @@ func DeserializeAsset(obj map[string]interface{}) (*Asset, bool, error) {
 		uri = u
 	}
 
-	return &Asset{Hash: hash, Text: text, Path: path, URI: uri}, true, nil
+	return &Asset{Sig: AssetSig, Hash: hash, Text: text, Path: path, URI: uri}, true, nil
 }
 
 // HasContents indicates whether or not an asset's contents can be read.
@@ -377,10 +379,53 @@ func (a *Asset) readURI() (*Blob, error) {
 		}
 		return NewFileBlob(f)
 	default:
-		return nil, errors.Errorf("Unrecognized or unsupported URI scheme: %v", s)
+		rc, err := resolveURI(url)
+		if err != nil {
+			return nil, err
+		}
+		return NewReadCloserBlob(rc)
 	}
 }
 
+// URIResolver fetches the contents of an asset or archive from a URI.
+type URIResolver func(url *url.URL) (io.ReadCloser, error)
+
+var (
+	uriResolversLock sync.RWMutex
+	uriResolvers     = map[string]URIResolver{}
+)
+
+// RegisterURIResolver registers a resolver for URIs with the given scheme (e.g. "s3"). Assets and archives whose URIs
+// use a scheme other than file, http, or https are fetched using the resolver registered for that scheme. Registering
+// a resolver for a scheme that already has one replaces it; registering a nil resolver removes it.
+func RegisterURIResolver(scheme string, resolver URIResolver) {
+	uriResolversLock.Lock()
+	defer uriResolversLock.Unlock()
+
+	scheme = strings.ToLower(scheme)
+	if resolver == nil {
+		delete(uriResolvers, scheme)
+	} else {
+		uriResolvers[scheme] = resolver
+	}
+}
+
+// resolveURI fetches the contents of a URI using the resolver registered for its scheme.
+func resolveURI(url *url.URL) (io.ReadCloser, error) {
+	uriResolversLock.RLock()
+	resolver, has := uriResolvers[strings.ToLower(url.Scheme)]
+	uriResolversLock.RUnlock()
+
+	if !has {
+		return nil, errors.Errorf("no resolver is registered for URI scheme '%v'", url.Scheme)
+	}
+	rc, err := resolver(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %v", url)
+	}
+	return rc, nil
+}
+
 // EnsureHash computes the SHA256 hash of the asset's contents and stores it on the object.
 func (a *Asset) EnsureHash() error {
 	if a.Hash == "" {
@@ -483,6 +528,32 @@ func NewAssetArchive(assets map[string]interface{}) (*Archive, error) {
 	return a, err
 }
 
+// NewBytesArchive creates an archive from a map of file paths to file contents, e.g. files that were generated in
+// memory, without requiring the files to be written to disk. Each file becomes a text asset within the archive, so the
+// contents of each file must be valid UTF-8. The archive's hash and serialized forms depend only on its paths and
+// contents.
+func NewBytesArchive(files map[string][]byte) (*Archive, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	assets := make(map[string]interface{}, len(files))
+	for _, path := range paths {
+		contents := files[path]
+		if !utf8.Valid(contents) {
+			return &Archive{}, errors.Errorf("the contents of %v are not valid UTF-8", path)
+		}
+		asset, err := NewTextAsset(string(contents))
+		if err != nil {
+			return &Archive{}, err
+		}
+		assets[path] = asset
+	}
+	return NewAssetArchive(assets)
+}
+
 func NewPathArchive(path string) (*Archive, error) {
 	a := &Archive{Sig: ArchiveSig, Path: path}
 	err := a.EnsureHash()
@@ -654,7 +725,7 @@ func DeserializeArchive(obj map[string]interface{}) (*Archive, bool, error) {
 		uri = u
 	}
 
-	return &Archive{Hash: hash, Assets: assets, Path: path, URI: uri}, true, nil
+	return &Archive{Sig: ArchiveSig, Hash: hash, Assets: assets, Path: path, URI: uri}, true, nil
 }
 
 // HasContents indicates whether or not an archive's contents can be read.
@@ -917,7 +988,7 @@ func (a *Archive) openURLStream(url *url.URL) (io.ReadCloser, error) {
 		contract.Assert(url.Fragment == "")
 		return os.Open(url.Path)
 	default:
-		return nil, errors.Errorf("Unrecognized or unsupported URI scheme: %v", s)
+		return resolveURI(url)
 	}
 }
 
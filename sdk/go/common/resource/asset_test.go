@@ -18,13 +18,16 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -496,6 +499,127 @@ func TestInvalidPathArchive(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestURIResolvers(t *testing.T) {
+	RegisterURIResolver("s3", func(url *url.URL) (io.ReadCloser, error) {
+		switch url.Path {
+		case "/fox.txt":
+			return ioutil.NopCloser(strings.NewReader("the quick brown fox")), nil
+		case "/test_dir.tar":
+			return os.Open("../../../../pkg/resource/testdata/test_dir.tar")
+		default:
+			return nil, errors.New("not found")
+		}
+	})
+	defer RegisterURIResolver("s3", nil)
+
+	asset, err := NewURIAsset("s3://bucket/fox.txt")
+	assert.NoError(t, err)
+	assertAssetTextEquals(t, asset, "the quick brown fox")
+
+	arch, err := NewURIArchive("s3://bucket/test_dir.tar")
+	assert.NoError(t, err)
+	validateTestDirArchive(t, arch, 3)
+
+	// Errors from the resolver are reported along with the URI.
+	_, err = NewURIAsset("s3://bucket/missing.txt")
+	assert.EqualError(t, err, "fetching s3://bucket/missing.txt: not found")
+
+	// Schemes without a resolver are reported by name.
+	_, err = NewURIAsset("gs://bucket/fox.txt")
+	assert.EqualError(t, err, "no resolver is registered for URI scheme 'gs'")
+	_, err = NewURIArchive("gs://bucket/test_dir.tar")
+	assert.EqualError(t, err, "no resolver is registered for URI scheme 'gs'")
+}
+
+func TestArchiveSerializationIsDeterministic(t *testing.T) {
+	newArchive := func(reverse bool) *Archive {
+		assets := map[string]interface{}{}
+		for i := 0; i < 32; i++ {
+			j := i
+			if reverse {
+				j = 31 - i
+			}
+			asset, err := NewTextAsset(fmt.Sprintf("contents of %d", j))
+			assert.NoError(t, err)
+			assets[fmt.Sprintf("dir%d/file%d.txt", j%4, j)] = asset
+		}
+		arch, err := NewAssetArchive(assets)
+		assert.NoError(t, err)
+		return arch
+	}
+
+	a, b := newArchive(false), newArchive(true)
+	assert.Equal(t, a.Hash, b.Hash)
+
+	aJSON, err := json.Marshal(a.Serialize())
+	assert.NoError(t, err)
+	bJSON, err := json.Marshal(b.Serialize())
+	assert.NoError(t, err)
+	assert.Equal(t, string(aJSON), string(bJSON))
+
+	for _, format := range []ArchiveFormat{TarArchive, TarGZIPArchive, ZIPArchive} {
+		aBytes, err := a.Bytes(format)
+		assert.NoError(t, err)
+		bBytes, err := b.Bytes(format)
+		assert.NoError(t, err)
+		assert.Equal(t, aBytes, bBytes, "format %v", format)
+	}
+}
+
+func TestBytesArchive(t *testing.T) {
+	files := map[string][]byte{
+		"config.yaml":      []byte("name: test\n"),
+		"conf.d/extra.ini": []byte("[section]\nkey = value\n"),
+		"empty.txt":        {},
+	}
+	arch, err := NewBytesArchive(files)
+	assert.NoError(t, err)
+
+	// The archive is equivalent to an archive of the corresponding text assets.
+	assets := map[string]interface{}{}
+	for path, contents := range files {
+		asset, err := NewTextAsset(string(contents))
+		assert.NoError(t, err)
+		assets[path] = asset
+	}
+	expected, err := NewAssetArchive(assets)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Hash, arch.Hash)
+	assert.True(t, arch.Equals(expected))
+
+	// The archive round-trips through its serialized form.
+	b, err := json.Marshal(arch.Serialize())
+	assert.NoError(t, err)
+	var obj map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &obj))
+	deserialized, isArchive, err := DeserializeArchive(obj)
+	assert.NoError(t, err)
+	assert.True(t, isArchive)
+	assert.True(t, arch.Equals(deserialized))
+
+	// Reading the archive produces the original files.
+	r, err := deserialized.Open()
+	assert.NoError(t, err)
+	defer contract.IgnoreClose(r)
+	read := map[string][]byte{}
+	for {
+		name, blob, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		contents, err := ioutil.ReadAll(blob)
+		assert.NoError(t, err)
+		assert.NoError(t, blob.Close())
+		read[name] = contents
+	}
+	assert.Equal(t, files, read)
+
+	// Contents that are not valid UTF-8 cannot be represented as text assets.
+	_, err = NewBytesArchive(map[string][]byte{"binary": {0xff, 0xfe}})
+	assert.EqualError(t, err, "the contents of binary are not valid UTF-8")
+}
+
 func validateTestDirArchive(t *testing.T, arch *Archive, expected int) {
 	r, err := arch.Open()
 	assert.Nil(t, err)
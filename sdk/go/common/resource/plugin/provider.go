@@ -228,6 +228,7 @@ type ConstructInfo struct {
 	DryRun         bool                  // true if we are performing a dry-run (preview).
 	Parallel       int                   // the degree of parallelism for resource operations (<=1 for serial).
 	MonitorAddress string                // the RPC address to the host resource monitor.
+	Refresh        bool                  // true if the component is being constructed during a refresh.
 }
 
 // ConstructOptions captures options for a call to Construct.
@@ -242,14 +243,25 @@ type ConstructOptions struct {
 	Providers map[string]string
 	// PropertyDependencies is a map from property name to a list of resources that property depends on.
 	PropertyDependencies map[resource.PropertyKey][]resource.URN
+	// CustomTimeouts, if non-nil, holds the custom timeouts to apply to the component's children.
+	CustomTimeouts *resource.CustomTimeouts
+	// AdditionalSecretOutputs is the list of output properties that should be treated as secrets.
+	AdditionalSecretOutputs []resource.PropertyKey
+	// DeleteBeforeReplace is true if the component's children should be deleted before they are replaced.
+	DeleteBeforeReplace bool
 }
 
 // ConstructResult is the result of a call to Construct.
 type ConstructResult struct {
-	// The URN of the constructed component resource.
+	// The URN of the constructed resource.
 	URN resource.URN
+	// The ID of the constructed resource if it is a custom resource. Empty for components, and for custom resources
+	// whose ID is not yet known.
+	ID resource.ID
 	// The output properties of the component resource.
 	Outputs resource.PropertyMap
 	// The resources that each output property depends on.
 	OutputDependencies map[resource.PropertyKey][]resource.URN
+	// The references of the providers used by the component's children.
+	Providers []string
 }
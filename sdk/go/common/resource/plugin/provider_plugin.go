@@ -21,6 +21,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
@@ -1095,6 +1096,28 @@ func (p *provider) Construct(info ConstructInfo, typ tokens.Type, name tokens.QN
 		inputDependencies[string(name)] = &pulumirpc.ConstructRequest_PropertyDependencies{Urns: urns}
 	}
 
+	// Marshal the additional secret outputs.
+	additionalSecretOutputs := make([]string, len(options.AdditionalSecretOutputs))
+	for i, key := range options.AdditionalSecretOutputs {
+		additionalSecretOutputs[i] = string(key)
+	}
+
+	// Marshal the custom timeouts as duration strings.
+	var customTimeouts *pulumirpc.ConstructRequest_CustomTimeouts
+	if timeouts := options.CustomTimeouts; timeouts != nil {
+		formatTimeout := func(seconds float64) string {
+			if seconds == 0 {
+				return ""
+			}
+			return time.Duration(seconds * float64(time.Second)).String()
+		}
+		customTimeouts = &pulumirpc.ConstructRequest_CustomTimeouts{
+			Create: formatTimeout(timeouts.Create),
+			Update: formatTimeout(timeouts.Update),
+			Delete: formatTimeout(timeouts.Delete),
+		}
+	}
+
 	// Marshal the config.
 	config := map[string]string{}
 	for k, v := range info.Config {
@@ -1102,21 +1125,25 @@ func (p *provider) Construct(info ConstructInfo, typ tokens.Type, name tokens.QN
 	}
 
 	resp, err := client.Construct(p.requestContext(), &pulumirpc.ConstructRequest{
-		Project:           info.Project,
-		Stack:             info.Stack,
-		Config:            config,
-		DryRun:            info.DryRun,
-		Parallel:          int32(info.Parallel),
-		MonitorEndpoint:   info.MonitorAddress,
-		Type:              string(typ),
-		Name:              string(name),
-		Parent:            string(parent),
-		Inputs:            minputs,
-		Protect:           options.Protect,
-		Providers:         options.Providers,
-		InputDependencies: inputDependencies,
-		Aliases:           aliases,
-		Dependencies:      dependencies,
+		Project:                 info.Project,
+		Stack:                   info.Stack,
+		Config:                  config,
+		DryRun:                  info.DryRun,
+		Parallel:                int32(info.Parallel),
+		MonitorEndpoint:         info.MonitorAddress,
+		Type:                    string(typ),
+		Name:                    string(name),
+		Parent:                  string(parent),
+		Inputs:                  minputs,
+		Protect:                 options.Protect,
+		Providers:               options.Providers,
+		InputDependencies:       inputDependencies,
+		Aliases:                 aliases,
+		Dependencies:            dependencies,
+		Refresh:                 info.Refresh,
+		CustomTimeouts:          customTimeouts,
+		AdditionalSecretOutputs: additionalSecretOutputs,
+		DeleteBeforeReplace:     options.DeleteBeforeReplace,
 	})
 	if err != nil {
 		return ConstructResult{}, err
@@ -1141,11 +1168,13 @@ func (p *provider) Construct(info ConstructInfo, typ tokens.Type, name tokens.QN
 		outputDependencies[resource.PropertyKey(k)] = urns
 	}
 
-	logging.V(7).Infof("%s success: #outputs=%d", label, len(outputs))
+	logging.V(7).Infof("%s success: #outputs=%d, providers=%v", label, len(outputs), resp.GetProviders())
 	return ConstructResult{
 		URN:                resource.URN(resp.GetUrn()),
+		ID:                 resource.ID(resp.GetId()),
 		Outputs:            outputs,
 		OutputDependencies: outputDependencies,
+		Providers:          resp.GetProviders(),
 	}, nil
 }
 
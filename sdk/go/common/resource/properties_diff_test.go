@@ -15,6 +15,7 @@
 package resource
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 
@@ -308,6 +309,41 @@ func TestAssetPropertyValueDiffs(t *testing.T) {
 	assert.True(t, d3.New.IsNull())
 }
 
+func TestContentEqualAssetsDoNotDiff(t *testing.T) {
+	t.Parallel()
+
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+	defer func() { contract.IgnoreError(os.Remove(f.Name())) }()
+	_, err = f.WriteString("exports.handler = () => {};")
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	text, err := NewTextAsset("exports.handler = () => {};")
+	assert.Nil(t, err)
+	file, err := NewPathAsset(f.Name())
+	assert.Nil(t, err)
+
+	// Assets built from different sources but with the same contents are equal.
+	assert.Nil(t, NewAssetProperty(text).Diff(NewAssetProperty(file)))
+	assert.Nil(t, NewAssetProperty(file).Diff(NewAssetProperty(text)))
+
+	// The same holds once the old asset has been round-tripped through serialization, as happens when it is read
+	// back from a checkpoint.
+	serialized, isasset, err := DeserializeAsset(text.Serialize())
+	assert.Nil(t, err)
+	assert.True(t, isasset)
+	olds := PropertyMap{"code": NewAssetProperty(serialized)}
+	news := PropertyMap{"code": NewAssetProperty(file)}
+	assert.Nil(t, olds.Diff(news))
+	assert.True(t, olds.DeepEquals(news))
+
+	// A change in contents is still a diff.
+	changed, err := NewTextAsset("exports.handler = () => 42;")
+	assert.Nil(t, err)
+	assert.NotNil(t, NewAssetProperty(changed).Diff(NewAssetProperty(file)))
+}
+
 func TestArchivePropertyValueDiffs(t *testing.T) {
 	t.Parallel()
 	path, err := tempArchive("test", false)
@@ -15,6 +15,8 @@
 package resource
 
 import (
+	"time"
+
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -43,6 +45,8 @@ type State struct {
 	Aliases                 []URN                 // TODO
 	CustomTimeouts          CustomTimeouts        // A config block that will be used to configure timeouts for CRUD operations
 	ImportID                ID                    // the resource's import id, if this was an imported resource.
+	Created                 *time.Time            // when the resource was created, if known.
+	Modified                *time.Time            // when the resource was last modified, if known.
 }
 
 // NewState creates a new resource value from existing resource state information.
@@ -51,7 +55,7 @@ func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 	external bool, dependencies []URN, initErrors []string, provider string,
 	propertyDependencies map[PropertyKey][]URN, pendingReplacement bool,
 	additionalSecretOutputs []PropertyKey, aliases []URN, timeouts *CustomTimeouts,
-	importID ID) *State {
+	importID ID, created *time.Time, modified *time.Time) *State {
 
 	contract.Assertf(t != "", "type was empty")
 	contract.Assertf(custom || id == "", "is custom or had empty ID")
@@ -76,6 +80,8 @@ func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 		AdditionalSecretOutputs: additionalSecretOutputs,
 		Aliases:                 aliases,
 		ImportID:                importID,
+		Created:                 created,
+		Modified:                modified,
 	}
 
 	if timeouts != nil {
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	multierror "github.com/hashicorp/go-multierror"
@@ -42,19 +43,34 @@ var disableResourceReferences = cmdutil.IsTruthy(os.Getenv("PULUMI_DISABLE_RESOU
 
 // Context handles registration of resources and exposes metadata about the current deployment context.
 type Context struct {
-	ctx           context.Context
-	info          RunInfo
-	stack         Resource
-	exports       map[string]Input
-	monitor       pulumirpc.ResourceMonitorClient
-	monitorConn   *grpc.ClientConn
-	engine        pulumirpc.EngineClient
-	engineConn    *grpc.ClientConn
-	keepResources bool        // true if resources should be marshaled as strongly-typed references.
-	rpcs          int         // the number of outstanding RPC requests.
-	rpcsDone      *sync.Cond  // an event signaling completion of RPCs.
-	rpcsLock      *sync.Mutex // a lock protecting the RPC count and event.
-	rpcError      error       // the first error (if any) encountered during an RPC.
+	ctx         context.Context
+	info        RunInfo
+	stack       Resource
+	exports     map[string]Input
+	monitor     pulumirpc.ResourceMonitorClient
+	monitorConn *grpc.ClientConn
+	engine      pulumirpc.EngineClient
+	engineConn  *grpc.ClientConn
+	features    EngineFeatures // the optional features supported by the resource monitor.
+	rpcs        int            // the number of outstanding RPC requests.
+	rpcsDone    *sync.Cond     // an event signaling completion of RPCs.
+	rpcsLock    *sync.Mutex    // a lock protecting the RPC count and event.
+	rpcError    error          // the first error (if any) encountered during an RPC.
+
+	// defaultVersions maps package names to the provider plugin version to use for resources that have neither an
+	// explicit provider nor an explicit version.
+	defaultVersions map[string]string
+	// registrationHook, if non-nil, is called with the type and name of each resource that is registered or read,
+	// in the order in which they are requested. It is called before the resource's inputs are prepared and the
+	// request is sent to the resource monitor, so a resource may be reported even if its request later fails; any
+	// such failure is recorded as the context's RPC error.
+	registrationHook func(t, name string)
+	// providersHook, if non-nil, is called with the type and name of each resource that is registered or read, along
+	// with the references of the providers that the resource uses, once those references have been resolved.
+	providersHook func(t, name string, refs []string)
+	// registeredHook, if non-nil, is called with the type, name, and URN of each resource that is successfully
+	// registered or read, once the resource monitor has responded.
+	registeredHook func(t, name string, urn URN)
 
 	Log Log // the logging interface for the Pulumi log stream.
 }
@@ -100,15 +116,13 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 		engine = &mockEngine{}
 	}
 
-	var keepResources bool
+	var features EngineFeatures
 	if monitor != nil {
-		supportsFeatureResp, err := monitor.SupportsFeature(ctx, &pulumirpc.SupportsFeatureRequest{
-			Id: "resourceReferences",
-		})
+		f, err := negotiateFeatures(ctx, monitor)
 		if err != nil {
-			return nil, fmt.Errorf("checking monitor features: %w", err)
+			return nil, err
 		}
-		keepResources = supportsFeatureResp.GetHasSupport()
+		features = f
 	}
 
 	mutex := &sync.Mutex{}
@@ -117,21 +131,52 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 		ctx:    ctx,
 	}
 	return &Context{
-		ctx:           ctx,
-		info:          info,
-		exports:       make(map[string]Input),
-		monitorConn:   monitorConn,
-		monitor:       monitor,
-		engineConn:    engineConn,
-		engine:        engine,
-		keepResources: keepResources,
-		rpcs:          0,
-		rpcsLock:      mutex,
-		rpcsDone:      sync.NewCond(mutex),
-		Log:           log,
+		ctx:         ctx,
+		info:        info,
+		exports:     make(map[string]Input),
+		monitorConn: monitorConn,
+		monitor:     monitor,
+		engineConn:  engineConn,
+		engine:      engine,
+		features:    features,
+		rpcs:        0,
+		rpcsLock:    mutex,
+		rpcsDone:    sync.NewCond(mutex),
+		Log:         log,
 	}, nil
 }
 
+// EngineFeatures describes the optional features supported by the engine that is running a program. Older engines
+// may not support all features, in which case the SDK falls back to a more conservative wire format.
+type EngineFeatures struct {
+	// Secrets is true if the engine tracks the secretness of values sent to it.
+	Secrets bool
+	// ResourceReferences is true if the engine accepts resource references. If false, references to resources are
+	// sent to the engine as their URNs (for components) or IDs (for custom resources).
+	ResourceReferences bool
+}
+
+// negotiateFeatures asks the resource monitor which of the optional features known to the SDK it supports.
+func negotiateFeatures(ctx context.Context, monitor pulumirpc.ResourceMonitorClient) (EngineFeatures, error) {
+	supportsFeature := func(id string) (bool, error) {
+		resp, err := monitor.SupportsFeature(ctx, &pulumirpc.SupportsFeatureRequest{Id: id})
+		if err != nil {
+			return false, fmt.Errorf("checking monitor features: %w", err)
+		}
+		return resp.GetHasSupport(), nil
+	}
+
+	var features EngineFeatures
+	var err error
+	if features.Secrets, err = supportsFeature("secrets"); err != nil {
+		return EngineFeatures{}, err
+	}
+	if features.ResourceReferences, err = supportsFeature("resourceReferences"); err != nil {
+		return EngineFeatures{}, err
+	}
+	return features, nil
+}
+
 // Close implements io.Closer and relinquishes any outstanding resources held by the context.
 func (ctx *Context) Close() error {
 	if ctx.engineConn != nil {
@@ -159,12 +204,66 @@ func (ctx *Context) Parallel() int { return ctx.info.Parallel }
 // DryRun is true when evaluating a program for purposes of planning, instead of performing a true deployment.
 func (ctx *Context) DryRun() bool { return ctx.info.DryRun }
 
+// IsRefresh is true when evaluating a program or component as part of a refresh.
+func (ctx *Context) IsRefresh() bool { return ctx.info.Refresh }
+
+// Features returns the optional features supported by the engine that is running the program. Programs can use this
+// to adapt to older engines, e.g. by avoiding resource references when they would be flattened to URNs.
+func (ctx *Context) Features() EngineFeatures { return ctx.features }
+
+// AutonamingPrefixConfigKey is the config key that supplies the autonaming prefix for a program or component if none
+// is set on its RunInfo.
+const AutonamingPrefixConfigKey = "pulumi:autonamingPrefix"
+
+// AutonamingPrefix returns the prefix that should be applied to the names of the resources a component registers, or
+// the empty string if there is none. Orchestration that creates many instances of a component can set the prefix,
+// either on the RunInfo or with the AutonamingPrefixConfigKey config key, so that the component's children are named
+// consistently across runs. Components should name their children using ChildName to honor the prefix.
+func (ctx *Context) AutonamingPrefix() string {
+	if ctx.info.AutonamingPrefix != "" {
+		return ctx.info.AutonamingPrefix
+	}
+	return ctx.info.Config[AutonamingPrefixConfigKey]
+}
+
+// ChildName returns the name to use for a resource with the given name, prefixed with the autonaming prefix and a
+// hyphen if there is a prefix.
+func (ctx *Context) ChildName(name string) string {
+	if prefix := ctx.AutonamingPrefix(); prefix != "" {
+		return prefix + "-" + name
+	}
+	return name
+}
+
 // GetConfig returns the config value, as a string, and a bool indicating whether it exists or not.
 func (ctx *Context) GetConfig(key string) (string, bool) {
 	v, ok := ctx.info.Config[key]
 	return v, ok
 }
 
+// ErrConfigMetadataUnsupported is returned by GetConfigMetadata when the engine did not supply config metadata.
+var ErrConfigMetadataUnsupported = errors.New("pulumi: config metadata is not supported by the engine")
+
+// ConfigMetadata describes the history of a config value, as supplied by the engine.
+type ConfigMetadata struct {
+	// Version is an opaque identifier that changes whenever the value changes.
+	Version string
+	// LastRotated is the time at which the value last changed, or the zero time if the engine does not know it.
+	LastRotated time.Time
+}
+
+// GetConfigMetadata returns the metadata for the given config key, and a bool indicating whether the engine supplied
+// metadata for the key. If the engine did not supply any config metadata, GetConfigMetadata returns
+// ErrConfigMetadataUnsupported. Components can use the metadata to react to the rotation of a secret, e.g. by
+// replacing a resource that depends on it only when its version changes.
+func (ctx *Context) GetConfigMetadata(key string) (ConfigMetadata, bool, error) {
+	if ctx.info.ConfigMetadata == nil {
+		return ConfigMetadata{}, false, ErrConfigMetadataUnsupported
+	}
+	md, ok := ctx.info.ConfigMetadata[key]
+	return md, ok, nil
+}
+
 // Invoke will invoke a provider's function, identified by its token tok. This function call is synchronous.
 //
 // args and result must be pointers to struct values fields and appropriately tagged and typed for use with Pulumi.
@@ -213,7 +312,11 @@ func (ctx *Context) Invoke(tok string, args interface{}, result interface{}, opt
 	keepUnknowns := ctx.DryRun()
 	rpcArgs, err := plugin.MarshalProperties(
 		resolvedArgsMap,
-		plugin.MarshalOptions{KeepUnknowns: keepUnknowns, KeepSecrets: true, KeepResources: ctx.keepResources},
+		plugin.MarshalOptions{
+			KeepUnknowns:  keepUnknowns,
+			KeepSecrets:   true,
+			KeepResources: ctx.features.ResourceReferences,
+		},
 	)
 	if err != nil {
 		return fmt.Errorf("marshaling arguments: %w", err)
@@ -227,7 +330,11 @@ func (ctx *Context) Invoke(tok string, args interface{}, result interface{}, opt
 
 	// Now, invoke the RPC to the provider synchronously.
 	logging.V(9).Infof("Invoke(%s, #args=%d): RPC call being made synchronously", tok, len(resolvedArgsMap))
-	resp, err := ctx.monitor.Invoke(ctx.ctx, &pulumirpc.InvokeRequest{
+	rpcCtx := ctx.ctx
+	if options.Context != nil {
+		rpcCtx = options.Context
+	}
+	resp, err := ctx.monitor.Invoke(rpcCtx, &pulumirpc.InvokeRequest{
 		Tok:             tok,
 		Args:            rpcArgs,
 		Provider:        providerRef,
@@ -334,6 +441,10 @@ func (ctx *Context) ReadResource(
 		return err
 	}
 
+	if ctx.registrationHook != nil {
+		ctx.registrationHook(t, name)
+	}
+
 	// Note that we're about to make an outstanding RPC request, so that we can rendezvous during shutdown.
 	if err := ctx.beginRPC(); err != nil {
 		return err
@@ -367,6 +478,7 @@ func (ctx *Context) ReadResource(
 		if err != nil {
 			return
 		}
+		ctx.reportProviders(t, name, inputs)
 
 		logging.V(9).Infof("ReadResource(%s, %s): Goroutine spawned, RPC call being made", t, name)
 		resp, err := ctx.monitor.ReadResource(ctx.ctx, &pulumirpc.ReadResourceRequest{
@@ -389,6 +501,7 @@ func (ctx *Context) ReadResource(
 		if resp != nil {
 			urn, resID = resp.Urn, string(idToRead)
 			state = resp.Properties
+			ctx.reportRegistered(t, name, err, urn)
 		}
 	}()
 
@@ -434,7 +547,11 @@ func (ctx *Context) getResource(urn string) (*pulumirpc.RegisterResourceResponse
 	keepUnknowns := ctx.DryRun()
 	rpcArgs, err := plugin.MarshalProperties(
 		resolvedArgsMap,
-		plugin.MarshalOptions{KeepUnknowns: keepUnknowns, KeepSecrets: true, KeepResources: ctx.keepResources},
+		plugin.MarshalOptions{
+			KeepUnknowns:  keepUnknowns,
+			KeepSecrets:   true,
+			KeepResources: ctx.features.ResourceReferences,
+		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling arguments: %w", err)
@@ -511,6 +628,10 @@ func (ctx *Context) registerResource(
 		return err
 	}
 
+	if ctx.registrationHook != nil {
+		ctx.registrationHook(t, name)
+	}
+
 	// Note that we're about to make an outstanding RPC request, so that we can rendezvous during shutdown.
 	if err := ctx.beginRPC(); err != nil {
 		return err
@@ -541,6 +662,7 @@ func (ctx *Context) registerResource(
 		if err != nil {
 			return
 		}
+		ctx.reportProviders(t, name, inputs)
 
 		var resp *pulumirpc.RegisterResourceResponse
 		if len(options.URN) > 0 {
@@ -591,6 +713,7 @@ func (ctx *Context) registerResource(
 				}
 				deps[key] = resources
 			}
+			ctx.reportRegistered(t, name, err, urn)
 		}
 	}()
 
@@ -877,6 +1000,31 @@ func (state *resourceState) resolve(ctx *Context, dryrun bool, err error, inputs
 	}
 }
 
+// reportProviders calls the providers hook, if any, with the references of the providers in the given inputs.
+func (ctx *Context) reportProviders(t, name string, inputs *resourceInputs) {
+	if ctx.providersHook == nil {
+		return
+	}
+
+	var refs []string
+	if inputs.provider != "" {
+		refs = append(refs, inputs.provider)
+	}
+	for _, ref := range inputs.providers {
+		refs = append(refs, ref)
+	}
+	if len(refs) != 0 {
+		ctx.providersHook(t, name, refs)
+	}
+}
+
+// reportRegistered calls the registered hook, if any, with the URN of a resource whose registration or read succeeded.
+func (ctx *Context) reportRegistered(t, name string, err error, urn string) {
+	if ctx.registeredHook != nil && err == nil {
+		ctx.registeredHook(t, name, URN(urn))
+	}
+}
+
 // resourceInputs reflects all of the inputs necessary to perform core resource RPC operations.
 type resourceInputs struct {
 	parent                  string
@@ -918,7 +1066,11 @@ func (ctx *Context) prepareResourceInputs(props Input, t string, opts *resourceO
 	keepUnknowns := ctx.DryRun()
 	rpcProps, err := plugin.MarshalProperties(
 		resolvedProps,
-		plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: keepUnknowns, KeepResources: ctx.keepResources})
+		plugin.MarshalOptions{
+			KeepSecrets:   true,
+			KeepUnknowns:  keepUnknowns,
+			KeepResources: ctx.features.ResourceReferences,
+		})
 	if err != nil {
 		return nil, fmt.Errorf("marshaling properties: %w", err)
 	}
@@ -1041,6 +1193,11 @@ func (ctx *Context) getOpts(t string, providers map[string]ProviderResource, opt
 		providerRef = pr
 	}
 
+	version := opts.Version
+	if provider == nil && version == "" {
+		version = ctx.defaultVersions[getPackage(t)]
+	}
+
 	var providerRefs map[string]string
 	if remote {
 		if opts.Providers != nil {
@@ -1056,7 +1213,7 @@ func (ctx *Context) getOpts(t string, providers map[string]ProviderResource, opt
 	}
 
 	return parentURN, depURNs, opts.Protect, providerRef, providerRefs, opts.DeleteBeforeReplace,
-		importID, opts.IgnoreChanges, opts.AdditionalSecretOutputs, opts.Version, nil
+		importID, opts.IgnoreChanges, opts.AdditionalSecretOutputs, version, nil
 }
 
 func (ctx *Context) resolveProviderReference(provider ProviderResource) (string, error) {
@@ -1071,7 +1228,7 @@ func (ctx *Context) resolveProviderReference(provider ProviderResource) (string,
 	if !known {
 		id = rpcTokenUnknownValue
 	}
-	return string(urn) + "::" + string(id), nil
+	return FormatProviderReference(urn, id), nil
 }
 
 // noMoreRPCs is a sentinel value used to stop subsequent RPCs from occurring.
@@ -1151,7 +1308,11 @@ func (ctx *Context) RegisterResourceOutputs(resource Resource, outs Map) error {
 		keepUnknowns := ctx.DryRun()
 		outsMarshalled, err := plugin.MarshalProperties(
 			outsResolved.ObjectValue(),
-			plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: keepUnknowns, KeepResources: ctx.keepResources})
+			plugin.MarshalOptions{
+				KeepSecrets:   true,
+				KeepUnknowns:  keepUnknowns,
+				KeepResources: ctx.features.ResourceReferences,
+			})
 		if err != nil {
 			return
 		}
@@ -78,6 +78,12 @@ func (log *logState) Error(msg string, args *LogArgs) error {
 func _log(ctx context.Context, engine pulumirpc.EngineClient, severity pulumirpc.LogSeverity,
 	message string, args *LogArgs) error {
 
+	// Without an engine there is nowhere to send the message (e.g. when a component is constructed outside of a
+	// deployment). Logging is never fatal, so just drop it.
+	if engine == nil {
+		return nil
+	}
+
 	if args == nil {
 		args = &LogArgs{}
 	}
@@ -16,23 +16,172 @@ package pulumi
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"google.golang.org/grpc"
 )
 
 type constructFunc func(ctx *Context, typ, name string, inputs map[string]interface{},
-	options ResourceOption) (URNInput, Input, error)
+	options ResourceOption) (URNInput, IDInput, Input, error)
 
-// construct adapts the gRPC ConstructRequest/ConstructResponse to/from the Pulumi Go SDK programming model.
-func construct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
-	constructF constructFunc) (*pulumirpc.ConstructResponse, error) {
+// ConstructError is the error returned when constructing a component fails because one or more of the RPCs made by
+// the component's constructor failed. In addition to the underlying error, it records the URNs of the component's
+// children that were registered successfully before construction failed, which can help to diagnose partially
+// deployed components. Use errors.As to retrieve it from an error returned by a component provider.
+type ConstructError struct {
+	err  error
+	urns []URN
+}
+
+// newConstructError returns a ConstructError that wraps err and records the given URNs in sorted order.
+func newConstructError(err error, urns []URN) *ConstructError {
+	sorted := make([]URN, len(urns))
+	copy(sorted, urns)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &ConstructError{err: err, urns: sorted}
+}
+
+func (e *ConstructError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ConstructError) Unwrap() error {
+	return e.err
+}
+
+// RegisteredURNs returns the URNs of the component's children that were registered successfully before construction
+// failed, in sorted order.
+func (e *ConstructError) RegisteredURNs() []URN {
+	return e.urns
+}
+
+// ConstructLogger receives structured events that describe the phases of constructing a component in a component
+// provider. Each event is identified by name and carries fields that describe the completed phase:
+//
+//   - "inputs deserialized": "type" and "name" of the component, and "count" and sorted "keys" of its inputs.
+//   - "options rebuilt": the "parent" URN, the sorted package names of the "providers", and the numbers of
+//     "dependencies" and "aliases".
+//   - "state marshaled": the number of state "properties" and the total number of property "dependencies".
+//
+// Loggers may be called concurrently by component providers that construct several components at once.
+type ConstructLogger interface {
+	LogConstructEvent(event string, fields map[string]interface{})
+}
+
+var (
+	constructLoggerLock sync.RWMutex
+	constructLogger     ConstructLogger
+)
+
+// SetConstructLogger sets the logger that receives events as components are constructed. By default no events are
+// logged; passing nil restores that behavior.
+func SetConstructLogger(logger ConstructLogger) {
+	constructLoggerLock.Lock()
+	defer constructLoggerLock.Unlock()
+	constructLogger = logger
+}
+
+// getConstructLogger returns the current construct logger, if any.
+func getConstructLogger() ConstructLogger {
+	constructLoggerLock.RLock()
+	defer constructLoggerLock.RUnlock()
+	return constructLogger
+}
+
+// constructOptions holds optional settings for constructWithOptions.
+type constructOptions struct {
+	// DefaultVersions maps package names to the provider plugin version to use for resources registered by the
+	// construct function that have neither an explicit provider (including those passed in the request) nor an
+	// explicit version.
+	DefaultVersions map[string]string
+	// KeepUnknowns determines whether unknown values are kept when marshaling the component's state into the
+	// response. By default, they are kept only during previews.
+	KeepUnknowns *bool
+	// KeepResources determines whether resource references are kept when marshaling the component's state into the
+	// response. By default, they are kept only if the resource monitor supports them.
+	KeepResources *bool
+	// Transformations are added to the options that are passed to the construct function, and so apply to the
+	// component and to each of its children. Transformations are local to the provider: they are never sent to or
+	// received from the engine.
+	Transformations []ResourceTransformation
+	// URNTimeout bounds how long construct waits for the component's URN to resolve once the construct function has
+	// returned. If zero, the duration given by the EnvConstructURNTimeout environment variable is used; if that is also
+	// unset, construct waits indefinitely.
+	URNTimeout time.Duration
+	// Plan, if non-nil, is called during previews with the type and name of each child resource that the construct
+	// function registers or reads.
+	Plan func(t, name string)
+}
+
+// EnvConstructURNTimeout is the envvar used to read the default limit on how long a component provider waits for a
+// constructed component's URN to resolve, e.g. "5m". If unset, the provider waits indefinitely.
+const EnvConstructURNTimeout = "PULUMI_CONSTRUCT_URN_TIMEOUT"
+
+// constructURNTimeout returns the URN timeout to use for the given options, falling back to EnvConstructURNTimeout.
+func constructURNTimeout(constructOpts constructOptions) (time.Duration, error) {
+	if constructOpts.URNTimeout != 0 {
+		return constructOpts.URNTimeout, nil
+	}
+	env := os.Getenv(EnvConstructURNTimeout)
+	if env == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(env)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing %v", EnvConstructURNTimeout)
+	}
+	return timeout, nil
+}
+
+// awaitConstructURN awaits the URN of a constructed component of the given type. If timeout is positive and the URN
+// does not resolve within that duration, an error is returned.
+func awaitConstructURN(ctx context.Context, typ string, urn URNOutput, timeout time.Duration) (URN, error) {
+	if timeout <= 0 {
+		rpcURN, _, _, err := urn.awaitURN(ctx)
+		return rpcURN, err
+	}
+
+	type result struct {
+		urn URN
+		err error
+	}
+	// The result channel is buffered so that the goroutine can exit even if the URN resolves after the timeout.
+	done := make(chan result, 1)
+	go func() {
+		rpcURN, _, _, err := urn.awaitURN(ctx)
+		done <- result{rpcURN, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.urn, r.err
+	case <-timer.C:
+		return "", errors.Errorf("constructing %v: the component did not produce a URN within %v", typ, timeout)
+	}
+}
+
+// constructWithOptions adapts the gRPC ConstructRequest/ConstructResponse to/from the Pulumi Go SDK programming model.
+// constructOpts controls how the component is constructed and how its state is marshaled into the response.
+func constructWithOptions(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+	constructOpts constructOptions, constructF constructFunc) (*pulumirpc.ConstructResponse, error) {
 
 	// Configure the RunInfo.
 	runInfo := RunInfo{
@@ -41,79 +190,206 @@ func construct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn
 		Config:      req.GetConfig(),
 		Parallel:    int(req.GetParallel()),
 		DryRun:      req.GetDryRun(),
+		Refresh:     req.GetRefresh(),
 		MonitorAddr: req.GetMonitorEndpoint(),
 		engineConn:  engineConn,
 	}
+	if md := req.GetConfigMetadata(); md != nil {
+		runInfo.ConfigMetadata = make(map[string]ConfigMetadata, len(md))
+		for k, v := range md {
+			var lastRotated time.Time
+			if v.GetLastRotated() != "" {
+				t, err := time.Parse(time.RFC3339, v.GetLastRotated())
+				if err != nil {
+					return nil, errors.Wrapf(err, "parsing last rotated time of config key %q", k)
+				}
+				lastRotated = t
+			}
+			runInfo.ConfigMetadata[k] = ConfigMetadata{Version: v.GetVersion(), LastRotated: lastRotated}
+		}
+	}
+	if err := runInfo.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid construct request")
+	}
+	urnTimeout, err := constructURNTimeout(constructOpts)
+	if err != nil {
+		return nil, err
+	}
 	pulumiCtx, err := NewContext(ctx, runInfo)
 	if err != nil {
 		return nil, errors.Wrap(err, "constructing run context")
 	}
+	pulumiCtx.defaultVersions = constructOpts.DefaultVersions
+	warnUnsupportedConstructOptions(pulumiCtx, req)
+	if planF := constructOpts.Plan; planF != nil && req.GetDryRun() {
+		pulumiCtx.registrationHook = func(t, name string) {
+			// Skip the component itself.
+			if t != req.GetType() || name != req.GetName() {
+				planF(t, name)
+			}
+		}
+	}
+
+	// Keep track of the providers used by the component's children.
+	var usedProvidersLock sync.Mutex
+	usedProviders := map[string]struct{}{}
+	pulumiCtx.providersHook = func(t, name string, refs []string) {
+		// Skip the component itself.
+		if t == req.GetType() && name == req.GetName() {
+			return
+		}
+		usedProvidersLock.Lock()
+		defer usedProvidersLock.Unlock()
+		for _, ref := range refs {
+			usedProviders[ref] = struct{}{}
+		}
+	}
+
+	// Keep track of the children that are registered successfully, so that they can be reported if any RPC fails.
+	var registeredLock sync.Mutex
+	var registered []URN
+	pulumiCtx.registeredHook = func(t, name string, urn URN) {
+		// Skip the component itself.
+		if t == req.GetType() && name == req.GetName() {
+			return
+		}
+		registeredLock.Lock()
+		defer registeredLock.Unlock()
+		registered = append(registered, urn)
+	}
 
 	// Deserialize the inputs and apply appropriate dependencies.
 	inputDependencies := req.GetInputDependencies()
-	deserializedInputs, err := plugin.UnmarshalProperties(
-		req.GetInputs(),
-		plugin.MarshalOptions{KeepSecrets: true, KeepResources: true, KeepUnknowns: req.GetDryRun()},
-	)
-	if err != nil {
-		return nil, errors.Wrap(err, "unmarshaling inputs")
+	rpcInputs := req.GetInputs().GetFields()
+	keys := make([]string, 0, len(rpcInputs))
+	for k := range rpcInputs {
+		keys = append(keys, k)
 	}
-	inputs := make(map[string]interface{}, len(deserializedInputs))
-	for key, input := range deserializedInputs {
-		k := string(key)
+	sort.Strings(keys)
+	inputs := make(map[string]interface{}, len(rpcInputs))
+	for _, k := range keys {
+		input := rpcInputs[k]
+		if !req.GetDryRun() && isUnknownRPCValue(input) {
+			continue
+		}
+
+		// Remove any duplicate dependencies, keeping the order in which the engine sent them.
 		var deps []Resource
+		seen := map[string]bool{}
 		if inputDeps, ok := inputDependencies[k]; ok {
-			deps = make([]Resource, len(inputDeps.GetUrns()))
-			for i, depURN := range inputDeps.GetUrns() {
-				deps[i] = newDependencyResource(URN(depURN))
+			deps = make([]Resource, 0, len(inputDeps.GetUrns()))
+			for _, depURN := range inputDeps.GetUrns() {
+				if seen[depURN] {
+					continue
+				}
+				seen[depURN] = true
+				deps = append(deps, newDependencyResource(URN(depURN)))
+			}
+		}
+
+		// Also record the resources that are referenced by the input's elements but that are not already
+		// dependencies of the input as a whole.
+		var elementDeps []Resource
+		for _, refURN := range constructInputResourceURNs(input, nil) {
+			if seen[refURN] {
+				continue
 			}
+			seen[refURN] = true
+			elementDeps = append(elementDeps, newDependencyResource(URN(refURN)))
 		}
 
-		val, secret, err := unmarshalPropertyValue(pulumiCtx, input)
+		val, known, secret, err := unmarshalConstructInput(pulumiCtx, input, req.GetDryRun())
 		if err != nil {
 			return nil, errors.Wrapf(err, "unmarshaling input %s", k)
 		}
 
 		inputs[k] = &constructInput{
-			value:  val,
-			secret: secret,
-			deps:   deps,
+			value:       val,
+			known:       known,
+			secret:      secret,
+			deps:        deps,
+			elementDeps: elementDeps,
+		}
+	}
+	logger := getConstructLogger()
+	if logger != nil {
+		inputKeys := make([]string, 0, len(inputs))
+		for _, k := range keys {
+			if _, ok := inputs[k]; ok {
+				inputKeys = append(inputKeys, k)
+			}
 		}
+		logger.LogConstructEvent("inputs deserialized", map[string]interface{}{
+			"type":  req.GetType(),
+			"name":  req.GetName(),
+			"count": len(inputKeys),
+			"keys":  inputKeys,
+		})
 	}
 
 	// Rebuild the resource options.
 	aliases := make([]Alias, len(req.GetAliases()))
 	for i, urn := range req.GetAliases() {
-		aliases[i] = Alias{URN: URN(urn)}
+		aliases[i] = constructAlias(URN(urn), req)
 	}
 	dependencies := make([]Resource, len(req.GetDependencies()))
 	for i, urn := range req.GetDependencies() {
 		dependencies[i] = newDependencyResource(URN(urn))
 	}
+	// Process the providers in package order so that any errors are reported deterministically.
+	pkgs := make([]string, 0, len(req.GetProviders()))
+	for pkg := range req.GetProviders() {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
 	providers := make(map[string]ProviderResource, len(req.GetProviders()))
-	for pkg, ref := range req.GetProviders() {
-		// Parse the URN and ID out of the provider reference.
-		lastSep := strings.LastIndex(ref, "::")
-		if lastSep == -1 {
-			return nil, errors.Errorf("expected '::' in provider reference %s", ref)
+	for _, pkg := range pkgs {
+		urn, id, err := ParseProviderReference(req.GetProviders()[pkg])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing provider for package %v", pkg)
 		}
-		urn := ref[0:lastSep]
-		id := ref[lastSep+2:]
-		providers[pkg] = newDependencyProviderResource(URN(urn), ID(id))
+		providers[pkg] = newDependencyProviderResource(urn, id)
 	}
 	var parent Resource
 	if req.GetParent() != "" {
 		parent = newDependencyResource(URN(req.GetParent()))
 	}
+	customTimeouts, err := constructCustomTimeouts(req.GetCustomTimeouts())
+	if err != nil {
+		return nil, err
+	}
+	// Children that are registered with these options also inherit the transformations from the component, so make
+	// sure that each transformation is only applied to each resource once.
+	transformations := make([]ResourceTransformation, len(constructOpts.Transformations))
+	for i, t := range constructOpts.Transformations {
+		transformations[i] = onceResourceTransformation(t)
+	}
+	// Combine the options with any that precede them in the same way as the individual options do, so that the
+	// component can layer its own options on top of these (e.g. with MergeOptions).
 	opts := resourceOption(func(ro *resourceOptions) {
-		ro.Aliases = aliases
-		ro.DependsOn = dependencies
+		Aliases(aliases).applyResourceOption(ro)
+		DependsOn(dependencies).applyResourceOption(ro)
+		ProviderMap(providers).applyResourceOption(ro)
+		Transformations(transformations).applyResourceOption(ro)
+		AdditionalSecretOutputs(req.GetAdditionalSecretOutputs()).applyResourceOption(ro)
 		ro.Protect = req.GetProtect()
-		ro.Providers = providers
+		ro.DeleteBeforeReplace = req.GetDeleteBeforeReplace()
 		ro.Parent = parent
+		if customTimeouts != nil {
+			ro.CustomTimeouts = customTimeouts
+		}
 	})
 
-	urn, state, err := constructF(pulumiCtx, req.GetType(), req.GetName(), inputs, opts)
+	if logger != nil {
+		logger.LogConstructEvent("options rebuilt", map[string]interface{}{
+			"parent":       req.GetParent(),
+			"providers":    pkgs,
+			"dependencies": len(dependencies),
+			"aliases":      len(aliases),
+		})
+	}
+
+	urn, id, state, err := constructF(pulumiCtx, req.GetType(), req.GetName(), inputs, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -121,14 +397,28 @@ func construct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn
 	// Ensure all outstanding RPCs have completed before proceeding. Also, prevent any new RPCs from happening.
 	pulumiCtx.waitForRPCs()
 	if pulumiCtx.rpcError != nil {
-		return nil, errors.Wrap(pulumiCtx.rpcError, "waiting for RPCs")
+		registeredLock.Lock()
+		defer registeredLock.Unlock()
+		return nil, newConstructError(errors.Wrap(pulumiCtx.rpcError, "waiting for RPCs"), registered)
 	}
 
-	rpcURN, _, _, err := urn.ToURNOutput().awaitURN(ctx)
+	if urn == nil {
+		return nil, errors.Errorf("constructing %v: the construct function did not return a URN", req.GetType())
+	}
+	rpcURN, err := awaitConstructURN(ctx, req.GetType(), urn.ToURNOutput(), urnTimeout)
 	if err != nil {
 		return nil, err
 	}
 
+	// If the constructed resource is a custom resource, also return its ID. Components have no ID. As with
+	// RegisterResource, an ID that is not yet known (e.g. during a preview) is returned as the empty string.
+	var rpcID ID
+	if id != nil {
+		if rpcID, _, _, err = id.ToIDOutput().awaitID(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Serialize all state properties, first by awaiting them, and then marshaling them to the requisite gRPC values.
 	resolvedProps, propertyDeps, _, err := marshalInputs(state)
 	if err != nil {
@@ -137,51 +427,355 @@ func construct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn
 
 	// Marshal all properties for the RPC call.
 	keepUnknowns := req.GetDryRun()
+	if constructOpts.KeepUnknowns != nil {
+		keepUnknowns = *constructOpts.KeepUnknowns
+	}
+	keepResourceRefs := pulumiCtx.features.ResourceReferences
+	if constructOpts.KeepResources != nil {
+		keepResourceRefs = *constructOpts.KeepResources
+	}
 	rpcProps, err := plugin.MarshalProperties(
 		resolvedProps,
-		plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: keepUnknowns, KeepResources: pulumiCtx.keepResources})
+		plugin.MarshalOptions{
+			KeepSecrets:   true,
+			KeepUnknowns:  keepUnknowns,
+			KeepResources: keepResourceRefs,
+		})
 	if err != nil {
 		return nil, errors.Wrap(err, "marshaling properties")
 	}
 
 	// Convert the property dependencies map for RPC and remove duplicates.
-	rpcPropertyDeps := make(map[string]*pulumirpc.ConstructResponse_PropertyDependencies)
+	rpcPropertyDeps := make(map[string]*pulumirpc.ConstructResponse_PropertyDependencies, len(propertyDeps))
+	seen := make(map[URN]struct{})
 	for k, deps := range propertyDeps {
-		sort.Slice(deps, func(i, j int) bool { return deps[i] < deps[j] })
-
-		urns := make([]string, 0, len(deps))
-		for i, d := range deps {
-			if i > 0 && urns[i-1] == string(d) {
-				continue
-			}
-			urns = append(urns, string(d))
-		}
-
 		rpcPropertyDeps[k] = &pulumirpc.ConstructResponse_PropertyDependencies{
-			Urns: urns,
+			Urns: dedupPropertyDependencies(deps, seen),
 		}
 	}
+	if logger != nil {
+		depCount := 0
+		for _, deps := range rpcPropertyDeps {
+			depCount += len(deps.GetUrns())
+		}
+		logger.LogConstructEvent("state marshaled", map[string]interface{}{
+			"properties":   len(rpcProps.GetFields()),
+			"dependencies": depCount,
+		})
+	}
+
+	providerRefs := make([]string, 0, len(usedProviders))
+	for ref := range usedProviders {
+		providerRefs = append(providerRefs, ref)
+	}
+	sort.Strings(providerRefs)
 
 	return &pulumirpc.ConstructResponse{
 		Urn:               string(rpcURN),
 		State:             rpcProps,
 		StateDependencies: rpcPropertyDeps,
+		Providers:         providerRefs,
+		Id:                string(rpcID),
 	}, nil
 }
 
+// warnUnsupportedConstructOptions logs a warning for each field in the request that this version of the SDK does not
+// recognize, and therefore ignores. These are options added to newer versions of the engine, so the component may
+// behave differently than the engine expects. Each field is warned about once per request.
+func warnUnsupportedConstructOptions(ctx *Context, req *pulumirpc.ConstructRequest) {
+	for _, field := range unrecognizedFields(proto.MessageReflect(req).GetUnknown()) {
+		msg := fmt.Sprintf("the engine sent %v (%v) an option (field %v) that is not supported by this version of "+
+			"the Pulumi SDK and will be ignored; upgrade the component's provider to honor it",
+			req.GetName(), req.GetType(), field)
+		contract.IgnoreError(ctx.Log.Warn(msg, nil))
+	}
+}
+
+// unrecognizedFields returns the numbers of the fields in the given protobuf wire-format data, in order of first
+// appearance.
+func unrecognizedFields(data []byte) []uint64 {
+	var fields []uint64
+	seen := map[uint64]bool{}
+	buf := proto.NewBuffer(data)
+	for len(buf.Unread()) > 0 {
+		key, err := buf.DecodeVarint()
+		if err != nil {
+			return fields
+		}
+		if field := key >> 3; !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+
+		// Skip the field's value.
+		switch key & 7 {
+		case 0:
+			_, err = buf.DecodeVarint()
+		case 1:
+			_, err = buf.DecodeFixed64()
+		case 2:
+			_, err = buf.DecodeRawBytes(false)
+		case 5:
+			_, err = buf.DecodeFixed32()
+		default:
+			// Groups are deprecated and never sent by the engine.
+			return fields
+		}
+		if err != nil {
+			return fields
+		}
+	}
+	return fields
+}
+
+// ProviderReferenceError is returned by ParseProviderReference when a provider reference is malformed.
+type ProviderReferenceError struct {
+	// Ref is the malformed provider reference.
+	Ref string
+}
+
+func (err *ProviderReferenceError) Error() string {
+	return fmt.Sprintf("expected '::' in provider reference %s", err.Ref)
+}
+
+// FormatProviderReference formats a provider's URN and ID as a provider reference of the form `<urn>::<id>`.
+func FormatProviderReference(urn URN, id ID) string {
+	return string(urn) + "::" + string(id)
+}
+
+// ParseProviderReference splits a provider reference of the form `<urn>::<id>` into the provider's URN and ID. The ID
+// is everything after the last `::` in the reference, so the URN may itself contain `::` but the ID may not. If the
+// reference does not contain `::`, a *ProviderReferenceError is returned. ParseProviderReference is the inverse of
+// FormatProviderReference.
+func ParseProviderReference(ref string) (URN, ID, error) {
+	lastSep := strings.LastIndex(ref, "::")
+	if lastSep == -1 {
+		return "", "", &ProviderReferenceError{Ref: ref}
+	}
+	return URN(ref[0:lastSep]), ID(ref[lastSep+2:]), nil
+}
+
+// onceResourceTransformation wraps the given transformation so that it transforms each resource at most once.
+func onceResourceTransformation(t ResourceTransformation) ResourceTransformation {
+	var lock sync.Mutex
+	transformed := map[Resource]bool{}
+	return func(args *ResourceTransformationArgs) *ResourceTransformationResult {
+		lock.Lock()
+		done := transformed[args.Resource]
+		transformed[args.Resource] = true
+		lock.Unlock()
+
+		if done {
+			return nil
+		}
+		return t(args)
+	}
+}
+
+// constructAlias decodes an alias URN sent by the engine. Aliases that differ from the component's URN only in their
+// type (e.g. after the component's type token was renamed) are decoded as type-only aliases so that they are resolved
+// relative to the component's current name and parent. All other aliases are decoded as URN aliases.
+func constructAlias(alias URN, req *pulumirpc.ConstructRequest) Alias {
+	urn := resource.URN(alias)
+	if !urn.IsValid() || string(urn.Name()) != req.GetName() {
+		return Alias{URN: alias}
+	}
+
+	// Compute the prefix that a type-only alias would be collapsed against. This must match CreateURN.
+	var prefix string
+	if parent := req.GetParent(); parent != "" {
+		prefix = parent[0:strings.LastIndex(parent, "::")] + "$"
+	} else {
+		prefix = "urn:pulumi:" + req.GetStack() + "::" + req.GetProject() + "::"
+	}
+
+	typ := string(urn.Type())
+	if typ == req.GetType() || string(alias) != prefix+typ+"::"+req.GetName() {
+		return Alias{URN: alias}
+	}
+	return Alias{Type: String(typ)}
+}
+
+// isUnknownRPCValue returns true if the given RPC value is one of the sentinels used to represent unknown values.
+func isUnknownRPCValue(v *structpb.Value) bool {
+	s, ok := v.GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return false
+	}
+	switch s.StringValue {
+	case plugin.UnknownBoolValue, plugin.UnknownNumberValue, plugin.UnknownStringValue, plugin.UnknownArrayValue,
+		plugin.UnknownAssetValue, plugin.UnknownArchiveValue, plugin.UnknownObjectValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// unmarshalConstructInput unmarshals a construct input from its RPC representation into its runtime representation,
+// returning whether the value is entirely known and whether it contains any secrets. The result is the same as that
+// of plugin.UnmarshalPropertyValue followed by unmarshalPropertyValue, but most values are unmarshaled in a single
+// traversal without building an intermediate resource.PropertyValue. Unknown values are only kept if keepUnknowns is
+// true; otherwise they are dropped from objects and are nil elsewhere.
+func unmarshalConstructInput(ctx *Context, v *structpb.Value, keepUnknowns bool) (interface{}, bool, bool, error) {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_NullValue:
+		return nil, true, false, nil
+	case *structpb.Value_BoolValue:
+		return kind.BoolValue, true, false, nil
+	case *structpb.Value_NumberValue:
+		return kind.NumberValue, true, false, nil
+	case *structpb.Value_StringValue:
+		if isUnknownRPCValue(v) {
+			return nil, !keepUnknowns, false, nil
+		}
+		return kind.StringValue, true, false, nil
+	case *structpb.Value_ListValue:
+		elems := kind.ListValue.GetValues()
+		arr := make([]interface{}, len(elems))
+		known, secret := true, false
+		for i, elem := range elems {
+			ev, eknown, esecret, err := unmarshalConstructInput(ctx, elem, keepUnknowns)
+			if err != nil {
+				return nil, false, false, err
+			}
+			arr[i], known, secret = ev, known && eknown, secret || esecret
+		}
+		return arr, known, secret, nil
+	case *structpb.Value_StructValue:
+		fields := kind.StructValue.GetFields()
+		if sig, hasSig := fields[resource.SigKey]; hasSig {
+			if sig.GetStringValue() != resource.SecretSig {
+				// Assets, archives, and resource references are rare enough that they can take the slow path.
+				return unmarshalConstructInputSlow(ctx, v, keepUnknowns)
+			}
+
+			value, ok := fields["value"]
+			if !ok || !keepUnknowns && isUnknownRPCValue(value) {
+				return nil, false, false, errors.New("malformed RPC secret: missing value")
+			}
+			sv, known, _, err := unmarshalConstructInput(ctx, value, keepUnknowns)
+			if err != nil {
+				return nil, false, false, err
+			}
+			return sv, known, true, nil
+		}
+
+		obj := make(map[string]interface{}, len(fields))
+		known, secret := true, false
+		for k, field := range fields {
+			if !keepUnknowns && isUnknownRPCValue(field) {
+				continue
+			}
+			fv, fknown, fsecret, err := unmarshalConstructInput(ctx, field, keepUnknowns)
+			if err != nil {
+				return nil, false, false, err
+			}
+			obj[k], known, secret = fv, known && fknown, secret || fsecret
+		}
+		return obj, known, secret, nil
+	default:
+		return unmarshalConstructInputSlow(ctx, v, keepUnknowns)
+	}
+}
+
+// unmarshalConstructInputSlow unmarshals a construct input by way of a resource.PropertyValue.
+func unmarshalConstructInputSlow(ctx *Context, v *structpb.Value, keepUnknowns bool) (interface{}, bool, bool, error) {
+	pv, err := plugin.UnmarshalPropertyValue(v, plugin.MarshalOptions{
+		KeepSecrets:   true,
+		KeepResources: true,
+		KeepUnknowns:  keepUnknowns,
+	})
+	if err != nil {
+		return nil, false, false, err
+	}
+	if pv == nil {
+		return nil, true, false, nil
+	}
+	val, secret, err := unmarshalPropertyValue(ctx, *pv)
+	if err != nil {
+		return nil, false, false, err
+	}
+	return val, !pv.ContainsUnknowns(), secret, nil
+}
+
+// constructInputResourceURNs appends the URNs of the resources that are referenced by the given construct input,
+// including those referenced by the elements of arrays and maps at any depth, to urns. Map elements are visited in
+// key order so that the result is deterministic.
+func constructInputResourceURNs(v *structpb.Value, urns []string) []string {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_ListValue:
+		for _, elem := range kind.ListValue.GetValues() {
+			urns = constructInputResourceURNs(elem, urns)
+		}
+	case *structpb.Value_StructValue:
+		fields := kind.StructValue.GetFields()
+		if sig, hasSig := fields[resource.SigKey]; hasSig {
+			switch sig.GetStringValue() {
+			case resource.ResourceReferenceSig:
+				if urn := fields["urn"].GetStringValue(); urn != "" {
+					urns = append(urns, urn)
+				}
+			case resource.SecretSig:
+				urns = constructInputResourceURNs(fields["value"], urns)
+			}
+			return urns
+		}
+
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			urns = constructInputResourceURNs(fields[k], urns)
+		}
+	}
+	return urns
+}
+
 type constructInput struct {
 	value  interface{}
+	known  bool
 	secret bool
-	deps   []Resource
+	// deps are the dependencies of the input as a whole, as sent by the engine.
+	deps []Resource
+	// elementDeps are the resources referenced by the input's value, including those referenced by the elements of
+	// arrays and maps, that are not already in deps.
+	elementDeps []Resource
 }
 
-// constructInputsMap returns the inputs as a Map.
+// isOutput returns true if the input was produced by an output (i.e. it is unknown, secret, or depends on other
+// resources) rather than being a plain value.
+func (input *constructInput) isOutput() bool {
+	return !input.known || input.secret || len(input.deps) > 0
+}
+
+// constructInputIsOutput returns true if the input with the given key was produced by an output rather than being a
+// plain value. The second result is false if there is no input with the given key.
+func constructInputIsOutput(inputs map[string]interface{}, key string) (bool, bool) {
+	v, ok := inputs[key]
+	if !ok {
+		return false, false
+	}
+	return v.(*constructInput).isOutput(), true
+}
+
+// dependencies returns the input's own dependencies followed by the other resources referenced by its value.
+func (input *constructInput) dependencies() []Resource {
+	deps := make([]Resource, 0, len(input.deps)+len(input.elementDeps))
+	deps = append(deps, input.deps...)
+	return append(deps, input.elementDeps...)
+}
+
+// constructInputsMap returns the inputs as a Map. Inputs that are unknown (e.g. during a preview) are resolved as
+// unknown outputs. Each output depends on the union of the input's own dependencies and the resources referenced by
+// its value, so an array or map input whose elements reference different resources depends on all of them.
 func constructInputsMap(inputs map[string]interface{}) Map {
 	result := make(Map, len(inputs))
 	for k, v := range inputs {
 		val := v.(*constructInput)
-		output := newOutput(anyOutputType, val.deps...)
-		output.getState().resolve(val.value, true /*known*/, val.secret, nil)
+		output := newOutput(anyOutputType, val.dependencies()...)
+		output.getState().resolve(val.value, val.known, val.secret, nil)
 		result[k] = output
 	}
 	return result
@@ -189,50 +783,40 @@ func constructInputsMap(inputs map[string]interface{}) Map {
 
 // constructInputsSetArgs sets the inputs on the given args struct.
 func constructInputsSetArgs(inputs map[string]interface{}, args interface{}) error {
-	if args == nil {
-		return errors.New("args must not be nil")
-	}
-	argsV := reflect.ValueOf(args)
-	typ := argsV.Type()
-	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
-		return errors.New("args must be a pointer to a struct")
+	argsV, err := constructArgsStruct(args)
+	if err != nil {
+		return err
 	}
-	argsV, typ = argsV.Elem(), typ.Elem()
 
+	fields := constructArgsFields(argsV.Type())
 	for k, v := range inputs {
 		val := v.(*constructInput)
-		for i := 0; i < typ.NumField(); i++ {
-			fieldV := argsV.Field(i)
-			if !fieldV.CanSet() {
-				continue
-			}
-			field := typ.Field(i)
-			tag, has := field.Tag.Lookup("pulumi")
-			if !has || tag != k {
-				continue
-			}
+		for _, field := range fields[k] {
+			fieldV := argsV.Field(field.index)
 
-			if !field.Type.Implements(reflect.TypeOf((*Input)(nil)).Elem()) {
+			// Fields with a plain Go type (e.g. *string or []string) are decoded from the input's value directly.
+			if field.plain {
+				if err := constructPlainInput(k, val, fieldV); err != nil {
+					return err
+				}
 				continue
 			}
 
-			outputType := anyOutputType
-
-			toOutputMethodName := "To" + strings.TrimSuffix(field.Type.Name(), "Input") + "Output"
-			toOutputMethod, found := field.Type.MethodByName(toOutputMethodName)
-			if found {
-				mt := toOutputMethod.Type
-				if mt.NumIn() != 0 || mt.NumOut() != 1 {
-					continue
+			// Fields with a concrete primitive type (e.g. generated enum types) cannot hold outputs, so the input
+			// value must be converted to the field's type directly.
+			if field.outputType == nil {
+				v, err := constructPrimitiveInput(k, val, fieldV.Type())
+				if err != nil {
+					return err
 				}
-				outputType = mt.Out(0)
-				if !outputType.Implements(reflect.TypeOf((*Output)(nil)).Elem()) {
-					continue
+				if v.IsValid() {
+					fieldV.Set(v)
 				}
+				continue
 			}
 
-			output := newOutput(outputType, val.deps...)
-			output.getState().resolve(val.value, true /*known*/, val.secret, nil)
+			output := newOutput(field.outputType, val.dependencies()...)
+			output.getState().resolve(val.value, val.known, val.secret, nil)
 			fieldV.Set(reflect.ValueOf(output))
 		}
 	}
@@ -240,32 +824,601 @@ func constructInputsSetArgs(inputs map[string]interface{}, args interface{}) err
 	return nil
 }
 
-// newConstructResult converts a resource into its associated URN and state.
-func newConstructResult(resource ComponentResource) (URNInput, Input, error) {
-	if resource == nil {
-		return nil, nil, errors.New("resource must not be nil")
+// constructInputsUnmappedKeys returns the keys of the inputs that constructInputsSetArgs would not set on any field of
+// the given args struct, in sorted order.
+func constructInputsUnmappedKeys(inputs map[string]interface{}, args interface{}) ([]string, error) {
+	argsV, err := constructArgsStruct(args)
+	if err != nil {
+		return nil, err
 	}
 
-	resourceV := reflect.ValueOf(resource)
-	typ := resourceV.Type()
+	fields := constructArgsFields(argsV.Type())
+	var unmapped []string
+	for k := range inputs {
+		if len(fields[k]) == 0 {
+			unmapped = append(unmapped, k)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped, nil
+}
+
+// constructArgsStruct returns the struct that the given args value points to, or an error if args is not a pointer to
+// a struct.
+func constructArgsStruct(args interface{}) (reflect.Value, error) {
+	if args == nil {
+		return reflect.Value{}, errors.New("args must not be nil")
+	}
+	argsV := reflect.ValueOf(args)
+	typ := argsV.Type()
 	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
-		return nil, nil, errors.New("resource must be a pointer to a struct")
+		return reflect.Value{}, errors.New("args must be a pointer to a struct")
 	}
-	resourceV, typ = resourceV.Elem(), typ.Elem()
+	return argsV.Elem(), nil
+}
 
-	state := make(Map)
+// constructArgsField describes a field of an args struct that can be set from a construct input.
+type constructArgsField struct {
+	index      int          // the index of the field within the struct.
+	outputType reflect.Type // the type of output to store in the field, or nil if the field has a primitive type.
+	plain      bool         // true if the field has a plain Go type that does not implement Input.
+}
+
+// constructArgsFieldsCache caches the result of constructArgsFields for each args struct type.
+var constructArgsFieldsCache sync.Map // map[reflect.Type]map[string][]constructArgsField
+
+// constructArgsFields returns the fields of the given args struct type that can be set from construct inputs, keyed
+// by the name of the input. The result is computed once per type and must not be modified.
+func constructArgsFields(typ reflect.Type) map[string][]constructArgsField {
+	if fields, ok := constructArgsFieldsCache.Load(typ); ok {
+		return fields.(map[string][]constructArgsField)
+	}
+
+	inputType := reflect.TypeOf((*Input)(nil)).Elem()
+	outputType := reflect.TypeOf((*Output)(nil)).Elem()
+
+	fields := map[string][]constructArgsField{}
 	for i := 0; i < typ.NumField(); i++ {
-		fieldV := resourceV.Field(i)
 		field := typ.Field(i)
+		if field.PkgPath != "" {
+			// Unexported fields cannot be set.
+			continue
+		}
 		tag, has := field.Tag.Lookup("pulumi")
 		if !has {
 			continue
 		}
+		if !field.Type.Implements(inputType) {
+			fields[tag] = append(fields[tag], constructArgsField{index: i, plain: true})
+			continue
+		}
+
+		if isPrimitiveInputType(field.Type) {
+			fields[tag] = append(fields[tag], constructArgsField{index: i})
+			continue
+		}
+
+		fieldOutputType := anyOutputType
+
+		toOutputMethodName := "To" + strings.TrimSuffix(field.Type.Name(), "Input") + "Output"
+		toOutputMethod, found := field.Type.MethodByName(toOutputMethodName)
+		if found {
+			mt := toOutputMethod.Type
+			if mt.NumIn() != 0 || mt.NumOut() != 1 {
+				continue
+			}
+			fieldOutputType = mt.Out(0)
+			if !fieldOutputType.Implements(outputType) {
+				continue
+			}
+		}
+
+		fields[tag] = append(fields[tag], constructArgsField{index: i, outputType: fieldOutputType})
+	}
+
+	actual, _ := constructArgsFieldsCache.LoadOrStore(typ, fields)
+	return actual.(map[string][]constructArgsField)
+}
+
+// isPrimitiveInputType returns true if the given type, or the type that it points to, is a primitive type rather than
+// an interface, struct, or collection.
+func isPrimitiveInputType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// constructPrimitiveInput converts the value of the given input to the given primitive type, which may be a pointer.
+// If the type has an EnumValues method, the value must be one of the values it returns. If the input's value is
+// unknown or null, the zero reflect.Value is returned.
+func constructPrimitiveInput(key string, input *constructInput, typ reflect.Type) (reflect.Value, error) {
+	if !input.known || input.value == nil {
+		return reflect.Value{}, nil
+	}
+	if input.secret {
+		return reflect.Value{}, errors.Errorf("input %s is secret and cannot be assigned to a field of type %v",
+			key, typ)
+	}
+
+	elemType := typ
+	if typ.Kind() == reflect.Ptr {
+		elemType = typ.Elem()
+	}
+
+	v := reflect.ValueOf(input.value)
+	switch {
+	case elemType.Kind() == reflect.String && v.Kind() == reflect.String,
+		elemType.Kind() == reflect.Bool && v.Kind() == reflect.Bool,
+		(elemType.Kind() == reflect.Int || elemType.Kind() == reflect.Float64) && v.Kind() == reflect.Float64:
+		v = v.Convert(elemType)
+	default:
+		return reflect.Value{}, errors.Errorf("cannot assign input %s of type %T to a field of type %v",
+			key, input.value, typ)
+	}
+
+	if enum, ok := v.Interface().(interface{ EnumValues() []interface{} }); ok {
+		valid := false
+		for _, value := range enum.EnumValues() {
+			if value == v.Interface() {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return reflect.Value{}, errors.Errorf("invalid value %v for input %s of type %v", input.value, key, elemType)
+		}
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		ptr := reflect.New(elemType)
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	return v, nil
+}
+
+// constructPlainInput decodes the value of the given input into dest, which is a field with a plain Go type that does
+// not implement Input. If the input's value is unknown or null, dest is left unchanged.
+func constructPlainInput(key string, input *constructInput, dest reflect.Value) error {
+	if !input.known || input.value == nil {
+		return nil
+	}
+	if input.secret {
+		return errors.Errorf("input %s is secret and cannot be assigned to a field of type %v", key, dest.Type())
+	}
+
+	// Decode into a fresh value so that the field is only set if the whole value can be decoded.
+	v := reflect.New(dest.Type()).Elem()
+	if err := constructPlainValue(key, input.value, v); err != nil {
+		return err
+	}
+	dest.Set(v)
+	return nil
+}
+
+// constructPlainValue decodes a construct input value into dest. Pointers, slices, and maps are allocated as
+// necessary. path names the value being decoded in errors.
+func constructPlainValue(path string, v interface{}, dest reflect.Value) error {
+	if v == nil {
+		return nil
+	}
+
+	// Allocate storage as necessary.
+	for dest.Kind() == reflect.Ptr {
+		elem := reflect.New(dest.Type().Elem())
+		dest.Set(elem)
+		dest = elem.Elem()
+	}
+
+	vV := reflect.ValueOf(v)
+	if vV.Type().AssignableTo(dest.Type()) {
+		dest.Set(vV)
+		return nil
+	}
+
+	mismatch := func() error {
+		return errors.Errorf("cannot assign input %s of type %T to a field of type %v", path, v, dest.Type())
+	}
+	switch dest.Kind() {
+	case reflect.Bool:
+		if vV.Kind() != reflect.Bool {
+			return mismatch()
+		}
+		dest.SetBool(vV.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if vV.Kind() != reflect.Float64 {
+			return mismatch()
+		}
+		if f := vV.Float(); f != math.Trunc(f) {
+			return errors.Errorf("cannot assign input %s with non-integer value %v to a field of type %v",
+				path, f, dest.Type())
+		}
+		dest.Set(vV.Convert(dest.Type()))
+	case reflect.Float32, reflect.Float64:
+		if vV.Kind() != reflect.Float64 {
+			return mismatch()
+		}
+		dest.Set(vV.Convert(dest.Type()))
+	case reflect.String:
+		if vV.Kind() != reflect.String {
+			return mismatch()
+		}
+		dest.Set(vV.Convert(dest.Type()))
+	case reflect.Slice:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		slice := reflect.MakeSlice(dest.Type(), len(arr), len(arr))
+		for i, e := range arr {
+			if err := constructPlainValue(fmt.Sprintf("%s[%d]", path, i), e, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dest.Set(slice)
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+		keyType, elemType := dest.Type().Key(), dest.Type().Elem()
+		if keyType.Kind() != reflect.String {
+			return errors.Errorf("cannot assign input %s to a field of type %v: map keys must be strings",
+				path, dest.Type())
+		}
+		result := reflect.MakeMapWithSize(dest.Type(), len(obj))
+		for k, e := range obj {
+			elem := reflect.New(elemType).Elem()
+			if err := constructPlainValue(path+"."+k, e, elem); err != nil {
+				return err
+			}
+			key := reflect.New(keyType).Elem()
+			key.SetString(k)
+			result.SetMapIndex(key, elem)
+		}
+		dest.Set(result)
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// newConstructResult converts a resource into its associated URN, ID, and state. The ID is nil unless the resource is
+// a custom resource.
+func newConstructResult(resource Resource) (URNInput, IDInput, Input, error) {
+	state, err := constructResultState(resource)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var id IDInput
+	if custom, ok := resource.(CustomResource); ok {
+		id = custom.ID()
+	}
+	return resource.URN(), id, state, nil
+}
+
+// registerConstructResult collects the state of a resource, registers it as the resource's outputs, and returns the
+// resource's associated URN and state. Only component resources may register outputs, so the returned ID is always nil.
+func registerConstructResult(ctx *Context, resource ComponentResource) (URNInput, IDInput, Input, error) {
+	state, err := constructResultState(resource)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := ctx.RegisterResourceOutputs(resource, state); err != nil {
+		return nil, nil, nil, err
+	}
+	return resource.URN(), nil, state, nil
+}
+
+// constructResultState collects the values of the resource's fields that are tagged with `pulumi`, including those
+// promoted from embedded structs, into a Map. Fields that are neither inputs nor arrays or maps of resources or inputs
+// are marshaled using the MarshalFunc registered for their type, if any, or else as plain values (e.g. strings,
+// numbers, and structs, slices, and maps thereof). It is an error if a non-nil field has a type that can be marshaled
+// neither way, e.g. a channel, a function, or a struct with fields that are not tagged with `pulumi`.
+func constructResultState(resource Resource) (Map, error) {
+	if resource == nil {
+		return nil, errors.New("resource must not be nil")
+	}
+
+	resourceV := reflect.ValueOf(resource)
+	typ := resourceV.Type()
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("resource must be a pointer to a struct")
+	}
+	fields, err := constructResultFields(resourceV.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	state := make(Map)
+	for _, f := range fields {
+		fieldV, field, tag := f.value, f.field, f.tag
 		val := fieldV.Interface()
 		if v, ok := val.(Input); ok {
 			state[tag] = v
+		} else if collection, ok := resourceCollection(fieldV); ok {
+			// Arrays and maps of resources or inputs (e.g. []Resource) are not inputs themselves, so wrap them in an
+			// output that depends on each of the collection's elements. Note that we can't use Any here: awaiting the
+			// collection would copy the resources, and the copies would have no URNs. Instead, the elements are left
+			// as-is and marshaled along with the rest of the state.
+			out := newOutput(anyOutputType, gatherDependencies(collection)...)
+			out.getState().resolve(collection, true, false, nil)
+			state[tag] = out
+		} else if marshal, ok := lookupMarshaler(field.Type); ok {
+			mv, err := marshal(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "marshaling field %v (%v)", field.Name, field.Type)
+			}
+			state[tag] = plainValueInput(mv)
+		} else if isMarshalableType(field.Type) {
+			state[tag] = plainValueInput(val)
+		} else if !isNilValue(fieldV) {
+			return nil, errors.Errorf("cannot marshal field %v of type %v: register a marshaler for the type with "+
+				"RegisterMarshaler", field.Name, field.Type)
+		}
+	}
+
+	if withDeps, ok := resource.(ConstructResultWithDependencies); ok {
+		for k, deps := range withDeps.OutputDependencies() {
+			v, has := state[k]
+			if !has {
+				return nil, errors.Errorf("cannot add dependencies to output %v: the resource has no such output", k)
+			}
+			if len(deps) > 0 {
+				state[k] = constructOutputWithDependencies(v, deps)
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// constructResultField is a field of a resource struct that is tagged with `pulumi`.
+type constructResultField struct {
+	tag   string
+	path  string // the name of the field, qualified by the names of any embedded structs that it is promoted from.
+	field reflect.StructField
+	value reflect.Value
+	depth int
+}
+
+// constructResultFields returns the exported fields of the given resource struct that are tagged with `pulumi`,
+// including those that are promoted from embedded structs (e.g. a base struct shared by several components). As with
+// Go's own field promotion, a field at a shallower depth takes precedence over a deeper field with the same tag. It is
+// an error for two fields at the same depth to have the same tag, as it would be ambiguous which of them to use.
+func constructResultFields(v reflect.Value) ([]constructResultField, error) {
+	var fields []constructResultField
+	var visit func(v reflect.Value, prefix string, depth int)
+	visit = func(v reflect.Value, prefix string, depth int) {
+		typ := v.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field, fieldV := typ.Field(i), v.Field(i)
+			if field.PkgPath != "" {
+				// Unexported fields (including those of unexported embedded structs) cannot be read.
+				continue
+			}
+			if tag, has := field.Tag.Lookup("pulumi"); has {
+				fields = append(fields, constructResultField{
+					tag:   tag,
+					path:  prefix + field.Name,
+					field: field,
+					value: fieldV,
+					depth: depth,
+				})
+				continue
+			}
+			if !field.Anonymous {
+				continue
+			}
+			if fieldV.Kind() == reflect.Ptr {
+				if fieldV.IsNil() {
+					continue
+				}
+				fieldV = fieldV.Elem()
+			}
+			if fieldV.Kind() == reflect.Struct {
+				visit(fieldV, prefix+field.Name+".", depth+1)
+			}
+		}
+	}
+	visit(v, "", 0)
+
+	// Keep only the shallowest field for each tag.
+	depths := map[string]int{}
+	for _, f := range fields {
+		if d, has := depths[f.tag]; !has || f.depth < d {
+			depths[f.tag] = f.depth
+		}
+	}
+	result, paths := fields[:0], map[string]string{}
+	for _, f := range fields {
+		if f.depth != depths[f.tag] {
+			continue
 		}
+		if path, has := paths[f.tag]; has {
+			return nil, errors.Errorf("fields %v and %v of %v have the same pulumi tag %q", path, f.path, v.Type(),
+				f.tag)
+		}
+		paths[f.tag] = f.path
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// ConstructResultWithDependencies may be implemented by a resource that is the result of a call to Construct in order
+// to declare dependencies of its outputs that cannot be inferred from their values, e.g. for outputs that are computed
+// by the component itself rather than taken from a child resource. OutputDependencies returns the additional
+// dependencies of each output, keyed by the output's `pulumi` tag. These are merged with the output's own dependencies
+// when the result is returned to the engine.
+type ConstructResultWithDependencies interface {
+	Resource
+
+	OutputDependencies() map[string][]Resource
+}
+
+// constructOutputWithDependencies returns an output with the value of the given input that also depends on the given
+// resources.
+func constructOutputWithDependencies(v Input, deps []Resource) Output {
+	src := ToOutput(v)
+	srcDeps := src.getState().dependencies()
+	out := newOutput(reflect.TypeOf(src), append(append(make([]Resource, 0, len(srcDeps)+len(deps)), srcDeps...),
+		deps...)...)
+	go func() {
+		value, known, secret, deps, err := src.getState().await(context.TODO())
+		out.getState().fulfill(value, known, secret, deps, err)
+	}()
+	return out
+}
+
+// constructCustomTimeouts converts the custom timeouts in a ConstructRequest into CustomTimeouts, returning an error if
+// any of the timeouts is not a valid duration. It returns nil if no timeouts were given.
+func constructCustomTimeouts(timeouts *pulumirpc.ConstructRequest_CustomTimeouts) (*CustomTimeouts, error) {
+	if timeouts == nil {
+		return nil, nil
+	}
+	for _, t := range []struct{ op, timeout string }{
+		{"create", timeouts.GetCreate()},
+		{"update", timeouts.GetUpdate()},
+		{"delete", timeouts.GetDelete()},
+	} {
+		if t.timeout == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(t.timeout); err != nil {
+			return nil, errors.Wrapf(err, "malformed %v timeout", t.op)
+		}
+	}
+	return &CustomTimeouts{
+		Create: timeouts.GetCreate(),
+		Update: timeouts.GetUpdate(),
+		Delete: timeouts.GetDelete(),
+	}, nil
+}
+
+// dedupPropertyDependencies returns the distinct URNs in deps in sorted order, so that the RPC result is
+// deterministic. seen is a scratch set that is empty on entry and is emptied again before returning, so that a single
+// set can be reused for each property.
+func dedupPropertyDependencies(deps []URN, seen map[URN]struct{}) []string {
+	urns := make([]string, 0, len(deps))
+	for _, d := range deps {
+		if _, has := seen[d]; !has {
+			seen[d] = struct{}{}
+			urns = append(urns, string(d))
+		}
+	}
+	for _, urn := range urns {
+		delete(seen, URN(urn))
+	}
+	sort.Strings(urns)
+	return urns
+}
+
+// isNilValue returns true if v is a nil pointer, interface, slice, or map. Such fields are omitted from the state.
+// plainValueInput returns v if it is an Input, or else an output that resolves to v and depends on any resources
+// that v contains. Note that we can't use Any here: awaiting v would copy any resources that it contains, and the
+// copies would have no URNs.
+func plainValueInput(v interface{}) Input {
+	if input, ok := v.(Input); ok {
+		return input
 	}
+	out := newOutput(anyOutputType, gatherDependencies(v)...)
+	out.getState().resolve(v, true, false, nil)
+	return out
+}
 
-	return resource.URN(), state, nil
+// isMarshalableType returns true if values of the given type can be marshaled as plain values, i.e. if the type is a
+// primitive type, an interface, an Input, or has a registered MarshalFunc, or if the type is a pointer to, an array,
+// slice, or string-keyed map of, or a struct whose fields are all exported, tagged with `pulumi`, and of such types.
+// Untagged struct fields are not marshaled, so structs that have them are rejected rather than silently truncated.
+func isMarshalableType(typ reflect.Type) bool {
+	return isMarshalableTypeRec(typ, map[reflect.Type]bool{})
+}
+
+func isMarshalableTypeRec(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	if typ.Implements(inputType) {
+		return true
+	}
+	if _, ok := lookupMarshaler(typ); ok {
+		return true
+	}
+	if visiting[typ] {
+		// Recursive types are marshalable if the rest of the type is.
+		return true
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr, reflect.Array, reflect.Slice:
+		return isMarshalableTypeRec(typ.Elem(), visiting)
+	case reflect.Map:
+		return typ.Key().Kind() == reflect.String && isMarshalableTypeRec(typ.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" || field.Tag.Get("pulumi") == "" || !isMarshalableTypeRec(field.Type, visiting) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// resourceCollection converts v to an []interface{} or a map[string]interface{} if it is a non-nil array, slice, or
+// string-keyed map whose elements are resources, inputs, or values with a registered MarshalFunc. The elements are
+// untyped so that each can be marshaled according to its own type.
+func resourceCollection(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		elem := v.Type().Elem()
+		if _, ok := lookupMarshaler(elem); !ok && !elem.Implements(resourceType) && !elem.Implements(inputType) {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	switch {
+	case v.Kind() == reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, true
+	case v.Kind() == reflect.Slice && v.IsNil():
+		return nil, false
+	default:
+		arr := make([]interface{}, v.Len())
+		for i := range arr {
+			arr[i] = v.Index(i).Interface()
+		}
+		return arr, true
+	}
 }
@@ -0,0 +1,69 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// CheckFunc validates the new inputs of a resource and returns the inputs the provider should use for it along with
+// any validation failures. olds holds the inputs the provider returned for the resource the last time it was checked,
+// and is empty if the resource is being created. Inputs that the new inputs leave unspecified can be taken from olds
+// in order to keep them stable across updates (e.g. a generated password).
+type CheckFunc func(ctx context.Context, urn resource.URN, olds, news resource.PropertyMap) (resource.PropertyMap,
+	[]plugin.CheckFailure, error)
+
+// Check adapts the gRPC CheckRequest/CheckResponse to/from a CheckFunc. Secrets, resource references, and unknown
+// values in the old and new inputs are preserved, as are any that the CheckFunc returns.
+func Check(ctx context.Context, req *pulumirpc.CheckRequest, check CheckFunc) (*pulumirpc.CheckResponse, error) {
+	opts := plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true, KeepResources: true}
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling old inputs")
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling new inputs")
+	}
+
+	inputs, failures, err := check(ctx, resource.URN(req.GetUrn()), olds, news)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcInputs, err := plugin.MarshalProperties(inputs, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling inputs")
+	}
+	var rpcFailures []*pulumirpc.CheckFailure
+	for _, failure := range failures {
+		rpcFailures = append(rpcFailures, &pulumirpc.CheckFailure{
+			Property: string(failure.Property),
+			Reason:   failure.Reason,
+		})
+	}
+
+	return &pulumirpc.CheckResponse{
+		Inputs:   rpcInputs,
+		Failures: rpcFailures,
+	}, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestCheckPreservesPassword(t *testing.T) {
+	opts := plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true, KeepResources: true}
+
+	generated := 0
+	check := func(ctx context.Context, urn resource.URN, olds, news resource.PropertyMap) (resource.PropertyMap,
+		[]plugin.CheckFailure, error) {
+
+		if !news.HasValue("name") {
+			return nil, []plugin.CheckFailure{{Property: "name", Reason: "missing required property"}}, nil
+		}
+
+		// Keep the old password if there is one; only generate a new password for new resources.
+		inputs := news.Copy()
+		if !inputs.HasValue("password") {
+			if old, ok := olds["password"]; ok {
+				inputs["password"] = old
+			} else {
+				generated++
+				inputs["password"] = resource.MakeSecret(resource.NewStringProperty("generated"))
+			}
+		}
+		return inputs, nil, nil
+	}
+
+	checkInputs := func(olds, news resource.PropertyMap) *pulumirpc.CheckResponse {
+		rpcOlds, err := plugin.MarshalProperties(olds, opts)
+		assert.NoError(t, err)
+		rpcNews, err := plugin.MarshalProperties(news, opts)
+		assert.NoError(t, err)
+
+		resp, err := Check(context.Background(), &pulumirpc.CheckRequest{
+			Urn:  "urn:pulumi:stack::project::pkg:index:Database::db",
+			Olds: rpcOlds,
+			News: rpcNews,
+		}, check)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	// Creating the resource generates a password, which is returned as a secret.
+	resp := checkInputs(resource.PropertyMap{}, resource.PropertyMap{"name": resource.NewStringProperty("db")})
+	created, err := plugin.UnmarshalProperties(resp.GetInputs(), opts)
+	assert.NoError(t, err)
+	assert.True(t, created["password"].IsSecret())
+	assert.Equal(t, 1, generated)
+
+	// Updating the resource keeps the existing password.
+	resp = checkInputs(created, resource.PropertyMap{
+		"name": resource.NewStringProperty("db"),
+		"size": resource.MakeComputed(resource.NewStringProperty("")),
+	})
+	updated, err := plugin.UnmarshalProperties(resp.GetInputs(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, created["password"], updated["password"])
+	assert.True(t, updated["size"].IsComputed())
+	assert.Equal(t, 1, generated)
+
+	// Failures are returned to the engine.
+	resp = checkInputs(created, resource.PropertyMap{})
+	if assert.Len(t, resp.GetFailures(), 1) {
+		assert.Equal(t, "name", resp.GetFailures()[0].GetProperty())
+		assert.Equal(t, "missing required property", resp.GetFailures()[0].GetReason())
+	}
+}
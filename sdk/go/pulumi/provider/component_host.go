@@ -0,0 +1,111 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"sync"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// ComponentProviderHost is a resource provider that constructs component resources. Each call to Construct is
+// dispatched to the ConstructFunc that is registered for the requested type token. The provider does not manage any
+// custom resources, so the methods other than GetPluginInfo, Configure, Construct, and Cancel are unimplemented.
+type ComponentProviderHost struct {
+	pulumirpc.UnimplementedResourceProviderServer
+
+	version    string
+	engineConn *grpc.ClientConn
+	opts       ConstructOptions
+
+	lock       sync.RWMutex
+	components map[string]ConstructFunc
+}
+
+// NewComponentProviderHost creates a ComponentProviderHost that constructs the given components, keyed by type token.
+// version is the version of the provider plugin that is reported to the engine, engineConn, if non-nil, is the
+// connection to the engine that is used for logging, and opts holds the options for each construction.
+func NewComponentProviderHost(version string, engineConn *grpc.ClientConn, opts ConstructOptions,
+	components map[string]ConstructFunc) *ComponentProviderHost {
+
+	h := &ComponentProviderHost{
+		version:    version,
+		engineConn: engineConn,
+		opts:       opts,
+		components: make(map[string]ConstructFunc, len(components)),
+	}
+	for typ, construct := range components {
+		h.components[typ] = construct
+	}
+	return h
+}
+
+// RegisterComponentResource registers the ConstructFunc for the component resource with the given type token. It is
+// an error to register a type token more than once.
+func (h *ComponentProviderHost) RegisterComponentResource(typ string, construct ConstructFunc) error {
+	if construct == nil {
+		return errors.Errorf("the construct function for %v must not be nil", typ)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, has := h.components[typ]; has {
+		return errors.Errorf("component resource %v is already registered", typ)
+	}
+	h.components[typ] = construct
+	return nil
+}
+
+// GetPluginInfo returns generic information about this plugin, like its version.
+func (h *ComponentProviderHost) GetPluginInfo(context.Context, *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{
+		Version: h.version,
+	}, nil
+}
+
+// Configure configures the resource provider with "globals" that control its behavior.
+func (h *ComponentProviderHost) Configure(ctx context.Context,
+	req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+	return &pulumirpc.ConfigureResponse{
+		AcceptSecrets:   true,
+		SupportsPreview: true,
+		AcceptResources: true,
+	}, nil
+}
+
+// Construct creates a new instance of the requested component resource using its registered ConstructFunc and
+// returns its state.
+func (h *ComponentProviderHost) Construct(ctx context.Context,
+	req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
+
+	h.lock.RLock()
+	construct, has := h.components[req.GetType()]
+	h.lock.RUnlock()
+	if !has {
+		return nil, errors.Errorf("unknown component resource type %v", req.GetType())
+	}
+	return ConstructWithOptions(ctx, req, h.engineConn, h.opts, construct)
+}
+
+// Cancel signals the provider to gracefully shut down and abort any ongoing resource operations.
+func (h *ComponentProviderHost) Cancel(context.Context, *pbempty.Empty) (*pbempty.Empty, error) {
+	return &pbempty.Empty{}, nil
+}
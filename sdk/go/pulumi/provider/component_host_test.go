@@ -0,0 +1,171 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+var _ pulumirpc.ResourceProviderServer = (*ComponentProviderHost)(nil)
+
+// testEngineMonitor is a fake resource monitor that registers every resource under the URN that the engine would
+// assign to it.
+type testEngineMonitor struct {
+	pulumirpc.UnimplementedResourceMonitorServer
+}
+
+func (m *testEngineMonitor) SupportsFeature(ctx context.Context,
+	req *pulumirpc.SupportsFeatureRequest) (*pulumirpc.SupportsFeatureResponse, error) {
+	return &pulumirpc.SupportsFeatureResponse{HasSupport: true}, nil
+}
+
+func (m *testEngineMonitor) RegisterResource(ctx context.Context,
+	req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+	var parentType tokens.Type
+	if parent := resource.URN(req.GetParent()); parent != "" {
+		parentType = parent.QualifiedType()
+	}
+	urn := resource.NewURN("stack", "project", parentType, tokens.Type(req.GetType()), tokens.QName(req.GetName()))
+	return &pulumirpc.RegisterResourceResponse{Urn: string(urn)}, nil
+}
+
+func (m *testEngineMonitor) RegisterResourceOutputs(ctx context.Context,
+	req *pulumirpc.RegisterResourceOutputsRequest) (*pbempty.Empty, error) {
+	return &pbempty.Empty{}, nil
+}
+
+// serve serves the given gRPC registrations on a local port and returns the port.
+func serve(t *testing.T, register func(srv *grpc.Server)) int {
+	cancel := make(chan bool)
+	port, _, err := rpcutil.Serve(0, cancel, []func(*grpc.Server) error{
+		func(srv *grpc.Server) error {
+			register(srv)
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("serving: %v", err)
+	}
+	t.Cleanup(func() { close(cancel) })
+	return port
+}
+
+type testNamedComponent struct {
+	pulumi.ResourceState
+
+	Greeting pulumi.StringOutput `pulumi:"greeting"`
+}
+
+func constructNamedComponent(greeting string) ConstructFunc {
+	return func(ctx *pulumi.Context, typ, name string, inputs ConstructInputs,
+		options pulumi.ResourceOption) (*ConstructResult, error) {
+
+		var args struct {
+			Name pulumi.StringInput `pulumi:"name"`
+		}
+		if err := inputs.SetArgs(&args); err != nil {
+			return nil, err
+		}
+
+		var component testNamedComponent
+		if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+			return nil, err
+		}
+		component.Greeting = pulumi.Sprintf("%s, %s", greeting, args.Name)
+		return RegisterConstructResult(ctx, &component)
+	}
+}
+
+func TestComponentProviderHost(t *testing.T) {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &testEngineMonitor{})
+	})
+
+	host := NewComponentProviderHost("1.0.0", nil, ConstructOptions{}, map[string]ConstructFunc{
+		"pkg:index:Hello": constructNamedComponent("hello"),
+	})
+	assert.NoError(t, host.RegisterComponentResource("pkg:index:Goodbye", constructNamedComponent("goodbye")))
+	assert.EqualError(t, host.RegisterComponentResource("pkg:index:Hello", constructNamedComponent("hi")),
+		"component resource pkg:index:Hello is already registered")
+
+	providerPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceProviderServer(srv, host)
+	})
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", providerPort), grpc.WithInsecure(),
+		rpcutil.GrpcChannelOptions())
+	if err != nil {
+		t.Fatalf("dialing provider: %v", err)
+	}
+	defer conn.Close()
+	client := pulumirpc.NewResourceProviderClient(conn)
+
+	// Load the provider the way the engine's plugin host does, which asks for its plugin info right away.
+	pctx, err := plugin.NewContext(nil, nil, nil, nil, "", nil, false, nil)
+	if err != nil {
+		t.Fatalf("creating plugin context: %v", err)
+	}
+	defer contract.IgnoreClose(pctx)
+	prov := plugin.NewProviderWithClient(pctx, "pkg", client, false)
+
+	info, err := prov.GetPluginInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "pkg", info.Name)
+	if assert.NotNil(t, info.Version) {
+		assert.Equal(t, "1.0.0", info.Version.String())
+	}
+
+	assert.NoError(t, prov.Configure(resource.PropertyMap{}))
+
+	constructInfo := plugin.ConstructInfo{
+		Project:        "project",
+		Stack:          "stack",
+		MonitorAddress: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+	}
+	inputs := resource.PropertyMap{"name": resource.NewStringProperty("world")}
+
+	// Each construction is dispatched to the component registered for its type.
+	for typ, greeting := range map[string]string{"pkg:index:Hello": "hello", "pkg:index:Goodbye": "goodbye"} {
+		result, err := prov.Construct(constructInfo, tokens.Type(typ), tokens.QName(greeting), "", inputs,
+			plugin.ConstructOptions{})
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, resource.NewURN("stack", "project", "", tokens.Type(typ), tokens.QName(greeting)), result.URN)
+		assert.Equal(t, resource.PropertyMap{"greeting": resource.NewStringProperty(greeting + ", world")},
+			result.Outputs)
+	}
+
+	_, err = prov.Construct(constructInfo, "pkg:index:Unknown", "unknown", "", inputs, plugin.ConstructOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown component resource type pkg:index:Unknown")
+
+	// Methods other than those needed to construct components are unimplemented.
+	_, err = client.Create(context.Background(), &pulumirpc.CreateRequest{})
+	assert.Error(t, err)
+}
@@ -16,6 +16,14 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
@@ -32,14 +40,135 @@ type ConstructFunc func(ctx *pulumi.Context, typ, name string, inputs ConstructI
 // Construct adapts the gRPC ConstructRequest/ConstructResponse to/from the Pulumi Go SDK programming model.
 func Construct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
 	construct ConstructFunc) (*pulumirpc.ConstructResponse, error) {
-	return linkedConstruct(ctx, req, engineConn, func(pulumiCtx *pulumi.Context, typ, name string,
-		inputs map[string]interface{}, options pulumi.ResourceOption) (pulumi.URNInput, pulumi.Input, error) {
+	return ConstructWithOptions(ctx, req, engineConn, ConstructOptions{}, construct)
+}
+
+// PlannedResource describes a child resource that a component registered during a preview.
+type PlannedResource struct {
+	// Type is the type token of the resource.
+	Type string
+	// Name is the name of the resource.
+	Name string
+}
+
+// ConstructOptions holds optional settings for ConstructWithOptions.
+type ConstructOptions struct {
+	// DefaultVersions maps package names to the provider plugin versions to use for child resources that have neither
+	// an explicit provider nor an explicit version. Any providers passed in the request take precedence over these
+	// defaults.
+	DefaultVersions map[string]string
+	// Plan, if non-nil, is called at the end of a successful preview with the child resources that the component
+	// registered or read, in the order in which they were requested. Plan is not called outside of previews, nor if
+	// any of the component's registrations or reads fails.
+	Plan func(resources []PlannedResource)
+	// DeclaredOutputs, if non-nil, maps component type tokens to the names of the outputs that the component's schema
+	// declares. If the type being constructed is present in the map, construction fails if the component produces
+	// any output that is not declared. DeclaredOutputsFromSchema can be used to compute this map from a package schema.
+	DeclaredOutputs map[string][]string
+	// KeepResources, if non-nil, overrides whether resource references are kept when marshaling the component's state
+	// into the response. By default, they are kept only if the engine's resource monitor supports them.
+	KeepResources *bool
+	// KeepUnknowns, if non-nil, overrides whether unknown values are kept when marshaling the component's state into
+	// the response. By default, they are kept only during previews. Keeping unknowns outside of previews is useful
+	// when the response is forwarded to another provider rather than returned to the engine.
+	KeepUnknowns *bool
+	// Transformations are applied to the component and to each child resource that is registered with the options
+	// passed to the ConstructFunc (or that has the component as an ancestor). Transformations are local to the
+	// provider: they are never sent to or received from the engine, so they cannot come from the calling program.
+	Transformations []pulumi.ResourceTransformation
+	// URNTimeout bounds how long construction waits for the component's URN to resolve once the ConstructFunc has
+	// returned. If zero, the duration given by the PULUMI_CONSTRUCT_URN_TIMEOUT environment variable is used; if that
+	// is also unset, construction waits indefinitely.
+	URNTimeout time.Duration
+}
+
+// DeclaredOutputsFromSchema returns the names of the outputs declared by each resource in the given JSON-encoded
+// package schema, suitable for use as ConstructOptions.DeclaredOutputs.
+func DeclaredOutputsFromSchema(schema []byte) (map[string][]string, error) {
+	var spec struct {
+		Resources map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(schema, &spec); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling schema")
+	}
+
+	outputs := make(map[string][]string, len(spec.Resources))
+	for token, res := range spec.Resources {
+		names := make([]string, 0, len(res.Properties))
+		for name := range res.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		outputs[token] = names
+	}
+	return outputs, nil
+}
+
+// checkDeclaredOutputs returns an error if the given state contains any properties that are not declared outputs.
+func checkDeclaredOutputs(typ string, state *structpb.Struct, declared []string) error {
+	isDeclared := make(map[string]bool, len(declared))
+	for _, name := range declared {
+		isDeclared[name] = true
+	}
+
+	var undeclared []string
+	for name := range state.GetFields() {
+		if !isDeclared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) == 0 {
+		return nil
+	}
+	sort.Strings(undeclared)
+	return errors.Errorf("component %v produced outputs that are not declared by its schema: %v",
+		typ, strings.Join(undeclared, ", "))
+}
+
+// ConstructWithOptions is like Construct, but accepts additional options that control how the component is
+// constructed.
+func ConstructWithOptions(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+	opts ConstructOptions, construct ConstructFunc) (*pulumirpc.ConstructResponse, error) {
+
+	var lock sync.Mutex
+	var planned []PlannedResource
+	var planF func(t, name string)
+	if opts.Plan != nil {
+		planF = func(t, name string) {
+			lock.Lock()
+			defer lock.Unlock()
+			planned = append(planned, PlannedResource{Type: t, Name: name})
+		}
+	}
+
+	constructF := func(pulumiCtx *pulumi.Context, typ, name string, inputs map[string]interface{},
+		options pulumi.ResourceOption) (pulumi.URNInput, pulumi.IDInput, pulumi.Input, error) {
 		result, err := construct(pulumiCtx, typ, name, ConstructInputs{inputs: inputs}, options)
-		if err != nil {
-			return nil, nil, err
+		if err != nil || result == nil {
+			return nil, nil, nil, err
 		}
-		return result.URN, result.State, nil
-	})
+		return result.URN, result.ID, result.State, nil
+	}
+	resp, err := linkedConstruct(ctx, req, engineConn, opts.DefaultVersions, opts.KeepUnknowns, opts.KeepResources,
+		opts.Transformations, opts.URNTimeout, planF, constructF)
+	if err != nil {
+		return nil, err
+	}
+
+	if declared, ok := opts.DeclaredOutputs[req.GetType()]; ok {
+		if err := checkDeclaredOutputs(req.GetType(), resp.GetState(), declared); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Plan != nil && req.GetDryRun() {
+		lock.Lock()
+		defer lock.Unlock()
+		opts.Plan(planned)
+	}
+	return resp, nil
 }
 
 // ConstructInputs represents the inputs associated with a call to Construct.
@@ -47,7 +176,9 @@ type ConstructInputs struct {
 	inputs map[string]interface{}
 }
 
-// Map returns the inputs as a Map.
+// Map returns the inputs as a Map. Each value is an output that depends on the dependencies of the corresponding
+// input, along with any resources that the input references, including those referenced by the elements of arrays
+// and maps.
 func (inputs ConstructInputs) Map() pulumi.Map {
 	return linkedConstructInputsMap(inputs.inputs)
 }
@@ -57,15 +188,49 @@ func (inputs ConstructInputs) SetArgs(args interface{}) error {
 	return linkedConstructInputsSetArgs(inputs.inputs, args)
 }
 
+// UnmappedKeys returns the keys of the inputs that SetArgs would not set on any field of the given args struct, in
+// sorted order. Component hosts can use this to reject unexpected inputs, e.g. those whose names are misspelled.
+func (inputs ConstructInputs) UnmappedKeys(args interface{}) ([]string, error) {
+	return linkedConstructInputsUnmappedKeys(inputs.inputs, args)
+}
+
+// IsOutput returns true if the input with the given key was produced by an output (i.e. it is unknown, secret, or
+// depends on other resources) rather than being a plain value. The second result is false if there is no input with
+// the given key.
+func (inputs ConstructInputs) IsOutput(key string) (bool, bool) {
+	return linkedConstructInputIsOutput(inputs.inputs, key)
+}
+
 // ConstructResult is the result of a call to Construct.
 type ConstructResult struct {
-	URN   pulumi.URNInput
+	// URN is the URN of the constructed resource.
+	URN pulumi.URNInput
+	// ID is the ID of the constructed resource if it is a custom resource, and nil if it is a component. An ID that is
+	// unknown (e.g. during a preview) is returned to the engine as the empty string, as with RegisterResource.
+	ID pulumi.IDInput
+	// State holds the outputs of the constructed resource.
 	State pulumi.Input
 }
 
-// NewConstructResult creates a ConstructResult from the resource.
-func NewConstructResult(resource pulumi.ComponentResource) (*ConstructResult, error) {
-	urn, state, err := linkedNewConstructResult(resource)
+// NewConstructResult creates a ConstructResult from the resource. The resource may be either a component resource or a
+// custom resource; if it is a custom resource, the result also carries the resource's ID.
+func NewConstructResult(resource pulumi.Resource) (*ConstructResult, error) {
+	urn, id, state, err := linkedNewConstructResult(resource)
+	if err != nil {
+		return nil, err
+	}
+	return &ConstructResult{
+		URN:   urn,
+		ID:    id,
+		State: state,
+	}, nil
+}
+
+// RegisterConstructResult registers the values of the resource's fields that are tagged with `pulumi` as the
+// resource's outputs and creates a ConstructResult from the resource. Components that use this function do not need to
+// call RegisterResourceOutputs themselves.
+func RegisterConstructResult(ctx *pulumi.Context, resource pulumi.ComponentResource) (*ConstructResult, error) {
+	urn, _, state, err := linkedRegisterConstructResult(ctx, resource)
 	if err != nil {
 		return nil, err
 	}
@@ -76,10 +241,12 @@ func NewConstructResult(resource pulumi.ComponentResource) (*ConstructResult, er
 }
 
 type constructFunc func(ctx *pulumi.Context, typ, name string, inputs map[string]interface{},
-	options pulumi.ResourceOption) (pulumi.URNInput, pulumi.Input, error)
+	options pulumi.ResourceOption) (pulumi.URNInput, pulumi.IDInput, pulumi.Input, error)
 
 // linkedConstruct is made available here from ../provider_linked.go via go:linkname.
 func linkedConstruct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+	defaultVersions map[string]string, keepUnknowns, keepResources *bool,
+	transformations []pulumi.ResourceTransformation, urnTimeout time.Duration, planF func(t, name string),
 	constructF constructFunc) (*pulumirpc.ConstructResponse, error)
 
 // linkedConstructInputsMap is made available here from ../provider_linked.go via go:linkname.
@@ -88,5 +255,15 @@ func linkedConstructInputsMap(inputs map[string]interface{}) pulumi.Map
 // linkedConstructInputsSetArgs is made available here from ../provider_linked.go via go:linkname.
 func linkedConstructInputsSetArgs(inputs map[string]interface{}, args interface{}) error
 
+// linkedConstructInputsUnmappedKeys is made available here from ../provider_linked.go via go:linkname.
+func linkedConstructInputsUnmappedKeys(inputs map[string]interface{}, args interface{}) ([]string, error)
+
+// linkedConstructInputIsOutput is made available here from ../provider_linked.go via go:linkname.
+func linkedConstructInputIsOutput(inputs map[string]interface{}, key string) (bool, bool)
+
 // linkedNewConstructResult is made available here from ../provider_linked.go via go:linkname.
-func linkedNewConstructResult(resource pulumi.ComponentResource) (pulumi.URNInput, pulumi.Input, error)
+func linkedNewConstructResult(resource pulumi.Resource) (pulumi.URNInput, pulumi.IDInput, pulumi.Input, error)
+
+// linkedRegisterConstructResult is made available here from ../provider_linked.go via go:linkname.
+func linkedRegisterConstructResult(ctx *pulumi.Context,
+	resource pulumi.ComponentResource) (pulumi.URNInput, pulumi.IDInput, pulumi.Input, error)
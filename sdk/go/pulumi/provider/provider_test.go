@@ -0,0 +1,228 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestDeclaredOutputs(t *testing.T) {
+	outputs, err := DeclaredOutputsFromSchema([]byte(`{
+		"name": "pkg",
+		"resources": {
+			"pkg:index:Cluster": {
+				"isComponent": true,
+				"properties": {
+					"kubeconfig": {"type": "string"},
+					"endpoint": {"type": "string"}
+				}
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"pkg:index:Cluster": {"endpoint", "kubeconfig"}}, outputs)
+
+	marshal := func(state resource.PropertyMap) error {
+		rpcState, err := plugin.MarshalProperties(state, plugin.MarshalOptions{KeepUnknowns: true})
+		assert.NoError(t, err)
+		return checkDeclaredOutputs("pkg:index:Cluster", rpcState, outputs["pkg:index:Cluster"])
+	}
+
+	// A subset of the declared outputs is fine.
+	assert.NoError(t, marshal(resource.PropertyMap{"kubeconfig": resource.NewStringProperty("config")}))
+
+	// Undeclared outputs are reported by name.
+	err = marshal(resource.PropertyMap{
+		"kubeConfig": resource.NewStringProperty("config"),
+		"endpoint":   resource.NewStringProperty("https://example.com"),
+		"extra":      resource.NewStringProperty("extra"),
+	})
+	assert.EqualError(t, err,
+		"component pkg:index:Cluster produced outputs that are not declared by its schema: extra, kubeConfig")
+}
+
+func TestConstructWithoutURN(t *testing.T) {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &testEngineMonitor{})
+	})
+
+	req := &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		MonitorEndpoint: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+		Type:            "pkg:index:Component",
+		Name:            "component",
+	}
+	for _, result := range []*ConstructResult{nil, {}} {
+		_, err := Construct(context.Background(), req, nil, func(ctx *pulumi.Context, typ, name string,
+			inputs ConstructInputs, options pulumi.ResourceOption) (*ConstructResult, error) {
+			return result, nil
+		})
+		assert.EqualError(t, err, "constructing pkg:index:Component: the construct function did not return a URN")
+	}
+}
+
+// constructInvalidRequest constructs a component from a request that is missing a required field, and returns the
+// resulting error. The construct function must not be called.
+func constructInvalidRequest(t *testing.T, modify func(req *pulumirpc.ConstructRequest)) error {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &testEngineMonitor{})
+	})
+
+	req := &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		MonitorEndpoint: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+		Type:            "pkg:index:Component",
+		Name:            "component",
+	}
+	modify(req)
+
+	called := false
+	_, err := Construct(context.Background(), req, nil, func(ctx *pulumi.Context, typ, name string,
+		inputs ConstructInputs, options pulumi.ResourceOption) (*ConstructResult, error) {
+		called = true
+		return nil, nil
+	})
+	assert.False(t, called)
+	return err
+}
+
+func TestConstructMissingProject(t *testing.T) {
+	err := constructInvalidRequest(t, func(req *pulumirpc.ConstructRequest) { req.Project = "" })
+	assert.EqualError(t, err, "invalid construct request: missing project name")
+}
+
+func TestConstructMissingStack(t *testing.T) {
+	err := constructInvalidRequest(t, func(req *pulumirpc.ConstructRequest) { req.Stack = "" })
+	assert.EqualError(t, err, "invalid construct request: missing stack name")
+}
+
+func TestConstructMissingMonitor(t *testing.T) {
+	err := constructInvalidRequest(t, func(req *pulumirpc.ConstructRequest) { req.MonitorEndpoint = "" })
+	assert.EqualError(t, err, "invalid construct request: missing resource monitor address")
+}
+
+func TestConstructURNTimeout(t *testing.T) {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &testEngineMonitor{})
+	})
+
+	req := &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		MonitorEndpoint: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+		Type:            "pkg:index:Component",
+		Name:            "component",
+	}
+	opts := ConstructOptions{URNTimeout: 10 * time.Millisecond}
+	_, err := ConstructWithOptions(context.Background(), req, nil, opts, func(ctx *pulumi.Context, typ, name string,
+		inputs ConstructInputs, options pulumi.ResourceOption) (*ConstructResult, error) {
+
+		// Return a URN that never resolves.
+		pending, _, _ := pulumi.NewOutput()
+		urn := pending.ApplyT(func(v interface{}) pulumi.URN { return v.(pulumi.URN) }).(pulumi.URNOutput)
+		return &ConstructResult{URN: urn}, nil
+	})
+	assert.EqualError(t, err, "constructing pkg:index:Component: the component did not produce a URN within 10ms")
+}
+
+func TestConstructTransformations(t *testing.T) {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &testEngineMonitor{})
+	})
+
+	var transformed []string
+	opts := ConstructOptions{Transformations: []pulumi.ResourceTransformation{
+		func(args *pulumi.ResourceTransformationArgs) *pulumi.ResourceTransformationResult {
+			transformed = append(transformed, args.Type)
+			return nil
+		},
+	}}
+	req := &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		MonitorEndpoint: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+		Type:            "pkg:index:Hello",
+		Name:            "hello",
+	}
+	_, err := ConstructWithOptions(context.Background(), req, nil, opts, constructNamedComponent("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pkg:index:Hello"}, transformed)
+}
+
+// failingEngineMonitor is a testEngineMonitor that fails to register the resource with the given name.
+type failingEngineMonitor struct {
+	testEngineMonitor
+
+	name string
+}
+
+func (m *failingEngineMonitor) RegisterResource(ctx context.Context,
+	req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+	if req.GetName() == m.name {
+		return nil, fmt.Errorf("failed to register %v", m.name)
+	}
+	return m.testEngineMonitor.RegisterResource(ctx, req)
+}
+
+func TestConstructPlanFailedRegistration(t *testing.T) {
+	monitorPort := serve(t, func(srv *grpc.Server) {
+		pulumirpc.RegisterResourceMonitorServer(srv, &failingEngineMonitor{name: "child"})
+	})
+
+	var planned []PlannedResource
+	opts := ConstructOptions{Plan: func(resources []PlannedResource) {
+		planned = resources
+	}}
+	req := &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		DryRun:          true,
+		MonitorEndpoint: fmt.Sprintf("127.0.0.1:%d", monitorPort),
+		Type:            "pkg:index:Component",
+		Name:            "component",
+	}
+	_, err := ConstructWithOptions(context.Background(), req, nil, opts, func(ctx *pulumi.Context, typ, name string,
+		inputs ConstructInputs, options pulumi.ResourceOption) (*ConstructResult, error) {
+
+		var component pulumi.ResourceState
+		if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+			return nil, err
+		}
+		var child pulumi.CustomResourceState
+		if err := ctx.RegisterResource("pkg:index:Child", "child", nil, &child, pulumi.Parent(&component)); err != nil {
+			return nil, err
+		}
+		return &ConstructResult{URN: component.URN()}, nil
+	})
+
+	// The child was requested, but because its registration failed the construction fails and no plan is reported.
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to register child")
+	}
+	assert.Nil(t, planned)
+}
@@ -17,6 +17,7 @@ package pulumi
 
 import (
 	"context"
+	"time"
 	_ "unsafe" // unsafe is needed to use go:linkname
 
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
@@ -30,8 +31,16 @@ import (
 
 //go:linkname linkedConstruct github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedConstruct
 func linkedConstruct(ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
-	constructF constructFunc) (*pulumirpc.ConstructResponse, error) {
-	return construct(ctx, req, engineConn, constructF)
+	defaultVersions map[string]string, keepUnknowns, keepResources *bool, transformations []ResourceTransformation,
+	urnTimeout time.Duration, planF func(t, name string), constructF constructFunc) (*pulumirpc.ConstructResponse, error) {
+	return constructWithOptions(ctx, req, engineConn, constructOptions{
+		DefaultVersions: defaultVersions,
+		KeepUnknowns:    keepUnknowns,
+		KeepResources:   keepResources,
+		Transformations: transformations,
+		URNTimeout:      urnTimeout,
+		Plan:            planF,
+	}, constructF)
 }
 
 //go:linkname linkedConstructInputsMap github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedConstructInputsMap
@@ -44,7 +53,22 @@ func linkedConstructInputsSetArgs(inputs map[string]interface{}, args interface{
 	return constructInputsSetArgs(inputs, args)
 }
 
+//go:linkname linkedConstructInputsUnmappedKeys github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedConstructInputsUnmappedKeys
+func linkedConstructInputsUnmappedKeys(inputs map[string]interface{}, args interface{}) ([]string, error) {
+	return constructInputsUnmappedKeys(inputs, args)
+}
+
+//go:linkname linkedConstructInputIsOutput github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedConstructInputIsOutput
+func linkedConstructInputIsOutput(inputs map[string]interface{}, key string) (bool, bool) {
+	return constructInputIsOutput(inputs, key)
+}
+
 //go:linkname linkedNewConstructResult github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedNewConstructResult
-func linkedNewConstructResult(resource ComponentResource) (URNInput, Input, error) {
+func linkedNewConstructResult(resource Resource) (URNInput, IDInput, Input, error) {
 	return newConstructResult(resource)
 }
+
+//go:linkname linkedRegisterConstructResult github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider.linkedRegisterConstructResult
+func linkedRegisterConstructResult(ctx *Context, resource ComponentResource) (URNInput, IDInput, Input, error) {
+	return registerConstructResult(ctx, resource)
+}
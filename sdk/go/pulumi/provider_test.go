@@ -0,0 +1,2281 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// constructMonitor serves a mock resource monitor over gRPC so that construct can dial it. It records every
+// registration it receives so that tests can assert on the options that construct applied.
+type constructMonitor struct {
+	pulumirpc.UnimplementedResourceMonitorServer
+
+	mock *mockMonitor
+
+	// registerResourceF, if set, handles resource registrations in place of the mock.
+	registerResourceF func(req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error)
+	// supportsFeatureF, if set, reports which features the monitor supports in place of the mock.
+	supportsFeatureF func(id string) bool
+
+	lock          sync.Mutex
+	registrations []*pulumirpc.RegisterResourceRequest
+	outputs       []*pulumirpc.RegisterResourceOutputsRequest
+}
+
+func (m *constructMonitor) SupportsFeature(ctx context.Context,
+	req *pulumirpc.SupportsFeatureRequest) (*pulumirpc.SupportsFeatureResponse, error) {
+	if m.supportsFeatureF != nil {
+		return &pulumirpc.SupportsFeatureResponse{HasSupport: m.supportsFeatureF(req.GetId())}, nil
+	}
+	return m.mock.SupportsFeature(ctx, req)
+}
+
+func (m *constructMonitor) Invoke(ctx context.Context,
+	req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+	return m.mock.Invoke(ctx, req)
+}
+
+func (m *constructMonitor) ReadResource(ctx context.Context,
+	req *pulumirpc.ReadResourceRequest) (*pulumirpc.ReadResourceResponse, error) {
+	return m.mock.ReadResource(ctx, req)
+}
+
+func (m *constructMonitor) RegisterResource(ctx context.Context,
+	req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+	m.lock.Lock()
+	m.registrations = append(m.registrations, req)
+	m.lock.Unlock()
+
+	if m.registerResourceF != nil {
+		return m.registerResourceF(req)
+	}
+	return m.mock.RegisterResource(ctx, req)
+}
+
+func (m *constructMonitor) RegisterResourceOutputs(ctx context.Context,
+	req *pulumirpc.RegisterResourceOutputsRequest) (*empty.Empty, error) {
+	m.lock.Lock()
+	m.outputs = append(m.outputs, req)
+	m.lock.Unlock()
+
+	return m.mock.RegisterResourceOutputs(ctx, req)
+}
+
+// registration returns the recorded registration for the resource with the given name, if any.
+func (m *constructMonitor) registration(name string) *pulumirpc.RegisterResourceRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, req := range m.registrations {
+		if req.GetName() == name {
+			return req
+		}
+	}
+	return nil
+}
+
+// registeredOutputs returns the recorded outputs for the resource with the given URN, if any.
+func (m *constructMonitor) registeredOutputs(urn string) *pulumirpc.RegisterResourceOutputsRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, req := range m.outputs {
+		if req.GetUrn() == urn {
+			return req
+		}
+	}
+	return nil
+}
+
+// startConstructMonitor starts a constructMonitor backed by the given mocks and returns it along with its address.
+func startConstructMonitor(t *testing.T, mocks MockResourceMonitor) (*constructMonitor, string) {
+	monitor := &constructMonitor{mock: &mockMonitor{project: "project", stack: "stack", mocks: mocks}}
+
+	cancel := make(chan bool)
+	port, _, err := rpcutil.Serve(0, cancel, []func(*grpc.Server) error{
+		func(srv *grpc.Server) error {
+			pulumirpc.RegisterResourceMonitorServer(srv, monitor)
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("starting resource monitor: %v", err)
+	}
+	t.Cleanup(func() { close(cancel) })
+
+	return monitor, fmt.Sprintf("127.0.0.1:%d", port)
+}
+
+// newConstructRequest returns a ConstructRequest for a component of the given type and name that targets the monitor
+// at the given address.
+func newConstructRequest(monitorAddr, typ, name string) *pulumirpc.ConstructRequest {
+	return &pulumirpc.ConstructRequest{
+		Project:         "project",
+		Stack:           "stack",
+		MonitorEndpoint: monitorAddr,
+		Type:            typ,
+		Name:            name,
+	}
+}
+
+type testComponent struct {
+	ResourceState
+
+	Foo StringOutput `pulumi:"foo"`
+}
+
+// registerTestComponent is a constructFunc that registers a testComponent and returns it as the construct result.
+func registerTestComponent(ctx *Context, typ, name string, inputs map[string]interface{},
+	options ResourceOption) (URNInput, IDInput, Input, error) {
+
+	var component testComponent
+	if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+		return nil, nil, nil, err
+	}
+	component.Foo = String("bar").ToStringOutput()
+	if err := ctx.RegisterResourceOutputs(&component, Map{"foo": component.Foo}); err != nil {
+		return nil, nil, nil, err
+	}
+	return newConstructResult(&component)
+}
+
+func TestConstructTypeAlias(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	oldURN := "urn:pulumi:stack::project::pkg:index:OldComponent::component"
+	otherURN := "urn:pulumi:stack::project::pkg:index:Component::other"
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Aliases = []string{oldURN, otherURN}
+
+	var aliases []Alias
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			ro := &resourceOptions{}
+			options.applyResourceOption(ro)
+			aliases = ro.Aliases
+
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:pulumi:stack::project::pkg:index:Component::component", resp.GetUrn())
+
+	// The renamed type is decoded as a type-only alias, while the unrelated URN remains a URN alias.
+	if assert.Len(t, aliases, 2) {
+		assert.Nil(t, aliases[0].URN)
+		assert.Equal(t, String("pkg:index:OldComponent"), aliases[0].Type)
+		assert.Equal(t, URN(otherURN), aliases[1].URN)
+	}
+
+	// The component is registered with an alias that matches its old URN, so the engine will not replace it.
+	registration := monitor.registration("component")
+	if assert.NotNil(t, registration) {
+		assert.Equal(t, []string{oldURN, otherURN}, registration.GetAliases())
+	}
+}
+
+func TestRegisterConstructResult(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Foo = String("bar").ToStringOutput()
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	// The registered outputs must match the state returned in the response.
+	outputs := monitor.registeredOutputs(resp.GetUrn())
+	if assert.NotNil(t, outputs) {
+		registered, err := plugin.UnmarshalProperties(outputs.GetOutputs(), plugin.MarshalOptions{})
+		assert.NoError(t, err)
+		state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, state, registered)
+		assert.Equal(t, resource.PropertyMap{"foo": resource.NewStringProperty("bar")}, state)
+	}
+}
+
+func TestConstructCustomResource(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{
+		NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+			return "resource-id", resource.PropertyMap{"foo": resource.NewStringProperty("bar")}, nil
+		},
+	})
+
+	// A custom resource's URN, ID, and state are all returned in the response.
+	req := newConstructRequest(addr, "pkg:index:Resource", "resource")
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var res testResource2
+			if err := ctx.RegisterResource(typ, name, nil, &res, options); err != nil {
+				return nil, nil, nil, err
+			}
+			return newConstructResult(&res)
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:pulumi:stack::project::pkg:index:Resource::resource", resp.GetUrn())
+	assert.Equal(t, "resource-id", resp.GetId())
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{"foo": resource.NewStringProperty("bar")}, state)
+
+	// Components have no ID.
+	resp, err = constructWithOptions(context.Background(), newConstructRequest(addr, "pkg:index:Component", "component"),
+		nil, constructOptions{}, registerTestComponent)
+	assert.NoError(t, err)
+	assert.Equal(t, "", resp.GetId())
+}
+
+func TestConstructInputIsOutput(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"plain":     resource.NewStringProperty("foo"),
+		"dependent": resource.NewStringProperty("bar"),
+		"unknown":   resource.MakeComputed(resource.NewStringProperty("")),
+		"secret":    resource.MakeSecret(resource.NewStringProperty("baz")),
+	}, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	assert.NoError(t, err)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.DryRun = true
+	req.Inputs = inputs
+	req.InputDependencies = map[string]*pulumirpc.ConstructRequest_PropertyDependencies{
+		"dependent": {Urns: []string{"urn:pulumi:stack::project::pkg:index:Other::other"}},
+	}
+
+	isOutput := map[string]bool{}
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			for _, k := range []string{"plain", "dependent", "unknown", "secret"} {
+				v, ok := constructInputIsOutput(inputs, k)
+				assert.True(t, ok)
+				isOutput[k] = v
+			}
+			_, ok := constructInputIsOutput(inputs, "missing")
+			assert.False(t, ok)
+
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"plain": false, "dependent": true, "unknown": true, "secret": true}, isOutput)
+}
+
+func TestConstructDuplicateInputDependencies(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"foo": resource.NewStringProperty("bar"),
+	}, plugin.MarshalOptions{})
+	assert.NoError(t, err)
+
+	urnA := "urn:pulumi:stack::project::pkg:index:Other::a"
+	urnB := "urn:pulumi:stack::project::pkg:index:Other::b"
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Inputs = inputs
+	req.InputDependencies = map[string]*pulumirpc.ConstructRequest_PropertyDependencies{
+		"foo": {Urns: []string{urnB, urnA, urnB, urnA}},
+	}
+
+	var deps []URN
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			input, ok := inputs["foo"].(*constructInput)
+			if assert.True(t, ok) {
+				for _, dep := range input.deps {
+					urn, _, _, err := dep.URN().awaitURN(context.Background())
+					assert.NoError(t, err)
+					deps = append(deps, urn)
+				}
+			}
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []URN{URN(urnB), URN(urnA)}, deps)
+}
+
+func TestConstructInputsMapUnknowns(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"known":   resource.NewStringProperty("foo"),
+		"unknown": resource.MakeComputed(resource.NewStringProperty("")),
+		"partial": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty("bar"),
+			resource.MakeComputed(resource.NewStringProperty("")),
+		}),
+	}, plugin.MarshalOptions{KeepUnknowns: true})
+	assert.NoError(t, err)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.DryRun = true
+	req.Inputs = inputs
+
+	known := map[string]bool{}
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			for k, v := range constructInputsMap(inputs) {
+				_, isKnown, _, _, err := v.(Output).getState().await(context.Background())
+				assert.NoError(t, err)
+				known[k] = isKnown
+			}
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"known": true, "unknown": false, "partial": false}, known)
+}
+
+func TestConstructInputsSetArgsUnknowns(t *testing.T) {
+	inputs := map[string]interface{}{
+		"name": &constructInput{value: "foo", known: true},
+		"tags": &constructInput{value: nil, known: false},
+	}
+
+	var args testSetArgs
+	assert.NoError(t, constructInputsSetArgs(inputs, &args))
+
+	_, known, _, _, err := args.Name.(Output).getState().await(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, known)
+
+	_, known, _, _, err = args.Tags.(Output).getState().await(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, known)
+}
+
+func TestConstructInputsMapElementDependencies(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	urnA := resource.URN("urn:pulumi:stack::project::pkg:index:Other::a")
+	urnB := resource.URN("urn:pulumi:stack::project::pkg:index:Other::b")
+	urnC := resource.URN("urn:pulumi:stack::project::pkg:index:Other::c")
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"array": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeComponentResourceReference(urnA, ""),
+			resource.MakeComponentResourceReference(urnB, ""),
+			resource.MakeComponentResourceReference(urnA, ""),
+		}),
+		"map": resource.NewObjectProperty(resource.PropertyMap{
+			"y": resource.MakeSecret(resource.MakeComponentResourceReference(urnB, "")),
+			"x": resource.MakeComponentResourceReference(urnC, ""),
+		}),
+		"plain": resource.NewStringProperty("foo"),
+	}, plugin.MarshalOptions{KeepSecrets: true, KeepResources: true})
+	assert.NoError(t, err)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Inputs = inputs
+	req.InputDependencies = map[string]*pulumirpc.ConstructRequest_PropertyDependencies{
+		"array": {Urns: []string{string(urnB)}},
+	}
+
+	deps := map[string][]URN{}
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			for k, v := range constructInputsMap(inputs) {
+				_, _, _, resources, err := v.(Output).getState().await(context.Background())
+				assert.NoError(t, err)
+				urns := []URN{}
+				for _, res := range resources {
+					urn, _, _, err := res.URN().awaitURN(context.Background())
+					assert.NoError(t, err)
+					urns = append(urns, urn)
+				}
+				deps[k] = urns
+			}
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+
+	// Each input depends on its own dependencies followed by any other resources referenced by its elements, without
+	// duplicates.
+	assert.Equal(t, map[string][]URN{
+		"array": {URN(urnB), URN(urnA)},
+		"map":   {URN(urnC), URN(urnB)},
+		"plain": {},
+	}, deps)
+}
+
+func TestConstructInputsSetArgsElementDependencies(t *testing.T) {
+	resA := newDependencyResource("urn:pulumi:stack::project::pkg:index:Other::a")
+	resB := newDependencyResource("urn:pulumi:stack::project::pkg:index:Other::b")
+	inputs := map[string]interface{}{
+		"tags": &constructInput{
+			value:       map[string]interface{}{"a": resA, "b": resB},
+			known:       true,
+			deps:        []Resource{resB},
+			elementDeps: []Resource{resA},
+		},
+	}
+
+	var args testSetArgs
+	assert.NoError(t, constructInputsSetArgs(inputs, &args))
+
+	// The field depends on the same resources as the corresponding entry in constructInputsMap.
+	_, _, _, deps, err := args.Tags.(Output).getState().await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Resource{resB, resA}, deps)
+
+	_, _, _, deps, err = constructInputsMap(inputs)["tags"].(Output).getState().await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Resource{resB, resA}, deps)
+}
+
+func TestConstructMalformedProviders(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Providers = map[string]string{
+		"pkgC": "malformed-c",
+		"pkgA": "malformed-a",
+		"pkgB": "malformed-b",
+	}
+
+	// The first malformed reference by package name is always the one that is reported.
+	for i := 0; i < 10; i++ {
+		_, err := constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+		assert.EqualError(t, err, "parsing provider for package pkgA: expected '::' in provider reference malformed-a")
+
+		var refErr *ProviderReferenceError
+		if assert.True(t, errors.As(err, &refErr)) {
+			assert.Equal(t, "malformed-a", refErr.Ref)
+		}
+	}
+}
+
+func TestParseProviderReference(t *testing.T) {
+	urn, id, err := ParseProviderReference("urn:pulumi:stack::project::pulumi:providers:pkgA::prov::prov-id")
+	assert.NoError(t, err)
+	assert.Equal(t, URN("urn:pulumi:stack::project::pulumi:providers:pkgA::prov"), urn)
+	assert.Equal(t, ID("prov-id"), id)
+
+	_, _, err = ParseProviderReference("malformed")
+	assert.Equal(t, &ProviderReferenceError{Ref: "malformed"}, err)
+}
+
+func TestProviderReferenceRoundTrip(t *testing.T) {
+	cases := []struct {
+		urn URN
+		id  ID
+	}{
+		{"urn:pulumi:stack::project::pulumi:providers:pkgA::prov", "prov-id"},
+		{"urn:pulumi:stack::project::pkgA:m:typA$pulumi:providers:pkgA::prov", "prov-id"},
+		{"urn:pulumi:stack::project::pulumi:providers:pkgA::prov", ""},
+		{"urn:pulumi:stack::project::pulumi:providers:pkgA::prov", rpcTokenUnknownValue},
+		{"::", "id"},
+	}
+	for _, c := range cases {
+		ref := FormatProviderReference(c.urn, c.id)
+		urn, id, err := ParseProviderReference(ref)
+		assert.NoError(t, err, ref)
+		assert.Equal(t, c.urn, urn, ref)
+		assert.Equal(t, c.id, id, ref)
+		assert.Equal(t, ref, FormatProviderReference(urn, id))
+	}
+}
+
+func TestConstructMergeOptions(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	depURN := "urn:pulumi:stack::project::pkg:index:Other::dep"
+	extraURN := "urn:pulumi:stack::project::pkg:index:Other::extra"
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Dependencies = []string{depURN}
+
+	// The component's own options are layered on top of the options passed to construct.
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			merged := MergeOptions(options, DependsOn([]Resource{newDependencyResource(URN(extraURN))}), Protect(true))
+			return registerTestComponent(ctx, typ, name, inputs, merged)
+		})
+	assert.NoError(t, err)
+
+	registration := monitor.registration("component")
+	if assert.NotNil(t, registration) {
+		assert.Equal(t, []string{depURN, extraURN}, registration.GetDependencies())
+		assert.True(t, registration.GetProtect())
+	}
+}
+
+func TestConstructDefaultVersions(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Providers = map[string]string{
+		"pkgB": "urn:pulumi:stack::project::pulumi:providers:pkgB::explicit::provider-id",
+	}
+
+	defaultVersions := map[string]string{"pkgA": "1.2.3", "pkgB": "2.0.0"}
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{DefaultVersions: defaultVersions}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			var a, b, c testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "a", nil, &a, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgB:m:typB", "b", nil, &b, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgA:m:typA", "c", nil, &c, Parent(&component),
+				Version("9.9.9")); err != nil {
+				return nil, nil, nil, err
+			}
+
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	// Resources without an explicit provider or version pick up the default version for their package.
+	if a := monitor.registration("a"); assert.NotNil(t, a) {
+		assert.Equal(t, "1.2.3", a.GetVersion())
+		assert.Equal(t, "", a.GetProvider())
+	}
+
+	// Explicit providers from the request take precedence over the default version.
+	if b := monitor.registration("b"); assert.NotNil(t, b) {
+		assert.Equal(t, "", b.GetVersion())
+		assert.Equal(t, req.Providers["pkgB"], b.GetProvider())
+	}
+
+	// Explicit versions take precedence over the default version.
+	if c := monitor.registration("c"); assert.NotNil(t, c) {
+		assert.Equal(t, "9.9.9", c.GetVersion())
+	}
+}
+
+func TestConstructOutputMarshalError(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// The value of "kubeconfig" resolves, but the URN of the resource it depends on does not.
+			var dep ResourceState
+			dep.urn.OutputState = newOutputState(dep.urn.ElementType(), &dep)
+			dep.urn.reject(errors.New("boom"))
+
+			kubeconfig := StringOutput{newOutputState(reflect.TypeOf(""), &dep)}
+			kubeconfig.resolve("config", true, false, nil)
+
+			return component.URN(), nil, Map{"good": String("value"), "kubeconfig": kubeconfig}, nil
+		})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "kubeconfig")
+		assert.Contains(t, err.Error(), "boom")
+	}
+}
+
+func TestConstructPlan(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	for _, dryRun := range []bool{true, false} {
+		req := newConstructRequest(addr, "pkg:index:Component", "component")
+		req.DryRun = dryRun
+
+		var planned []string
+		plan := func(t, name string) {
+			planned = append(planned, t+"::"+name)
+		}
+		_, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{Plan: plan}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				var a, b testResource2
+				if err := ctx.RegisterResource("pkgA:m:typA", "a", nil, &a, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				if err := ctx.ReadResource("pkgA:m:typA", "b", ID("id"), nil, &b, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+
+				return registerConstructResult(ctx, &component)
+			})
+		assert.NoError(t, err)
+
+		// The children are reported only during previews, and the component itself is never reported.
+		if dryRun {
+			assert.Equal(t, []string{"pkgA:m:typA::a", "pkgA:m:typA::b"}, planned)
+		} else {
+			assert.Empty(t, planned)
+		}
+	}
+}
+
+type testColor String
+
+const (
+	testColorRed  = testColor("red")
+	testColorBlue = testColor("blue")
+)
+
+func (testColor) EnumValues() []interface{} {
+	return []interface{}{testColorRed, testColorBlue}
+}
+
+func (testColor) ElementType() reflect.Type {
+	return reflect.TypeOf((*String)(nil)).Elem()
+}
+
+type testSize Float64
+
+func (testSize) ElementType() reflect.Type {
+	return reflect.TypeOf((*Float64)(nil)).Elem()
+}
+
+type testEnumArgs struct {
+	Color    testColor  `pulumi:"color"`
+	OptColor *testColor `pulumi:"optColor"`
+	Size     testSize   `pulumi:"size"`
+	Name     String     `pulumi:"name"`
+}
+
+func TestConstructInputsSetArgsEnums(t *testing.T) {
+	inputs := map[string]interface{}{
+		"color":    &constructInput{value: "blue", known: true},
+		"optColor": &constructInput{value: "red", known: true},
+		"size":     &constructInput{value: 4.0, known: true},
+		"name":     &constructInput{value: "foo", known: true},
+	}
+
+	var args testEnumArgs
+	assert.NoError(t, constructInputsSetArgs(inputs, &args))
+	assert.Equal(t, testColorBlue, args.Color)
+	if assert.NotNil(t, args.OptColor) {
+		assert.Equal(t, testColorRed, *args.OptColor)
+	}
+	assert.Equal(t, testSize(4), args.Size)
+	assert.Equal(t, String("foo"), args.Name)
+
+	// Unknown values leave the field unset.
+	args = testEnumArgs{}
+	assert.NoError(t, constructInputsSetArgs(map[string]interface{}{
+		"color": &constructInput{value: "", known: false},
+	}, &args))
+	assert.Equal(t, testColor(""), args.Color)
+
+	// Values that are not members of the enum are rejected.
+	err := constructInputsSetArgs(map[string]interface{}{
+		"color": &constructInput{value: "green", known: true},
+	}, &args)
+	assert.EqualError(t, err, "invalid value green for input color of type pulumi.testColor")
+
+	// Values of the wrong type are rejected.
+	err = constructInputsSetArgs(map[string]interface{}{
+		"size": &constructInput{value: "big", known: true},
+	}, &args)
+	assert.Error(t, err)
+}
+
+type testSetArgs struct {
+	Color testColor   `pulumi:"color"`
+	Name  StringInput `pulumi:"name"`
+	Tags  MapInput    `pulumi:"tags"`
+	name  StringInput `pulumi:"hidden"`
+}
+
+type testPlainArgs struct {
+	Name     *string            `pulumi:"name"`
+	Count    int                `pulumi:"count"`
+	Ratio    float32            `pulumi:"ratio"`
+	Enabled  bool               `pulumi:"enabled"`
+	Zones    []string           `pulumi:"zones"`
+	Tags     map[string]string  `pulumi:"tags"`
+	Matrix   [][]int            `pulumi:"matrix"`
+	Extra    interface{}        `pulumi:"extra"`
+	Optional *map[string]*int   `pulumi:"optional"`
+	Pending  []string           `pulumi:"pending"`
+	Input    StringInput        `pulumi:"input"`
+	Nested   map[string][]bool  `pulumi:"nested"`
+	Unset    map[string]float64 `pulumi:"unset"`
+}
+
+func TestConstructInputsSetArgsPlainFields(t *testing.T) {
+	inputs := map[string]interface{}{
+		"name":    &constructInput{value: "foo", known: true},
+		"count":   &constructInput{value: 3.0, known: true},
+		"ratio":   &constructInput{value: 0.5, known: true},
+		"enabled": &constructInput{value: true, known: true},
+		"zones":   &constructInput{value: []interface{}{"a", "b"}, known: true},
+		"tags":    &constructInput{value: map[string]interface{}{"env": "dev"}, known: true},
+		"matrix": &constructInput{value: []interface{}{
+			[]interface{}{1.0, 2.0},
+			[]interface{}{3.0},
+		}, known: true},
+		"extra":    &constructInput{value: map[string]interface{}{"x": 1.0}, known: true},
+		"optional": &constructInput{value: map[string]interface{}{"a": 1.0, "b": nil}, known: true},
+		"pending":  &constructInput{known: false},
+		"input":    &constructInput{value: "bar", known: true},
+		"nested":   &constructInput{value: map[string]interface{}{"a": []interface{}{true}}, known: true},
+	}
+
+	var args testPlainArgs
+	assert.NoError(t, constructInputsSetArgs(inputs, &args))
+	if assert.NotNil(t, args.Name) {
+		assert.Equal(t, "foo", *args.Name)
+	}
+	assert.Equal(t, 3, args.Count)
+	assert.Equal(t, float32(0.5), args.Ratio)
+	assert.True(t, args.Enabled)
+	assert.Equal(t, []string{"a", "b"}, args.Zones)
+	assert.Equal(t, map[string]string{"env": "dev"}, args.Tags)
+	assert.Equal(t, [][]int{{1, 2}, {3}}, args.Matrix)
+	assert.Equal(t, map[string]interface{}{"x": 1.0}, args.Extra)
+	if assert.NotNil(t, args.Optional) {
+		one := 1
+		assert.Equal(t, map[string]*int{"a": &one, "b": nil}, *args.Optional)
+	}
+	assert.Nil(t, args.Pending)
+	assert.NotNil(t, args.Input)
+	assert.Equal(t, map[string][]bool{"a": {true}}, args.Nested)
+	assert.Nil(t, args.Unset)
+
+	// Values whose kind does not match the field's kind are rejected, and the field is left unchanged.
+	for key, value := range map[string]interface{}{
+		"count":   "three",
+		"zones":   "a",
+		"tags":    []interface{}{"dev"},
+		"matrix":  []interface{}{[]interface{}{"1"}},
+		"nested":  map[string]interface{}{"a": []interface{}{1.0}},
+		"enabled": 1.0,
+	} {
+		args := testPlainArgs{Count: 7}
+		err := constructInputsSetArgs(map[string]interface{}{key: &constructInput{value: value, known: true}}, &args)
+		assert.Error(t, err, key)
+		assert.Equal(t, testPlainArgs{Count: 7}, args, key)
+	}
+	err := constructInputsSetArgs(map[string]interface{}{
+		"matrix": &constructInput{value: []interface{}{[]interface{}{1.0, "2"}}, known: true},
+	}, &args)
+	assert.EqualError(t, err, "cannot assign input matrix[0][1] of type string to a field of type int")
+	err = constructInputsSetArgs(map[string]interface{}{
+		"count": &constructInput{value: 1.5, known: true},
+	}, &args)
+	assert.EqualError(t, err, "cannot assign input count with non-integer value 1.5 to a field of type int")
+
+	// Secret values cannot be assigned to plain fields.
+	err = constructInputsSetArgs(map[string]interface{}{
+		"name": &constructInput{value: "foo", known: true, secret: true},
+	}, &args)
+	assert.EqualError(t, err, "input name is secret and cannot be assigned to a field of type *string")
+}
+
+func TestConstructInputsUnmappedKeys(t *testing.T) {
+	inputs := map[string]interface{}{
+		"color":  &constructInput{value: "red", known: true},
+		"name":   &constructInput{value: "foo", known: true},
+		"nmae":   &constructInput{value: "foo", known: true},
+		"hidden": &constructInput{value: "bar", known: true},
+	}
+
+	// Inputs that match no settable field, including those that match unexported fields, are reported.
+	var args testSetArgs
+	unmapped, err := constructInputsUnmappedKeys(inputs, &args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hidden", "nmae"}, unmapped)
+
+	unmapped, err = constructInputsUnmappedKeys(map[string]interface{}{}, &args)
+	assert.NoError(t, err)
+	assert.Empty(t, unmapped)
+
+	_, err = constructInputsUnmappedKeys(inputs, args)
+	assert.EqualError(t, err, "args must be a pointer to a struct")
+}
+
+func TestConstructInputsSetArgsConcurrent(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"color":  resource.NewStringProperty("red"),
+		"name":   resource.NewStringProperty("foo"),
+		"tags":   resource.NewObjectProperty(resource.PropertyMap{"a": resource.NewStringProperty("b")}),
+		"hidden": resource.NewStringProperty("hidden"),
+	}, plugin.MarshalOptions{})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			req := newConstructRequest(addr, "pkg:index:Component", fmt.Sprintf("component-%d", i))
+			req.Inputs = inputs
+			_, err := constructWithOptions(context.Background(), req, nil,
+				constructOptions{}, func(ctx *Context, typ, name string,
+					inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+					var args testSetArgs
+					if err := constructInputsSetArgs(inputs, &args); err != nil {
+						return nil, nil, nil, err
+					}
+					assert.Equal(t, testColorRed, args.Color)
+					assert.Nil(t, args.name)
+
+					v, known, _, _, err := await(args.Name.ToStringOutput())
+					assert.NoError(t, err)
+					assert.True(t, known)
+					assert.Equal(t, "foo", v)
+
+					return registerTestComponent(ctx, typ, name, inputs, options)
+				})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkConstructInputsSetArgs(b *testing.B) {
+	inputs := map[string]interface{}{
+		"color": &constructInput{value: "red", known: true},
+		"name":  &constructInput{value: "foo", known: true},
+		"tags":  &constructInput{value: map[string]interface{}{"a": "b"}, known: true},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var args testSetArgs
+		if err := constructInputsSetArgs(inputs, &args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConstructPreviewUnknownOutputs(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	// During a preview, the outputs of a resource that is yet to be created are unknown.
+	monitor.registerResourceF = func(req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+		urn := monitor.mock.newURN(req.GetParent(), req.GetType(), req.GetName())
+		if !req.GetCustom() {
+			return &pulumirpc.RegisterResourceResponse{Urn: urn}, nil
+		}
+		object, err := plugin.MarshalProperties(resource.PropertyMap{
+			"foo": resource.MakeComputed(resource.NewStringProperty("")),
+		}, plugin.MarshalOptions{KeepUnknowns: true})
+		if err != nil {
+			return nil, err
+		}
+		return &pulumirpc.RegisterResourceResponse{Urn: urn, Id: plugin.UnknownStringValue, Object: object}, nil
+	}
+
+	unknown := resource.PropertyMap{"foo": resource.MakeComputed(resource.NewStringProperty(""))}
+
+	for _, register := range []bool{true, false} {
+		name := fmt.Sprintf("component-%v", register)
+		req := newConstructRequest(addr, "pkg:index:Component", name)
+		req.DryRun = true
+		resp, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				var child testResource2
+				if err := ctx.RegisterResource("pkgA:m:typA", name+"-child", nil, &child,
+					Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				component.Foo = child.Foo.ApplyT(func(v string) string { return v + "!" }).(StringOutput)
+
+				if register {
+					return registerConstructResult(ctx, &component)
+				}
+				if err := ctx.RegisterResourceOutputs(&component, Map{"foo": component.Foo}); err != nil {
+					return nil, nil, nil, err
+				}
+				return newConstructResult(&component)
+			})
+		assert.NoError(t, err)
+
+		// Both the response and the registered outputs report the output as unknown.
+		state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepUnknowns: true})
+		assert.NoError(t, err)
+		assert.Equal(t, unknown, state)
+
+		outputs := monitor.registeredOutputs(resp.GetUrn())
+		if assert.NotNil(t, outputs) {
+			registered, err := plugin.UnmarshalProperties(outputs.GetOutputs(),
+				plugin.MarshalOptions{KeepUnknowns: true})
+			assert.NoError(t, err)
+			assert.Equal(t, unknown, registered)
+		}
+
+		// The component depends on the child whose output it exposes.
+		if assert.Contains(t, resp.GetStateDependencies(), "foo") {
+			assert.Len(t, resp.GetStateDependencies()["foo"].GetUrns(), 1)
+		}
+	}
+}
+
+func TestConstructFeatures(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	cases := []struct {
+		name     string
+		features map[string]bool
+		expected EngineFeatures
+	}{
+		{"all", map[string]bool{"secrets": true, "resourceReferences": true}, EngineFeatures{true, true}},
+		{"secrets", map[string]bool{"secrets": true}, EngineFeatures{Secrets: true}},
+		{"none", map[string]bool{}, EngineFeatures{}},
+	}
+	for _, c := range cases {
+		monitor.supportsFeatureF = func(id string) bool { return c.features[id] }
+
+		var features EngineFeatures
+		req := newConstructRequest(addr, "pkg:index:Component", c.name)
+		resp, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				features = ctx.Features()
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				var child testInstanceResource
+				if err := ctx.RegisterResource("pkg:index:Instance", name+"-child", &testInstanceResourceInputs{},
+					&child, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				return component.URN(), nil, Map{"child": &child}, nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, features)
+
+		// Without resource reference support, the child is flattened to its ID.
+		state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepResources: true})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected.ResourceReferences, state["child"].IsResourceReference(), c.name)
+	}
+}
+
+func TestConstructKeepResources(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	keep, drop := true, false
+	cases := []struct {
+		name          string
+		supported     bool
+		keepResources *bool
+		expected      bool
+	}{
+		{"negotiated", true, nil, true},
+		{"unsupported", false, nil, false},
+		{"forced-on", false, &keep, true},
+		{"forced-off", true, &drop, false},
+	}
+	for _, c := range cases {
+		monitor.supportsFeatureF = func(id string) bool { return c.supported }
+
+		req := newConstructRequest(addr, "pkg:index:Component", c.name)
+		resp, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{KeepResources: c.keepResources}, func(ctx *Context,
+				typ, name string, inputs map[string]interface{},
+				options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				var child testInstanceResource
+				if err := ctx.RegisterResource("pkg:index:Instance", name+"-child", &testInstanceResourceInputs{},
+					&child, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				return component.URN(), nil, Map{"child": &child}, nil
+			})
+		assert.NoError(t, err)
+
+		state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepResources: true})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, state["child"].IsResourceReference(), c.name)
+	}
+}
+
+func TestConstructKeepUnknowns(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	keep, drop := true, false
+	cases := []struct {
+		name         string
+		dryRun       bool
+		keepUnknowns *bool
+		expected     bool
+	}{
+		{"preview", true, nil, true},
+		{"update", false, nil, false},
+		{"forced-on", false, &keep, true},
+		{"forced-off", true, &drop, false},
+	}
+	for _, c := range cases {
+		req := newConstructRequest(addr, "pkg:index:Component", c.name)
+		req.DryRun = c.dryRun
+		resp, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{KeepUnknowns: c.keepUnknowns}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				unknown := newOutput(anyOutputType)
+				unknown.getState().resolve(nil, false, false, nil)
+				return component.URN(), nil, Map{"unknown": unknown}, nil
+			})
+		assert.NoError(t, err)
+
+		state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepUnknowns: true})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, state["unknown"].IsComputed(), c.name)
+	}
+}
+
+func TestConstructTransformations(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	// The transformation protects every custom resource.
+	var transformed []string
+	protect := func(args *ResourceTransformationArgs) *ResourceTransformationResult {
+		transformed = append(transformed, args.Name)
+		if _, ok := args.Resource.(CustomResource); !ok {
+			return nil
+		}
+		return &ResourceTransformationResult{Props: args.Props, Opts: append(args.Opts, Protect(true))}
+	}
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{Transformations: []ResourceTransformation{protect}}, func(ctx *Context, typ,
+			name string, inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// Children are transformed whether they are registered with the request's options or only with the
+			// component as their parent.
+			var child, other testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "child", nil, &child, options,
+				Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgA:m:typA", "other", nil, &other, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"component", "child", "other"}, transformed)
+	if reg := monitor.registration("component"); assert.NotNil(t, reg) {
+		assert.False(t, reg.GetProtect())
+	}
+	for _, name := range []string{"child", "other"} {
+		if reg := monitor.registration(name); assert.NotNil(t, reg, name) {
+			assert.True(t, reg.GetProtect(), name)
+		}
+	}
+}
+
+func TestConstructAutonamingPrefix(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	for name, prefix := range map[string]string{"plain": "", "prefixed": "team"} {
+		req := newConstructRequest(addr, "pkg:index:Component", name)
+		if prefix != "" {
+			req.Config = map[string]string{AutonamingPrefixConfigKey: prefix}
+		}
+		_, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				assert.Equal(t, prefix, ctx.AutonamingPrefix())
+
+				var component testComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+
+				var child testResource2
+				if err := ctx.RegisterResource("pkgA:m:typA", ctx.ChildName(name+"-child"), nil, &child,
+					Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				return registerConstructResult(ctx, &component)
+			})
+		assert.NoError(t, err)
+	}
+
+	assert.NotNil(t, monitor.registration("plain-child"))
+	assert.NotNil(t, monitor.registration("team-prefixed-child"))
+
+	// A prefix set on the RunInfo takes precedence over config.
+	ctx, err := NewContext(context.Background(), RunInfo{
+		Config:           map[string]string{AutonamingPrefixConfigKey: "config"},
+		AutonamingPrefix: "info",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "info-child", ctx.ChildName("child"))
+}
+
+func TestConstructRefresh(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	for _, refresh := range []bool{false, true} {
+		req := newConstructRequest(addr, "pkg:index:Component", fmt.Sprintf("component-%v", refresh))
+		req.Refresh = refresh
+		_, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				assert.Equal(t, refresh, ctx.IsRefresh())
+				return registerTestComponent(ctx, typ, name, inputs, options)
+			})
+		assert.NoError(t, err)
+	}
+}
+
+func TestConstructConfigMetadata(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	rotated := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+	for name, md := range map[string]map[string]*pulumirpc.ConstructRequest_ConfigMetadata{
+		"unsupported": nil,
+		"supported": {
+			"pkg:token":  {Version: "3", LastRotated: rotated.Format(time.RFC3339)},
+			"pkg:region": {Version: "1"},
+		},
+	} {
+		req := newConstructRequest(addr, "pkg:index:Component", name)
+		req.ConfigMetadata = md
+		_, err := constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				token, ok, err := ctx.GetConfigMetadata("pkg:token")
+				if md == nil {
+					assert.Equal(t, ErrConfigMetadataUnsupported, err)
+					assert.False(t, ok)
+				} else {
+					assert.NoError(t, err)
+					assert.True(t, ok)
+					assert.Equal(t, ConfigMetadata{Version: "3", LastRotated: rotated}, token)
+
+					region, ok, err := ctx.GetConfigMetadata("pkg:region")
+					assert.NoError(t, err)
+					assert.True(t, ok)
+					assert.True(t, region.LastRotated.IsZero())
+
+					_, ok, err = ctx.GetConfigMetadata("pkg:missing")
+					assert.NoError(t, err)
+					assert.False(t, ok)
+				}
+				return registerTestComponent(ctx, typ, name, inputs, options)
+			})
+		assert.NoError(t, err)
+	}
+
+	// A malformed rotation time is an error.
+	req := newConstructRequest(addr, "pkg:index:Component", "malformed")
+	req.ConfigMetadata = map[string]*pulumirpc.ConstructRequest_ConfigMetadata{
+		"pkg:token": {LastRotated: "yesterday"},
+	}
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.Error(t, err)
+}
+
+func TestConstructCustomTimeouts(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.CustomTimeouts = &pulumirpc.ConstructRequest_CustomTimeouts{Create: "5m", Delete: "1h30m"}
+	_, err := constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.NoError(t, err)
+
+	// The timeouts are applied to the resources that are registered with the options.
+	if reg := monitor.registration("component"); assert.NotNil(t, reg) {
+		assert.Equal(t, &pulumirpc.RegisterResourceRequest_CustomTimeouts{Create: "5m", Delete: "1h30m"},
+			reg.GetCustomTimeouts())
+	}
+
+	// Malformed timeouts are errors.
+	req = newConstructRequest(addr, "pkg:index:Component", "malformed")
+	req.CustomTimeouts = &pulumirpc.ConstructRequest_CustomTimeouts{Update: "ten minutes"}
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.EqualError(t, err, `malformed update timeout: time: invalid duration "ten minutes"`)
+}
+
+func TestConstructInvalidRunInfo(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	cases := []struct {
+		name     string
+		modify   func(req *pulumirpc.ConstructRequest)
+		expected string
+	}{
+		{"project", func(req *pulumirpc.ConstructRequest) { req.Project = "" }, "missing project name"},
+		{"stack", func(req *pulumirpc.ConstructRequest) { req.Stack = "" }, "missing stack name"},
+		{"monitor", func(req *pulumirpc.ConstructRequest) { req.MonitorEndpoint = "" },
+			"missing resource monitor address"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := newConstructRequest(addr, "pkg:index:Component", "component")
+			c.modify(req)
+
+			called := false
+			_, err := constructWithOptions(context.Background(), req, nil,
+				constructOptions{}, func(ctx *Context, typ, name string,
+					inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+					called = true
+					return registerTestComponent(ctx, typ, name, inputs, options)
+				})
+			assert.EqualError(t, err, "invalid construct request: "+c.expected)
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestConstructErrorRegisteredURNs(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+	monitor.registerResourceF = func(req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+		if req.GetName() == "bad" {
+			return nil, errors.New("registration failed")
+		}
+		return monitor.mock.RegisterResource(context.Background(), req)
+	}
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			for _, child := range []string{"good", "bad"} {
+				var res testComponent
+				if err := ctx.RegisterComponentResource("pkg:index:Child", child, &res, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			return newConstructResult(&component)
+		})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting for RPCs")
+
+	// Only the child that was registered successfully is reported; the component itself is not.
+	var constructErr *ConstructError
+	if assert.True(t, errors.As(err, &constructErr)) {
+		assert.Equal(t, []URN{"urn:pulumi:stack::project::pkg:index:Component$pkg:index:Child::good"},
+			constructErr.RegisteredURNs())
+	}
+}
+
+func TestConstructURNTimeout(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	// A construct function that returns a URN that never resolves.
+	pending := func(ctx *Context, typ, name string, inputs map[string]interface{},
+		options ResourceOption) (URNInput, IDInput, Input, error) {
+
+		return URNOutput{newOutputState(urnType)}, nil, nil, nil
+	}
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{URNTimeout: 10 * time.Millisecond}, pending)
+	assert.EqualError(t, err, "constructing pkg:index:Component: the component did not produce a URN within 10ms")
+
+	// The timeout can also be set by the environment.
+	oldTimeout, hadTimeout := os.LookupEnv(EnvConstructURNTimeout)
+	defer func() {
+		if hadTimeout {
+			os.Setenv(EnvConstructURNTimeout, oldTimeout)
+		} else {
+			os.Unsetenv(EnvConstructURNTimeout)
+		}
+	}()
+	os.Setenv(EnvConstructURNTimeout, "20ms")
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, pending)
+	assert.EqualError(t, err, "constructing pkg:index:Component: the component did not produce a URN within 20ms")
+
+	// A URN that resolves in time is unaffected by the timeout.
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.NoError(t, err)
+
+	// A malformed timeout is an error.
+	os.Setenv(EnvConstructURNTimeout, "soon")
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.EqualError(t, err, `parsing PULUMI_CONSTRUCT_URN_TIMEOUT: time: invalid duration "soon"`)
+}
+
+type recordingConstructLogger struct {
+	events []string
+	fields []map[string]interface{}
+}
+
+func (l *recordingConstructLogger) LogConstructEvent(event string, fields map[string]interface{}) {
+	l.events = append(l.events, event)
+	l.fields = append(l.fields, fields)
+}
+
+func TestConstructLogger(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"b":       resource.NewStringProperty("foo"),
+		"a":       resource.NewStringProperty("bar"),
+		"unknown": resource.MakeComputed(resource.NewStringProperty("")),
+	}, plugin.MarshalOptions{KeepUnknowns: true})
+	assert.NoError(t, err)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Inputs = inputs
+	req.Dependencies = []string{"urn:pulumi:stack::project::pkg:index:Other::other"}
+	req.Providers = map[string]string{
+		"pkgB": "urn:pulumi:stack::project::pulumi:providers:pkgB::b::id-b",
+		"pkgA": "urn:pulumi:stack::project::pulumi:providers:pkgA::a::id-a",
+	}
+
+	// By default, there is no logger.
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.NoError(t, err)
+
+	logger := &recordingConstructLogger{}
+	SetConstructLogger(logger)
+	defer SetConstructLogger(nil)
+
+	req.Name = "logged"
+	_, err = constructWithOptions(context.Background(), req, nil, constructOptions{}, registerTestComponent)
+	assert.NoError(t, err)
+
+	// Unknown inputs are skipped outside of previews, so they are not logged.
+	assert.Equal(t, []string{"inputs deserialized", "options rebuilt", "state marshaled"}, logger.events)
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "pkg:index:Component", "name": "logged", "count": 2, "keys": []string{"a", "b"}},
+		{"parent": "", "providers": []string{"pkgA", "pkgB"}, "dependencies": 1, "aliases": 0},
+		{"properties": 1, "dependencies": 0},
+	}, logger.fields)
+}
+
+func TestConstructAdditionalSecretOutputs(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.AdditionalSecretOutputs = []string{"password"}
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// A child that is registered with the request's options also treats the outputs as secret.
+			var child testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "child", nil, &child, options,
+				Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	for _, name := range []string{"component", "child"} {
+		if reg := monitor.registration(name); assert.NotNil(t, reg, name) {
+			assert.Equal(t, []string{"password"}, reg.GetAdditionalSecretOutputs(), name)
+		}
+	}
+}
+
+func TestConstructDeleteBeforeReplace(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.DeleteBeforeReplace = true
+	_, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// A child that is registered with the request's options is deleted before it is replaced, but one that is
+			// registered without them is not.
+			var child, other testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "child", nil, &child, options,
+				Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgA:m:typA", "other", nil, &other, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	if reg := monitor.registration("child"); assert.NotNil(t, reg) {
+		assert.True(t, reg.GetDeleteBeforeReplace())
+	}
+	if reg := monitor.registration("other"); assert.NotNil(t, reg) {
+		assert.False(t, reg.GetDeleteBeforeReplace())
+	}
+}
+
+type testTokenArgs struct {
+	Token StringInput `pulumi:"token"`
+}
+
+func TestConstructSecretArgs(t *testing.T) {
+	monitor, addr := startConstructMonitor(t, &testMonitor{})
+
+	inputs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"token": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+	}, plugin.MarshalOptions{KeepSecrets: true})
+	assert.NoError(t, err)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Inputs = inputs
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var args testTokenArgs
+			if err := constructInputsSetArgs(inputs, &args); err != nil {
+				return nil, nil, nil, err
+			}
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Foo = args.Token.ToStringOutput().ApplyT(func(token string) string {
+				return "Bearer " + token
+			}).(StringOutput)
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	// The value derived from the secret input is still secret, both in the response and in the registered outputs.
+	expected := resource.PropertyMap{"foo": resource.MakeSecret(resource.NewStringProperty("Bearer hunter2"))}
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepSecrets: true})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	if outputs := monitor.registeredOutputs(resp.GetUrn()); assert.NotNil(t, outputs) {
+		registered, err := plugin.UnmarshalProperties(outputs.GetOutputs(), plugin.MarshalOptions{KeepSecrets: true})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, registered)
+	}
+}
+
+type testEngine struct {
+	pulumirpc.UnimplementedEngineServer
+
+	mu   sync.Mutex
+	logs []*pulumirpc.LogRequest
+}
+
+func (e *testEngine) Log(ctx context.Context, req *pulumirpc.LogRequest) (*empty.Empty, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logs = append(e.logs, req)
+	return &empty.Empty{}, nil
+}
+
+// startTestEngine starts a testEngine and returns it along with a connection to it.
+func startTestEngine(t *testing.T) (*testEngine, *grpc.ClientConn) {
+	engine := &testEngine{}
+
+	cancel := make(chan bool)
+	port, _, err := rpcutil.Serve(0, cancel, []func(*grpc.Server) error{
+		func(srv *grpc.Server) error {
+			pulumirpc.RegisterEngineServer(srv, engine)
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("starting engine: %v", err)
+	}
+	t.Cleanup(func() { close(cancel) })
+
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithInsecure(), rpcutil.GrpcChannelOptions())
+	if err != nil {
+		t.Fatalf("connecting to engine: %v", err)
+	}
+	t.Cleanup(func() { contract.IgnoreClose(conn) })
+
+	return engine, conn
+}
+
+func TestConstructWarnings(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+	engine, engineConn := startTestEngine(t)
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	resp, err := constructWithOptions(context.Background(), req, engineConn,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.Log.Warn("input 'size' is deprecated", &LogArgs{Resource: &component}); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Foo = String("bar").ToStringOutput()
+			return registerConstructResult(ctx, &component)
+		})
+
+	// The warning does not fail construction, and is reported against the component.
+	assert.NoError(t, err)
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if assert.Len(t, engine.logs, 1) {
+		assert.Equal(t, pulumirpc.LogSeverity_WARNING, engine.logs[0].GetSeverity())
+		assert.Equal(t, "input 'size' is deprecated", engine.logs[0].GetMessage())
+		assert.Equal(t, resp.GetUrn(), engine.logs[0].GetUrn())
+	}
+
+	// Without an engine, the warning is dropped rather than failing construction.
+	req = newConstructRequest(addr, "pkg:index:Component", "component2")
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			assert.NoError(t, ctx.Log.Warn("input 'size' is deprecated", nil))
+			return registerTestComponent(ctx, typ, name, inputs, options)
+		})
+	assert.NoError(t, err)
+}
+
+// unmarshalConstructInputsTwoPass unmarshals construct inputs by way of a resource.PropertyMap. This is how construct
+// used to unmarshal its inputs, and unmarshalConstructInput must agree with it.
+func unmarshalConstructInputsTwoPass(inputs *structpb.Struct, keepUnknowns bool) (map[string]*constructInput, error) {
+	props, err := plugin.UnmarshalProperties(inputs,
+		plugin.MarshalOptions{KeepSecrets: true, KeepResources: true, KeepUnknowns: keepUnknowns})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*constructInput, len(props))
+	for k, v := range props {
+		val, secret, err := unmarshalPropertyValue(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		result[string(k)] = &constructInput{value: val, known: !v.ContainsUnknowns(), secret: secret}
+	}
+	return result, nil
+}
+
+func unmarshalConstructInputs(inputs *structpb.Struct, keepUnknowns bool) (map[string]*constructInput, error) {
+	result := make(map[string]*constructInput, len(inputs.GetFields()))
+	for k, v := range inputs.GetFields() {
+		if !keepUnknowns && isUnknownRPCValue(v) {
+			continue
+		}
+		val, known, secret, err := unmarshalConstructInput(nil, v, keepUnknowns)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = &constructInput{value: val, known: known, secret: secret}
+	}
+	return result, nil
+}
+
+func TestUnmarshalConstructInput(t *testing.T) {
+	text, err := resource.NewTextAsset("hello")
+	assert.NoError(t, err)
+	props := resource.PropertyMap{
+		"null":    resource.NewNullProperty(),
+		"bool":    resource.NewBoolProperty(true),
+		"number":  resource.NewNumberProperty(42),
+		"string":  resource.NewStringProperty("foo"),
+		"unknown": resource.MakeComputed(resource.NewStringProperty("")),
+		"secret":  resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"asset":   resource.NewAssetProperty(text),
+		"array": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty("a"),
+			resource.MakeComputed(resource.NewStringProperty("")),
+			resource.MakeSecret(resource.NewNumberProperty(1)),
+		}),
+		"object": resource.NewObjectProperty(resource.PropertyMap{
+			"known":   resource.NewStringProperty("bar"),
+			"unknown": resource.MakeComputed(resource.NewStringProperty("")),
+			"nested": resource.NewObjectProperty(resource.PropertyMap{
+				"secret": resource.MakeSecret(resource.NewBoolProperty(false)),
+			}),
+		}),
+		"secretUnknown": resource.MakeSecret(resource.MakeComputed(resource.NewStringProperty(""))),
+	}
+	inputs, err := plugin.MarshalProperties(props,
+		plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true, KeepResources: true})
+	assert.NoError(t, err)
+
+	for _, keepUnknowns := range []bool{true, false} {
+		if !keepUnknowns {
+			// Without unknowns, a secret's unknown value is dropped, which leaves a malformed secret.
+			delete(inputs.Fields, "secretUnknown")
+		}
+
+		expected, err := unmarshalConstructInputsTwoPass(inputs, keepUnknowns)
+		assert.NoError(t, err)
+		actual, err := unmarshalConstructInputs(inputs, keepUnknowns)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// Malformed values produce errors.
+	_, _, _, err = unmarshalConstructInput(nil, &structpb.Value{Kind: &structpb.Value_StructValue{
+		StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			resource.SigKey: {Kind: &structpb.Value_StringValue{StringValue: "bogus"}},
+		}},
+	}}, true)
+	assert.EqualError(t, err, "unrecognized signature 'bogus' in property map")
+}
+
+func BenchmarkUnmarshalConstructInputs(b *testing.B) {
+	props := resource.PropertyMap{}
+	for i := 0; i < 1000; i++ {
+		props[resource.PropertyKey(fmt.Sprintf("string%d", i))] = resource.NewStringProperty("value")
+		props[resource.PropertyKey(fmt.Sprintf("secret%d", i))] = resource.MakeSecret(resource.NewNumberProperty(1))
+		props[resource.PropertyKey(fmt.Sprintf("object%d", i))] = resource.NewObjectProperty(resource.PropertyMap{
+			"name": resource.NewStringProperty("name"),
+			"tags": resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewStringProperty("a"),
+				resource.MakeComputed(resource.NewStringProperty("")),
+			}),
+		})
+	}
+	inputs, err := plugin.MarshalProperties(props,
+		plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true, KeepResources: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("TwoPass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := unmarshalConstructInputsTwoPass(inputs, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("SinglePass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := unmarshalConstructInputs(inputs, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestConstructProviders(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	providerRef := func(pkg string) string {
+		return fmt.Sprintf("urn:pulumi:stack::project::pulumi:providers:%[1]s::%[1]s::%[1]s-id", pkg)
+	}
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	req.Providers = map[string]string{
+		"pkg":  providerRef("pkg"),
+		"pkgA": providerRef("pkgA"),
+		"pkgB": providerRef("pkgB"),
+	}
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// The first child inherits its provider from the component; the second has no provider.
+			var a, c testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "a", nil, &a, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgC:m:typC", "c", nil, &c, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Foo = String("bar").ToStringOutput()
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	// Only the providers used by the children are reported, not those that are merely available to them or those
+	// used by the component itself.
+	assert.Equal(t, []string{providerRef("pkgA")}, resp.GetProviders())
+}
+
+type testClusterComponent struct {
+	ResourceState
+
+	Workers []Resource              `pulumi:"workers"`
+	Names   map[string]StringOutput `pulumi:"names"`
+	Missing []Resource              `pulumi:"missing"`
+}
+
+type testDependentComponent struct {
+	ResourceState
+
+	Endpoint StringOutput `pulumi:"endpoint"`
+	Size     Int          `pulumi:"size"`
+
+	endpointDeps []Resource
+}
+
+func (c *testDependentComponent) OutputDependencies() map[string][]Resource {
+	return map[string][]Resource{
+		"endpoint": c.endpointDeps,
+		"size":     nil,
+	}
+}
+
+func TestConstructOutputDependencies(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testDependentComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			var a, b testResource2
+			if err := ctx.RegisterResource("pkgA:m:typA", "a", nil, &a, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+			if err := ctx.RegisterResource("pkgA:m:typA", "b", nil, &b, Parent(&component)); err != nil {
+				return nil, nil, nil, err
+			}
+
+			// The endpoint is derived from a, but is synthesized by the component such that it also depends on b.
+			component.Endpoint = a.URN().ApplyT(func(urn URN) string { return "https://" + string(urn) }).(StringOutput)
+			component.Size = Int(3)
+			component.endpointDeps = []Resource{&a, &b}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	childURN := func(name string) string {
+		return string(resource.NewURN("stack", "project", "pkg:index:Component", "pkgA:m:typA", tokens.QName(name)))
+	}
+
+	// The declared dependencies are merged with the output's own dependencies, and the output's value is unchanged.
+	if deps, ok := resp.GetStateDependencies()["endpoint"]; assert.True(t, ok) {
+		assert.Equal(t, []string{childURN("a"), childURN("b")}, deps.GetUrns())
+	}
+	assert.NotContains(t, resp.GetStateDependencies(), "size")
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"endpoint": resource.NewStringProperty("https://" + childURN("a")),
+		"size":     resource.NewNumberProperty(3),
+	}, state)
+}
+
+type testComponentBase struct {
+	Endpoint StringOutput `pulumi:"endpoint"`
+	Name     StringOutput `pulumi:"name"`
+}
+
+type testComponentLabels struct {
+	Labels StringMapOutput `pulumi:"labels"`
+}
+
+type testEmbeddingComponent struct {
+	ResourceState
+	testComponentBase
+	*testComponentLabels
+
+	// Name shadows the base's field of the same name.
+	Name StringOutput `pulumi:"name"`
+}
+
+func TestConstructResultEmbeddedFields(t *testing.T) {
+	component := &testEmbeddingComponent{
+		testComponentBase: testComponentBase{
+			Endpoint: String("https://example.com").ToStringOutput(),
+			Name:     String("base").ToStringOutput(),
+		},
+		Name: String("component").ToStringOutput(),
+	}
+
+	// Fields of exported, embedded structs are collected, but those of unexported ones cannot be read.
+	state, err := constructResultState(component)
+	assert.NoError(t, err)
+	assert.Equal(t, Map{"name": component.Name}, state)
+
+	type ExportedBase = testComponentBase
+	type ExportedLabels = testComponentLabels
+	exported := &struct {
+		ResourceState
+		ExportedBase
+		*ExportedLabels
+
+		Name StringOutput `pulumi:"name"`
+	}{
+		ExportedBase: component.testComponentBase,
+		Name:         component.Name,
+	}
+	state, err = constructResultState(exported)
+	assert.NoError(t, err)
+	assert.Equal(t, Map{"endpoint": exported.Endpoint, "name": exported.Name}, state)
+
+	exported.ExportedLabels = &ExportedLabels{Labels: StringMap{"a": String("b")}.ToStringMapOutput()}
+	state, err = constructResultState(exported)
+	assert.NoError(t, err)
+	assert.Equal(t, Map{"endpoint": exported.Endpoint, "name": exported.Name, "labels": exported.Labels}, state)
+}
+
+func TestConstructResultDuplicateTags(t *testing.T) {
+	type ExportedBase = testComponentBase
+
+	// Fields at the same depth that share a tag are ambiguous.
+	type testCopiedField struct {
+		ResourceState
+
+		Endpoint    StringOutput `pulumi:"endpoint"`
+		EndpointURL StringOutput `pulumi:"endpoint"`
+	}
+	_, err := constructResultState(&testCopiedField{})
+	assert.EqualError(t, err,
+		"fields Endpoint and EndpointURL of pulumi.testCopiedField have the same pulumi tag \"endpoint\"")
+
+	type Other struct {
+		Address StringOutput `pulumi:"endpoint"`
+	}
+	type testTwoBases struct {
+		ResourceState
+		ExportedBase
+		Other
+	}
+	_, err = constructResultState(&testTwoBases{})
+	assert.EqualError(t, err, "fields ExportedBase.Endpoint and Other.Address of pulumi.testTwoBases have the same "+
+		"pulumi tag \"endpoint\"")
+
+	// A shallower field resolves the ambiguity.
+	type testShadowedBases struct {
+		ResourceState
+		ExportedBase
+		Other
+
+		Endpoint StringOutput `pulumi:"endpoint"`
+	}
+	state, err := constructResultState(&testShadowedBases{})
+	assert.NoError(t, err)
+	assert.Contains(t, state, "endpoint")
+}
+
+type testMisspelledDependentComponent struct {
+	ResourceState
+
+	Endpoint StringOutput `pulumi:"endpoint"`
+}
+
+func (c *testMisspelledDependentComponent) OutputDependencies() map[string][]Resource {
+	return map[string][]Resource{"endpiont": {c}}
+}
+
+func TestConstructOutputDependenciesUnknownOutput(t *testing.T) {
+	_, err := constructResultState(&testMisspelledDependentComponent{})
+	assert.EqualError(t, err, "cannot add dependencies to output endpiont: the resource has no such output")
+}
+
+func TestConstructResourceCollectionOutputs(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Cluster", "cluster")
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testClusterComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+
+			component.Names = map[string]StringOutput{}
+			for _, worker := range []string{"worker-0", "worker-1"} {
+				var res testResource2
+				if err := ctx.RegisterResource("pkgA:m:typA", worker, nil, &res, Parent(&component)); err != nil {
+					return nil, nil, nil, err
+				}
+				component.Workers = append(component.Workers, &res)
+				component.Names[worker] = res.URN().ApplyT(func(urn URN) string { return string(urn) }).(StringOutput)
+			}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	workerURN := func(name string) string {
+		return string(resource.NewURN("stack", "project", "pkg:index:Cluster", "pkgA:m:typA", tokens.QName(name)))
+	}
+
+	// The collections are returned as arrays and maps, and nil collections are omitted.
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{KeepResources: true})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"workers": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeCustomResourceReference(resource.URN(workerURN("worker-0")), "worker-0", ""),
+			resource.MakeCustomResourceReference(resource.URN(workerURN("worker-1")), "worker-1", ""),
+		}),
+		"names": resource.NewObjectProperty(resource.PropertyMap{
+			"worker-0": resource.NewStringProperty(workerURN("worker-0")),
+			"worker-1": resource.NewStringProperty(workerURN("worker-1")),
+		}),
+	}, state)
+
+	// Each collection depends on each of its elements.
+	for _, k := range []string{"workers", "names"} {
+		if deps, ok := resp.GetStateDependencies()[k]; assert.True(t, ok) {
+			assert.ElementsMatch(t, []string{workerURN("worker-0"), workerURN("worker-1")}, deps.GetUrns())
+		}
+	}
+}
+
+func TestConstructUnsupportedOptions(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+	engine, engineConn := startTestEngine(t)
+
+	// Simulate a newer engine by appending fields that this version of the SDK doesn't know about.
+	newRequest := func(name string) *pulumirpc.ConstructRequest {
+		b, err := proto.Marshal(newConstructRequest(addr, "pkg:index:Component", name))
+		assert.NoError(t, err)
+		buf := proto.NewBuffer(b)
+		assert.NoError(t, buf.EncodeVarint(1001<<3)) // a varint field
+		assert.NoError(t, buf.EncodeVarint(1))
+		assert.NoError(t, buf.EncodeVarint(1002<<3|2)) // a length-delimited field
+		assert.NoError(t, buf.EncodeStringBytes("structured-alias"))
+		assert.NoError(t, buf.EncodeVarint(1002<<3|2))
+		assert.NoError(t, buf.EncodeStringBytes("another-structured-alias"))
+
+		var req pulumirpc.ConstructRequest
+		assert.NoError(t, proto.Unmarshal(buf.Bytes(), &req))
+		return &req
+	}
+
+	// Each unsupported option is warned about once per request, and construction still succeeds.
+	names := []string{"component", "component2"}
+	for _, name := range names {
+		_, err := constructWithOptions(context.Background(), newRequest(name), engineConn,
+			constructOptions{}, registerTestComponent)
+		assert.NoError(t, err)
+	}
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if assert.Len(t, engine.logs, 4) {
+		for i, name := range names {
+			for j, field := range []int{1001, 1002} {
+				log := engine.logs[2*i+j]
+				assert.Equal(t, pulumirpc.LogSeverity_WARNING, log.GetSeverity())
+				assert.Equal(t, fmt.Sprintf("the engine sent %v (pkg:index:Component) an option (field %v) that "+
+					"is not supported by this version of the Pulumi SDK and will be ignored; upgrade the "+
+					"component's provider to honor it", name, field), log.GetMessage())
+			}
+		}
+	}
+}
+
+type testEndpoint struct {
+	host string
+	port int
+}
+
+type testUnmarshalable struct{ value string }
+
+type testPlainEndpoint struct {
+	Host string `pulumi:"host"`
+	Port int    `pulumi:"port"`
+}
+
+type testPlainComponent struct {
+	ResourceState
+
+	Name      string             `pulumi:"name"`
+	Count     int                `pulumi:"count"`
+	Enabled   bool               `pulumi:"enabled"`
+	Tags      map[string]string  `pulumi:"tags"`
+	Endpoint  testPlainEndpoint  `pulumi:"endpoint"`
+	Endpoints *testPlainEndpoint `pulumi:"endpoints"`
+	Updates   chan string        `pulumi:"updates"`
+}
+
+type testEndpointComponent struct {
+	ResourceState
+
+	Endpoint  testEndpoint       `pulumi:"endpoint"`
+	Endpoints []testEndpoint     `pulumi:"endpoints"`
+	Missing   *testUnmarshalable `pulumi:"missing"`
+}
+
+func init() {
+	RegisterMarshaler(testEndpoint{}, func(v interface{}) (interface{}, error) {
+		e := v.(testEndpoint)
+		return Map{"host": String(e.host), "port": Int(e.port)}, nil
+	})
+}
+
+func TestConstructCustomMarshaler(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	req := newConstructRequest(addr, "pkg:index:Component", "component")
+	resp, err := constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testEndpointComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Endpoint = testEndpoint{host: "localhost", port: 80}
+			component.Endpoints = []testEndpoint{{host: "a", port: 1}}
+			return registerConstructResult(ctx, &component)
+		})
+	assert.NoError(t, err)
+
+	// The registered marshaler is used for fields of the type and for values of the type nested in other values.
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"endpoint": resource.NewObjectProperty(resource.PropertyMap{
+			"host": resource.NewStringProperty("localhost"),
+			"port": resource.NewNumberProperty(80),
+		}),
+		"endpoints": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"host": resource.NewStringProperty("a"),
+				"port": resource.NewNumberProperty(1),
+			}),
+		}),
+	}, state)
+
+	// A non-nil field of a type without a marshaler is an error that names the field and its type.
+	req = newConstructRequest(addr, "pkg:index:Component", "unmarshalable")
+	_, err = constructWithOptions(context.Background(), req, nil,
+		constructOptions{}, func(ctx *Context, typ, name string,
+			inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+			var component testEndpointComponent
+			if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+				return nil, nil, nil, err
+			}
+			component.Missing = &testUnmarshalable{value: "x"}
+			return newConstructResult(&component)
+		})
+	assert.EqualError(t, err, "cannot marshal field Missing of type *pulumi.testUnmarshalable: register a "+
+		"marshaler for the type with RegisterMarshaler")
+
+	assert.Panics(t, func() {
+		RegisterMarshaler(testEndpoint{}, func(v interface{}) (interface{}, error) { return nil, nil })
+	})
+}
+
+func TestConstructPlainFields(t *testing.T) {
+	_, addr := startConstructMonitor(t, &testMonitor{})
+
+	construct := func(modify func(component *testPlainComponent)) (*pulumirpc.ConstructResponse, error) {
+		req := newConstructRequest(addr, "pkg:index:Component", "component")
+		return constructWithOptions(context.Background(), req, nil,
+			constructOptions{}, func(ctx *Context, typ, name string,
+				inputs map[string]interface{}, options ResourceOption) (URNInput, IDInput, Input, error) {
+
+				var component testPlainComponent
+				if err := ctx.RegisterComponentResource(typ, name, &component, options); err != nil {
+					return nil, nil, nil, err
+				}
+				modify(&component)
+				return registerConstructResult(ctx, &component)
+			})
+	}
+
+	// Tagged fields of primitive types and of plain structs, maps, and pointers thereof are marshaled as-is.
+	resp, err := construct(func(component *testPlainComponent) {
+		component.Name = "name"
+		component.Count = 42
+		component.Enabled = true
+		component.Tags = map[string]string{"a": "b"}
+		component.Endpoint = testPlainEndpoint{Host: "localhost", Port: 80}
+	})
+	assert.NoError(t, err)
+	state, err := plugin.UnmarshalProperties(resp.GetState(), plugin.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"name":    resource.NewStringProperty("name"),
+		"count":   resource.NewNumberProperty(42),
+		"enabled": resource.NewBoolProperty(true),
+		"tags":    resource.NewObjectProperty(resource.PropertyMap{"a": resource.NewStringProperty("b")}),
+		"endpoint": resource.NewObjectProperty(resource.PropertyMap{
+			"host": resource.NewStringProperty("localhost"),
+			"port": resource.NewNumberProperty(80),
+		}),
+	}, state)
+
+	// A non-nil field of a type that cannot be marshaled is still an error.
+	_, err = construct(func(component *testPlainComponent) {
+		component.Updates = make(chan string)
+	})
+	assert.EqualError(t, err, "cannot marshal field Updates of type chan string: register a marshaler for the type "+
+		"with RegisterMarshaler")
+}
+
+// dedupPropertyDependenciesSorted removes duplicate URNs by sorting them. This is how construct used to deduplicate
+// property dependencies, and dedupPropertyDependencies must agree with it.
+func dedupPropertyDependenciesSorted(deps []URN) []string {
+	sorted := append([]URN(nil), deps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	urns := make([]string, 0, len(sorted))
+	for _, d := range sorted {
+		if len(urns) > 0 && urns[len(urns)-1] == string(d) {
+			continue
+		}
+		urns = append(urns, string(d))
+	}
+	return urns
+}
+
+func TestDedupPropertyDependencies(t *testing.T) {
+	seen := make(map[URN]struct{})
+	for _, deps := range [][]URN{
+		nil,
+		{"a"},
+		{"c", "a", "b"},
+		{"b", "a", "b", "a", "a", "a"},
+	} {
+		assert.Equal(t, dedupPropertyDependenciesSorted(deps), dedupPropertyDependencies(deps, seen))
+		assert.Empty(t, seen)
+	}
+}
+
+func BenchmarkDedupPropertyDependencies(b *testing.B) {
+	// A 500x500 dependency matrix: each of 500 properties depends on 500 resources, half of which are duplicates.
+	propertyDeps := make(map[string][]URN, 500)
+	for i := 0; i < 500; i++ {
+		deps := make([]URN, 500)
+		for j := range deps {
+			deps[j] = URN(fmt.Sprintf("urn:pulumi:stack::project::pkgA:m:typA::res%d", (i*7+j*13)%250))
+		}
+		propertyDeps[fmt.Sprintf("prop%d", i)] = deps
+	}
+
+	b.Run("SortThenDedup", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, deps := range propertyDeps {
+				dedupPropertyDependenciesSorted(deps)
+			}
+		}
+	})
+	b.Run("Set", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			seen := make(map[URN]struct{})
+			for _, deps := range propertyDeps {
+				dedupPropertyDependencies(deps, seen)
+			}
+		}
+	})
+}
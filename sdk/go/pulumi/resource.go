@@ -15,6 +15,7 @@
 package pulumi
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -224,6 +225,8 @@ type invokeOptions struct {
 	Provider ProviderResource
 	// Version is an optional version of the provider plugin to use for the invoke.
 	Version string
+	// Context is an optional context that bounds the lifetime of the invoke.
+	Context context.Context
 }
 
 type ResourceOption interface {
@@ -245,6 +248,12 @@ func (o resourceOption) applyResourceOption(opts *resourceOptions) {
 	o(opts)
 }
 
+type invokeOption func(*invokeOptions)
+
+func (o invokeOption) applyInvokeOption(opts *invokeOptions) {
+	o(opts)
+}
+
 type resourceOrInvokeOption func(ro *resourceOptions, io *invokeOptions)
 
 func (o resourceOrInvokeOption) applyResourceOption(opts *resourceOptions) {
@@ -268,6 +277,21 @@ func merge(opts ...ResourceOption) *resourceOptions {
 	return options
 }
 
+// MergeOptions returns a single option that applies base followed by each of the extra options, in order. This allows
+// a component to layer its own options for a child on top of the options it inherited, e.g. those passed to Construct.
+// Options that hold a single value (e.g. Parent, Protect, or Version) take the value of the last option that sets
+// them, list options (e.g. DependsOn or Aliases) are appended to one another, and provider maps are merged, with later
+// providers replacing earlier ones for the same package. Nil options are ignored.
+func MergeOptions(base ResourceOption, extra ...ResourceOption) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		for _, o := range append([]ResourceOption{base}, extra...) {
+			if o != nil {
+				o.applyResourceOption(ro)
+			}
+		}
+	})
+}
+
 // AdditionalSecretOutputs specifies a list of output properties to mark as secret.
 func AdditionalSecretOutputs(o []string) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
@@ -389,6 +413,15 @@ func URN_(o string) ResourceOption {
 	})
 }
 
+// InvokeContext is an optional context for an invoke. If the context is canceled or its deadline expires before the
+// invoke completes, the invoke is abandoned and returns an error. This makes it possible to bound the time spent in
+// invokes that query slow external systems.
+func InvokeContext(ctx context.Context) InvokeOption {
+	return invokeOption(func(io *invokeOptions) {
+		io.Context = ctx
+	})
+}
+
 // Version is an optional version, corresponding to the version of the provider plugin that should be used when
 // operating on this resource. This version overrides the version information inferred from the current package and
 // should rarely be used.
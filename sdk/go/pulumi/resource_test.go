@@ -272,6 +272,38 @@ func TestResourceOptionMergingTransformations(t *testing.T) {
 	assertTransformations(t, []ResourceTransformation{t1, t2, t2, t3}, opts.Transformations)
 }
 
+func TestMergeOptions(t *testing.T) {
+	p1 := &testRes{foo: "a"}
+	p2 := &testRes{foo: "b"}
+	d1 := &testRes{foo: "c"}
+	d2 := &testRes{foo: "d"}
+	prov1 := &testProv{foo: "e"}
+	prov1.pkg = "aws"
+	prov2 := &testProv{foo: "f"}
+	prov2.pkg = "aws"
+	prov3 := &testProv{foo: "g"}
+	prov3.pkg = "azure"
+
+	base := MergeOptions(Parent(p1), Protect(true), DependsOn([]Resource{d1}), Providers(prov1, prov3))
+
+	// Single values are taken from the last option that sets them, lists are appended, and provider maps are merged.
+	opts := merge(MergeOptions(base, Protect(false), nil, DependsOn([]Resource{d2}), Provider(prov2), Parent(p2)))
+	assert.Equal(t, p2, opts.Parent)
+	assert.Equal(t, false, opts.Protect)
+	assert.Equal(t, []Resource{d1, d2}, opts.DependsOn)
+	assert.Equal(t, map[string]ProviderResource{"aws": prov2, "azure": prov3}, opts.Providers)
+
+	// Options that are not overridden are inherited from the base.
+	opts = merge(MergeOptions(base, Version("1.2.3")))
+	assert.Equal(t, p1, opts.Parent)
+	assert.Equal(t, true, opts.Protect)
+	assert.Equal(t, "1.2.3", opts.Version)
+
+	// A nil base is ignored.
+	opts = merge(MergeOptions(nil, Protect(true)))
+	assert.Equal(t, true, opts.Protect)
+}
+
 func assertTransformations(t *testing.T, t1 []ResourceTransformation, t2 []ResourceTransformation) {
 	assert.Equal(t, len(t1), len(t2))
 	for i := range t1 {
@@ -85,7 +85,7 @@ func marshalInputs(props Input) (resource.PropertyMap, map[string][]URN, []URN,
 		for _, dep := range resourceDeps {
 			depURN, _, _, err := dep.URN().awaitURN(context.TODO())
 			if err != nil {
-				return err
+				return fmt.Errorf("awaiting dependencies of input property %s: %w", pname, err)
 			}
 			if !pdepset[depURN] {
 				deps = append(deps, depURN)
@@ -165,6 +165,34 @@ const rpcTokenUnknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
 
 const cannotAwaitFmt = "cannot marshal Output value of type %T; please use Apply to access the Output's value"
 
+// MarshalFunc converts a value of a type that the Pulumi runtime does not know how to marshal into a value that it
+// does, e.g. a Map of the value's fields.
+type MarshalFunc func(v interface{}) (interface{}, error)
+
+var customMarshalers sync.Map // map[reflect.Type]MarshalFunc
+
+// RegisterMarshaler registers a MarshalFunc for values of the same type as example with the Pulumi runtime. The
+// function is used whenever a value of that type is marshaled, including when a component's construct result has a
+// `pulumi`-tagged field of that type that is not an Input.
+func RegisterMarshaler(example interface{}, marshal MarshalFunc) {
+	contract.Require(example != nil, "example")
+	contract.Require(marshal != nil, "marshal")
+
+	typ := reflect.TypeOf(example)
+	if _, hasExisting := customMarshalers.LoadOrStore(typ, marshal); hasExisting {
+		panic(fmt.Errorf("a marshaler for %v is already registered", typ))
+	}
+}
+
+// lookupMarshaler returns the MarshalFunc registered for the given type, if any.
+func lookupMarshaler(typ reflect.Type) (MarshalFunc, bool) {
+	marshal, ok := customMarshalers.Load(typ)
+	if !ok {
+		return nil, false
+	}
+	return marshal.(MarshalFunc), true
+}
+
 // marshalInput marshals an input value, returning its raw serializable value along with any dependencies.
 func marshalInput(v interface{}, destType reflect.Type, await bool) (resource.PropertyValue, []Resource, error) {
 	val, deps, secret, err := marshalInputAndDetermineSecret(v, destType, await)
@@ -285,6 +313,16 @@ func marshalInputAndDetermineSecret(v interface{},
 			return resource.MakeComponentResourceReference(resource.URN(urn), ""), deps, secret, nil
 		}
 
+		// If a marshaler is registered for the value's type, marshal its result instead.
+		if marshal, ok := lookupMarshaler(reflect.TypeOf(v)); ok {
+			mv, err := marshal(v)
+			if err != nil {
+				return resource.PropertyValue{}, nil, false, fmt.Errorf("marshaling value of type %T: %w", v, err)
+			}
+			v, destType = mv, anyType
+			continue
+		}
+
 		contract.Assertf(valueType.AssignableTo(destType) || valueType.ConvertibleTo(destType),
 			"%v: cannot assign %v to %v", v, valueType, destType)
 
@@ -130,11 +130,32 @@ type RunInfo struct {
 	Config      map[string]string
 	Parallel    int
 	DryRun      bool
+	Refresh     bool // true if the program or component is being evaluated as part of a refresh.
 	MonitorAddr string
 	EngineAddr  string
 	Mocks       MockResourceMonitor
-	getPlugins  bool
-	engineConn  *grpc.ClientConn // Pre-existing engine connection. If set this is used over EngineAddr.
+	// AutonamingPrefix, if set, is the prefix that components should apply to the names of their children. If unset,
+	// the value of the AutonamingPrefixConfigKey config key is used instead.
+	AutonamingPrefix string
+	// ConfigMetadata holds the metadata for config values, keyed by config key. It is nil if the engine does not
+	// supply config metadata.
+	ConfigMetadata map[string]ConfigMetadata
+	getPlugins     bool
+	engineConn     *grpc.ClientConn // Pre-existing engine connection. If set this is used over EngineAddr.
+}
+
+// validate checks that the fields required to run a program or construct a component are set. Mocked runs do not
+// connect to a resource monitor, so they do not require a monitor address.
+func (info RunInfo) validate() error {
+	switch {
+	case info.Project == "":
+		return errors.New("missing project name")
+	case info.Stack == "":
+		return errors.New("missing stack name")
+	case info.MonitorAddr == "" && info.Mocks == nil:
+		return errors.New("missing resource monitor address")
+	}
+	return nil
 }
 
 // getEnvInfo reads various program information from the process environment.
@@ -4,11 +4,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 )
 
 type testMonitor struct {
@@ -263,6 +266,33 @@ func TestInvoke(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// blockingInvokeMonitor is a resource monitor whose invokes do not complete until their context is done.
+type blockingInvokeMonitor struct {
+	pulumirpc.ResourceMonitorClient
+}
+
+func (m *blockingInvokeMonitor) Invoke(ctx context.Context, in *pulumirpc.InvokeRequest,
+	opts ...grpc.CallOption) (*pulumirpc.InvokeResponse, error) {
+
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestInvokeContext(t *testing.T) {
+	err := RunErr(func(ctx *Context) error {
+		ctx.monitor = &blockingInvokeMonitor{ResourceMonitorClient: ctx.monitor}
+
+		invokeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var result invokeResult
+		err := ctx.Invoke("test:index:func", &invokeArgs{}, &result, InvokeContext(invokeCtx))
+		assert.Equal(t, context.DeadlineExceeded, err)
+		return nil
+	}, WithMocks("project", "stack", &testMonitor{}))
+	assert.NoError(t, err)
+}
+
 type testInstanceResource struct {
 	CustomResourceState
 }
@@ -596,3 +596,48 @@ func TestDeps(t *testing.T) {
 	assert.ElementsMatch(t, []Resource{stringDep1, stringDep2, boolDep1, boolDep2}, deps)
 	assert.NoError(t, err)
 }
+
+// TestApplyTNestedOutput tests the pattern used by the generated URN and ID accessors of resource output types: an
+// applier that returns an output produces an AnyOutput, which resolves to the value of the inner output when awaited.
+func TestApplyTNestedOutput(t *testing.T) {
+	outerDep, innerDep := &ResourceState{}, &ResourceState{}
+	outer := StringOutput{newOutputState(reflect.TypeOf(""), outerDep)}
+	inner := IDOutput{newOutputState(reflect.TypeOf(ID("")), innerDep)}
+	go func() {
+		outer.resolve("outer", true, false, nil)
+		inner.resolve(ID("inner"), true, false, nil)
+	}()
+
+	nested := outer.ApplyT(func(v string) IDOutput {
+		return inner
+	})
+	_, ok := nested.(AnyOutput)
+	assert.True(t, ok)
+
+	id := nested.ApplyT(func(v interface{}) ID {
+		return v.(ID)
+	}).(IDOutput)
+	v, known, _, deps, err := await(id)
+	assert.NoError(t, err)
+	assert.Equal(t, ID("inner"), v)
+	assert.True(t, known)
+	assert.ElementsMatch(t, []Resource{outerDep, innerDep}, deps)
+
+	// An unknown inner output produces an unknown result.
+	unknown := IDOutput{newOutputState(reflect.TypeOf(ID("")))}
+	go unknown.resolve(ID(""), false, false, nil)
+	id = outer.ApplyT(func(v string) IDOutput {
+		return unknown
+	}).ApplyT(func(v interface{}) ID {
+		return v.(ID)
+	}).(IDOutput)
+	_, known, _, _, err = await(id)
+	assert.NoError(t, err)
+	assert.False(t, known)
+
+	// Appliers that return an interface type still produce an AnyOutput.
+	_, ok = outer.ApplyT(func(v string) Output {
+		return inner
+	}).(AnyOutput)
+	assert.True(t, ok)
+}
@@ -26,6 +26,8 @@ goog.exportSymbol('proto.pulumirpc.ConfigureErrorMissingKeys.MissingKey', null,
 goog.exportSymbol('proto.pulumirpc.ConfigureRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.ConfigureResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.ConstructRequest', null, global);
+goog.exportSymbol('proto.pulumirpc.ConstructRequest.ConfigMetadata', null, global);
+goog.exportSymbol('proto.pulumirpc.ConstructRequest.CustomTimeouts', null, global);
 goog.exportSymbol('proto.pulumirpc.ConstructRequest.PropertyDependencies', null, global);
 goog.exportSymbol('proto.pulumirpc.ConstructResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.ConstructResponse.PropertyDependencies', null, global);
@@ -539,9 +541,51 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.pulumirpc.ConstructResponse = function(opt_data) {
+proto.pulumirpc.ConstructRequest.ConfigMetadata = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.pulumirpc.ConstructRequest.ConfigMetadata, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.ConstructRequest.ConfigMetadata.displayName = 'proto.pulumirpc.ConstructRequest.ConfigMetadata';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
+goog.inherits(proto.pulumirpc.ConstructRequest.CustomTimeouts, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.ConstructRequest.CustomTimeouts.displayName = 'proto.pulumirpc.ConstructRequest.CustomTimeouts';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.ConstructResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.ConstructResponse.repeatedFields_, null);
+};
 goog.inherits(proto.pulumirpc.ConstructResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
@@ -5259,7 +5303,7 @@ proto.pulumirpc.DeleteRequest.prototype.setTimeout = function(value) {
  * @private {!Array<number>}
  * @const
  */
-proto.pulumirpc.ConstructRequest.repeatedFields_ = [14,15];
+proto.pulumirpc.ConstructRequest.repeatedFields_ = [14,15,19];
 
 
 
@@ -5306,7 +5350,12 @@ proto.pulumirpc.ConstructRequest.toObject = function(includeInstance, msg) {
     protect: jspb.Message.getBooleanFieldWithDefault(msg, 12, false),
     providersMap: (f = msg.getProvidersMap()) ? f.toObject(includeInstance, undefined) : [],
     aliasesList: (f = jspb.Message.getRepeatedField(msg, 14)) == null ? undefined : f,
-    dependenciesList: (f = jspb.Message.getRepeatedField(msg, 15)) == null ? undefined : f
+    dependenciesList: (f = jspb.Message.getRepeatedField(msg, 15)) == null ? undefined : f,
+    refresh: jspb.Message.getBooleanFieldWithDefault(msg, 16, false),
+    configmetadataMap: (f = msg.getConfigmetadataMap()) ? f.toObject(includeInstance, proto.pulumirpc.ConstructRequest.ConfigMetadata.toObject) : [],
+    customtimeouts: (f = msg.getCustomtimeouts()) && proto.pulumirpc.ConstructRequest.CustomTimeouts.toObject(includeInstance, f),
+    additionalsecretoutputsList: (f = jspb.Message.getRepeatedField(msg, 19)) == null ? undefined : f,
+    deletebeforereplace: jspb.Message.getBooleanFieldWithDefault(msg, 20, false)
   };
 
   if (includeInstance) {
@@ -5410,6 +5459,29 @@ proto.pulumirpc.ConstructRequest.deserializeBinaryFromReader = function(msg, rea
       var value = /** @type {string} */ (reader.readString());
       msg.addDependencies(value);
       break;
+    case 16:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRefresh(value);
+      break;
+    case 17:
+      var value = msg.getConfigmetadataMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.ConstructRequest.ConfigMetadata.deserializeBinaryFromReader, "", new proto.pulumirpc.ConstructRequest.ConfigMetadata());
+         });
+      break;
+    case 18:
+      var value = new proto.pulumirpc.ConstructRequest.CustomTimeouts;
+      reader.readMessage(value,proto.pulumirpc.ConstructRequest.CustomTimeouts.deserializeBinaryFromReader);
+      msg.setCustomtimeouts(value);
+      break;
+    case 19:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAdditionalsecretoutputs(value);
+      break;
+    case 20:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDeletebeforereplace(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -5536,6 +5608,39 @@ proto.pulumirpc.ConstructRequest.serializeBinaryToWriter = function(message, wri
       f
     );
   }
+  f = message.getRefresh();
+  if (f) {
+    writer.writeBool(
+      16,
+      f
+    );
+  }
+  f = message.getConfigmetadataMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(17, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.ConstructRequest.ConfigMetadata.serializeBinaryToWriter);
+  }
+  f = message.getCustomtimeouts();
+  if (f != null) {
+    writer.writeMessage(
+      18,
+      f,
+      proto.pulumirpc.ConstructRequest.CustomTimeouts.serializeBinaryToWriter
+    );
+  }
+  f = message.getAdditionalsecretoutputsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      19,
+      f
+    );
+  }
+  f = message.getDeletebeforereplace();
+  if (f) {
+    writer.writeBool(
+      20,
+      f
+    );
+  }
 };
 
 
@@ -5695,157 +5800,505 @@ proto.pulumirpc.ConstructRequest.PropertyDependencies.prototype.clearUrnsList =
 };
 
 
-/**
- * optional string project = 1;
- * @return {string}
- */
-proto.pulumirpc.ConstructRequest.prototype.getProject = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.ConstructRequest.prototype.setProject = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ConstructRequest.ConfigMetadata.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional string stack = 2;
- * @return {string}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.ConstructRequest.ConfigMetadata} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ConstructRequest.prototype.getStack = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
-};
-
+proto.pulumirpc.ConstructRequest.ConfigMetadata.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    version: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    lastrotated: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
 
-/**
- * @param {string} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
- */
-proto.pulumirpc.ConstructRequest.prototype.setStack = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * map<string, string> config = 3;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.ConstructRequest.ConfigMetadata}
  */
-proto.pulumirpc.ConstructRequest.prototype.getConfigMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
-      null));
+proto.pulumirpc.ConstructRequest.ConfigMetadata.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.ConstructRequest.ConfigMetadata;
+  return proto.pulumirpc.ConstructRequest.ConfigMetadata.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.ConstructRequest.ConfigMetadata} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.ConstructRequest.ConfigMetadata}
  */
-proto.pulumirpc.ConstructRequest.prototype.clearConfigMap = function() {
-  this.getConfigMap().clear();
-  return this;};
+proto.pulumirpc.ConstructRequest.ConfigMetadata.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setVersion(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setLastrotated(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
 
 
 /**
- * optional bool dryRun = 4;
- * @return {boolean}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.ConstructRequest.prototype.getDryrun = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.ConstructRequest.ConfigMetadata.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.ConstructRequest.ConfigMetadata} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ConstructRequest.prototype.setDryrun = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 4, value);
+proto.pulumirpc.ConstructRequest.ConfigMetadata.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getVersion();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getLastrotated();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
 };
 
 
 /**
- * optional int32 parallel = 5;
- * @return {number}
+ * optional string version = 1;
+ * @return {string}
  */
-proto.pulumirpc.ConstructRequest.prototype.getParallel = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.getVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {number} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest.ConfigMetadata} returns this
  */
-proto.pulumirpc.ConstructRequest.prototype.setParallel = function(value) {
-  return jspb.Message.setProto3IntField(this, 5, value);
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.setVersion = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string monitorEndpoint = 6;
+ * optional string lastRotated = 2;
  * @return {string}
  */
-proto.pulumirpc.ConstructRequest.prototype.getMonitorendpoint = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.getLastrotated = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * @return {!proto.pulumirpc.ConstructRequest.ConfigMetadata} returns this
  */
-proto.pulumirpc.ConstructRequest.prototype.setMonitorendpoint = function(value) {
-  return jspb.Message.setProto3StringField(this, 6, value);
+proto.pulumirpc.ConstructRequest.ConfigMetadata.prototype.setLastrotated = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
-/**
- * optional string type = 7;
- * @return {string}
- */
-proto.pulumirpc.ConstructRequest.prototype.getType = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.ConstructRequest.prototype.setType = function(value) {
-  return jspb.Message.setProto3StringField(this, 7, value);
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ConstructRequest.CustomTimeouts.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional string name = 8;
- * @return {string}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.ConstructRequest.CustomTimeouts} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ConstructRequest.prototype.getName = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+proto.pulumirpc.ConstructRequest.CustomTimeouts.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    create: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    update: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    pb_delete: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ConstructRequest} returns this
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.ConstructRequest.CustomTimeouts}
  */
-proto.pulumirpc.ConstructRequest.prototype.setName = function(value) {
-  return jspb.Message.setProto3StringField(this, 8, value);
+proto.pulumirpc.ConstructRequest.CustomTimeouts.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.ConstructRequest.CustomTimeouts;
+  return proto.pulumirpc.ConstructRequest.CustomTimeouts.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * optional string parent = 9;
- * @return {string}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.ConstructRequest.CustomTimeouts} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.ConstructRequest.CustomTimeouts}
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCreate(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUpdate(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDelete(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.ConstructRequest.CustomTimeouts.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.ConstructRequest.CustomTimeouts} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCreate();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getUpdate();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getDelete();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string create = 1;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.getCreate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest.CustomTimeouts} returns this
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.setCreate = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string update = 2;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.getUpdate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest.CustomTimeouts} returns this
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.setUpdate = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string delete = 3;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.getDelete = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest.CustomTimeouts} returns this
+ */
+proto.pulumirpc.ConstructRequest.CustomTimeouts.prototype.setDelete = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string project = 1;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getProject = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setProject = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string stack = 2;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getStack = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setStack = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * map<string, string> config = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getConfigMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.clearConfigMap = function() {
+  this.getConfigMap().clear();
+  return this;};
+
+
+/**
+ * optional bool dryRun = 4;
+ * @return {boolean}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getDryrun = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setDryrun = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+/**
+ * optional int32 parallel = 5;
+ * @return {number}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getParallel = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setParallel = function(value) {
+  return jspb.Message.setProto3IntField(this, 5, value);
+};
+
+
+/**
+ * optional string monitorEndpoint = 6;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getMonitorendpoint = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setMonitorendpoint = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional string type = 7;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setType = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional string name = 8;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+/**
+ * optional string parent = 9;
+ * @return {string}
  */
 proto.pulumirpc.ConstructRequest.prototype.getParent = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
@@ -6034,6 +6487,147 @@ proto.pulumirpc.ConstructRequest.prototype.clearDependenciesList = function() {
 };
 
 
+/**
+ * optional bool refresh = 16;
+ * @return {boolean}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getRefresh = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 16, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setRefresh = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 16, value);
+};
+
+
+/**
+ * map<string, ConfigMetadata> configMetadata = 17;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!proto.pulumirpc.ConstructRequest.ConfigMetadata>}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getConfigmetadataMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!proto.pulumirpc.ConstructRequest.ConfigMetadata>} */ (
+      jspb.Message.getMapField(this, 17, opt_noLazyCreate,
+      proto.pulumirpc.ConstructRequest.ConfigMetadata));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.clearConfigmetadataMap = function() {
+  this.getConfigmetadataMap().clear();
+  return this;};
+
+
+/**
+ * optional CustomTimeouts customTimeouts = 18;
+ * @return {?proto.pulumirpc.ConstructRequest.CustomTimeouts}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getCustomtimeouts = function() {
+  return /** @type{?proto.pulumirpc.ConstructRequest.CustomTimeouts} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.ConstructRequest.CustomTimeouts, 18));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.ConstructRequest.CustomTimeouts|undefined} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+*/
+proto.pulumirpc.ConstructRequest.prototype.setCustomtimeouts = function(value) {
+  return jspb.Message.setWrapperField(this, 18, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.clearCustomtimeouts = function() {
+  return this.setCustomtimeouts(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ConstructRequest.prototype.hasCustomtimeouts = function() {
+  return jspb.Message.getField(this, 18) != null;
+};
+
+
+/**
+ * repeated string additionalSecretOutputs = 19;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getAdditionalsecretoutputsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 19));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setAdditionalsecretoutputsList = function(value) {
+  return jspb.Message.setField(this, 19, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.addAdditionalsecretoutputs = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 19, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.clearAdditionalsecretoutputsList = function() {
+  return this.setAdditionalsecretoutputsList([]);
+};
+
+
+/**
+ * optional bool deleteBeforeReplace = 20;
+ * @return {boolean}
+ */
+proto.pulumirpc.ConstructRequest.prototype.getDeletebeforereplace = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 20, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.ConstructRequest} returns this
+ */
+proto.pulumirpc.ConstructRequest.prototype.setDeletebeforereplace = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 20, value);
+};
+
+
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.ConstructResponse.repeatedFields_ = [4];
 
 
 
@@ -6068,7 +6662,9 @@ proto.pulumirpc.ConstructResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
     urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
     state: (f = msg.getState()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    statedependenciesMap: (f = msg.getStatedependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.ConstructResponse.PropertyDependencies.toObject) : []
+    statedependenciesMap: (f = msg.getStatedependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.ConstructResponse.PropertyDependencies.toObject) : [],
+    providersList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f,
+    id: jspb.Message.getFieldWithDefault(msg, 5, "")
   };
 
   if (includeInstance) {
@@ -6120,6 +6716,14 @@ proto.pulumirpc.ConstructResponse.deserializeBinaryFromReader = function(msg, re
         jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.ConstructResponse.PropertyDependencies.deserializeBinaryFromReader, "", new proto.pulumirpc.ConstructResponse.PropertyDependencies());
          });
       break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addProviders(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -6168,6 +6772,20 @@ proto.pulumirpc.ConstructResponse.serializeBinaryToWriter = function(message, wr
   if (f && f.getLength() > 0) {
     f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.ConstructResponse.PropertyDependencies.serializeBinaryToWriter);
   }
+  f = message.getProvidersList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      4,
+      f
+    );
+  }
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -6404,6 +7022,61 @@ proto.pulumirpc.ConstructResponse.prototype.clearStatedependenciesMap = function
   return this;};
 
 
+/**
+ * repeated string providers = 4;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.ConstructResponse.prototype.getProvidersList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.ConstructResponse} returns this
+ */
+proto.pulumirpc.ConstructResponse.prototype.setProvidersList = function(value) {
+  return jspb.Message.setField(this, 4, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.ConstructResponse} returns this
+ */
+proto.pulumirpc.ConstructResponse.prototype.addProviders = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.ConstructResponse} returns this
+ */
+proto.pulumirpc.ConstructResponse.prototype.clearProvidersList = function() {
+  return this.setProvidersList([]);
+};
+
+
+/**
+ * optional string id = 5;
+ * @return {string}
+ */
+proto.pulumirpc.ConstructResponse.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ConstructResponse} returns this
+ */
+proto.pulumirpc.ConstructResponse.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
 
 /**
  * List of repeated fields within this message type.
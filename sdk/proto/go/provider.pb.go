@@ -1294,24 +1294,29 @@ func (m *DeleteRequest) GetTimeout() float64 {
 }
 
 type ConstructRequest struct {
-	Project              string                                            `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
-	Stack                string                                            `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
-	Config               map[string]string                                 `protobuf:"bytes,3,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	DryRun               bool                                              `protobuf:"varint,4,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
-	Parallel             int32                                             `protobuf:"varint,5,opt,name=parallel,proto3" json:"parallel,omitempty"`
-	MonitorEndpoint      string                                            `protobuf:"bytes,6,opt,name=monitorEndpoint,proto3" json:"monitorEndpoint,omitempty"`
-	Type                 string                                            `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
-	Name                 string                                            `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
-	Parent               string                                            `protobuf:"bytes,9,opt,name=parent,proto3" json:"parent,omitempty"`
-	Inputs               *_struct.Struct                                   `protobuf:"bytes,10,opt,name=inputs,proto3" json:"inputs,omitempty"`
-	InputDependencies    map[string]*ConstructRequest_PropertyDependencies `protobuf:"bytes,11,rep,name=inputDependencies,proto3" json:"inputDependencies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Protect              bool                                              `protobuf:"varint,12,opt,name=protect,proto3" json:"protect,omitempty"`
-	Providers            map[string]string                                 `protobuf:"bytes,13,rep,name=providers,proto3" json:"providers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Aliases              []string                                          `protobuf:"bytes,14,rep,name=aliases,proto3" json:"aliases,omitempty"`
-	Dependencies         []string                                          `protobuf:"bytes,15,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                                          `json:"-"`
-	XXX_unrecognized     []byte                                            `json:"-"`
-	XXX_sizecache        int32                                             `json:"-"`
+	Project                 string                                            `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Stack                   string                                            `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
+	Config                  map[string]string                                 `protobuf:"bytes,3,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DryRun                  bool                                              `protobuf:"varint,4,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Parallel                int32                                             `protobuf:"varint,5,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	MonitorEndpoint         string                                            `protobuf:"bytes,6,opt,name=monitorEndpoint,proto3" json:"monitorEndpoint,omitempty"`
+	Type                    string                                            `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	Name                    string                                            `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
+	Parent                  string                                            `protobuf:"bytes,9,opt,name=parent,proto3" json:"parent,omitempty"`
+	Inputs                  *_struct.Struct                                   `protobuf:"bytes,10,opt,name=inputs,proto3" json:"inputs,omitempty"`
+	InputDependencies       map[string]*ConstructRequest_PropertyDependencies `protobuf:"bytes,11,rep,name=inputDependencies,proto3" json:"inputDependencies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Protect                 bool                                              `protobuf:"varint,12,opt,name=protect,proto3" json:"protect,omitempty"`
+	Providers               map[string]string                                 `protobuf:"bytes,13,rep,name=providers,proto3" json:"providers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Aliases                 []string                                          `protobuf:"bytes,14,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Dependencies            []string                                          `protobuf:"bytes,15,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	Refresh                 bool                                              `protobuf:"varint,16,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	ConfigMetadata          map[string]*ConstructRequest_ConfigMetadata       `protobuf:"bytes,17,rep,name=configMetadata,proto3" json:"configMetadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CustomTimeouts          *ConstructRequest_CustomTimeouts                  `protobuf:"bytes,18,opt,name=customTimeouts,proto3" json:"customTimeouts,omitempty"`
+	AdditionalSecretOutputs []string                                          `protobuf:"bytes,19,rep,name=additionalSecretOutputs,proto3" json:"additionalSecretOutputs,omitempty"`
+	DeleteBeforeReplace     bool                                              `protobuf:"varint,20,opt,name=deleteBeforeReplace,proto3" json:"deleteBeforeReplace,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}                                          `json:"-"`
+	XXX_unrecognized        []byte                                            `json:"-"`
+	XXX_sizecache           int32                                             `json:"-"`
 }
 
 func (m *ConstructRequest) Reset()         { *m = ConstructRequest{} }
@@ -1444,6 +1449,41 @@ func (m *ConstructRequest) GetDependencies() []string {
 	return nil
 }
 
+func (m *ConstructRequest) GetRefresh() bool {
+	if m != nil {
+		return m.Refresh
+	}
+	return false
+}
+
+func (m *ConstructRequest) GetConfigMetadata() map[string]*ConstructRequest_ConfigMetadata {
+	if m != nil {
+		return m.ConfigMetadata
+	}
+	return nil
+}
+
+func (m *ConstructRequest) GetCustomTimeouts() *ConstructRequest_CustomTimeouts {
+	if m != nil {
+		return m.CustomTimeouts
+	}
+	return nil
+}
+
+func (m *ConstructRequest) GetAdditionalSecretOutputs() []string {
+	if m != nil {
+		return m.AdditionalSecretOutputs
+	}
+	return nil
+}
+
+func (m *ConstructRequest) GetDeleteBeforeReplace() bool {
+	if m != nil {
+		return m.DeleteBeforeReplace
+	}
+	return false
+}
+
 // PropertyDependencies describes the resources that a particular property depends on.
 type ConstructRequest_PropertyDependencies struct {
 	Urns                 []string `protobuf:"bytes,1,rep,name=urns,proto3" json:"urns,omitempty"`
@@ -1484,10 +1524,116 @@ func (m *ConstructRequest_PropertyDependencies) GetUrns() []string {
 	return nil
 }
 
+// ConfigMetadata describes the history of a configuration value.
+type ConstructRequest_ConfigMetadata struct {
+	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	LastRotated          string   `protobuf:"bytes,2,opt,name=lastRotated,proto3" json:"lastRotated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConstructRequest_ConfigMetadata) Reset()         { *m = ConstructRequest_ConfigMetadata{} }
+func (m *ConstructRequest_ConfigMetadata) String() string { return proto.CompactTextString(m) }
+func (*ConstructRequest_ConfigMetadata) ProtoMessage()    {}
+func (*ConstructRequest_ConfigMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6a9f3c02af3d1c8, []int{20, 1}
+}
+
+func (m *ConstructRequest_ConfigMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConstructRequest_ConfigMetadata.Unmarshal(m, b)
+}
+func (m *ConstructRequest_ConfigMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConstructRequest_ConfigMetadata.Marshal(b, m, deterministic)
+}
+func (m *ConstructRequest_ConfigMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConstructRequest_ConfigMetadata.Merge(m, src)
+}
+func (m *ConstructRequest_ConfigMetadata) XXX_Size() int {
+	return xxx_messageInfo_ConstructRequest_ConfigMetadata.Size(m)
+}
+func (m *ConstructRequest_ConfigMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConstructRequest_ConfigMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConstructRequest_ConfigMetadata proto.InternalMessageInfo
+
+func (m *ConstructRequest_ConfigMetadata) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ConstructRequest_ConfigMetadata) GetLastRotated() string {
+	if m != nil {
+		return m.LastRotated
+	}
+	return ""
+}
+
+// CustomTimeouts specifies timeouts for resource provisioning operations, as Go duration strings (e.g. "5m").
+type ConstructRequest_CustomTimeouts struct {
+	Create               string   `protobuf:"bytes,1,opt,name=create,proto3" json:"create,omitempty"`
+	Update               string   `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
+	Delete               string   `protobuf:"bytes,3,opt,name=delete,proto3" json:"delete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConstructRequest_CustomTimeouts) Reset()         { *m = ConstructRequest_CustomTimeouts{} }
+func (m *ConstructRequest_CustomTimeouts) String() string { return proto.CompactTextString(m) }
+func (*ConstructRequest_CustomTimeouts) ProtoMessage()    {}
+func (*ConstructRequest_CustomTimeouts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6a9f3c02af3d1c8, []int{20, 2}
+}
+
+func (m *ConstructRequest_CustomTimeouts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConstructRequest_CustomTimeouts.Unmarshal(m, b)
+}
+func (m *ConstructRequest_CustomTimeouts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConstructRequest_CustomTimeouts.Marshal(b, m, deterministic)
+}
+func (m *ConstructRequest_CustomTimeouts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConstructRequest_CustomTimeouts.Merge(m, src)
+}
+func (m *ConstructRequest_CustomTimeouts) XXX_Size() int {
+	return xxx_messageInfo_ConstructRequest_CustomTimeouts.Size(m)
+}
+func (m *ConstructRequest_CustomTimeouts) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConstructRequest_CustomTimeouts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConstructRequest_CustomTimeouts proto.InternalMessageInfo
+
+func (m *ConstructRequest_CustomTimeouts) GetCreate() string {
+	if m != nil {
+		return m.Create
+	}
+	return ""
+}
+
+func (m *ConstructRequest_CustomTimeouts) GetUpdate() string {
+	if m != nil {
+		return m.Update
+	}
+	return ""
+}
+
+func (m *ConstructRequest_CustomTimeouts) GetDelete() string {
+	if m != nil {
+		return m.Delete
+	}
+	return ""
+}
+
 type ConstructResponse struct {
 	Urn                  string                                             `protobuf:"bytes,1,opt,name=urn,proto3" json:"urn,omitempty"`
 	State                *_struct.Struct                                    `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
 	StateDependencies    map[string]*ConstructResponse_PropertyDependencies `protobuf:"bytes,3,rep,name=stateDependencies,proto3" json:"stateDependencies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Providers            []string                                           `protobuf:"bytes,4,rep,name=providers,proto3" json:"providers,omitempty"`
+	Id                   string                                             `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                                           `json:"-"`
 	XXX_unrecognized     []byte                                             `json:"-"`
 	XXX_sizecache        int32                                              `json:"-"`
@@ -1539,6 +1685,20 @@ func (m *ConstructResponse) GetStateDependencies() map[string]*ConstructResponse
 	return nil
 }
 
+func (m *ConstructResponse) GetProviders() []string {
+	if m != nil {
+		return m.Providers
+	}
+	return nil
+}
+
+func (m *ConstructResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
 // PropertyDependencies describes the resources that a particular property depends on.
 type ConstructResponse_PropertyDependencies struct {
 	Urns                 []string `protobuf:"bytes,1,rep,name=urns,proto3" json:"urns,omitempty"`
@@ -1674,9 +1834,12 @@ func init() {
 	proto.RegisterType((*DeleteRequest)(nil), "pulumirpc.DeleteRequest")
 	proto.RegisterType((*ConstructRequest)(nil), "pulumirpc.ConstructRequest")
 	proto.RegisterMapType((map[string]string)(nil), "pulumirpc.ConstructRequest.ConfigEntry")
+	proto.RegisterMapType((map[string]*ConstructRequest_ConfigMetadata)(nil), "pulumirpc.ConstructRequest.ConfigMetadataEntry")
 	proto.RegisterMapType((map[string]*ConstructRequest_PropertyDependencies)(nil), "pulumirpc.ConstructRequest.InputDependenciesEntry")
 	proto.RegisterMapType((map[string]string)(nil), "pulumirpc.ConstructRequest.ProvidersEntry")
 	proto.RegisterType((*ConstructRequest_PropertyDependencies)(nil), "pulumirpc.ConstructRequest.PropertyDependencies")
+	proto.RegisterType((*ConstructRequest_ConfigMetadata)(nil), "pulumirpc.ConstructRequest.ConfigMetadata")
+	proto.RegisterType((*ConstructRequest_CustomTimeouts)(nil), "pulumirpc.ConstructRequest.CustomTimeouts")
 	proto.RegisterType((*ConstructResponse)(nil), "pulumirpc.ConstructResponse")
 	proto.RegisterMapType((map[string]*ConstructResponse_PropertyDependencies)(nil), "pulumirpc.ConstructResponse.StateDependenciesEntry")
 	proto.RegisterType((*ConstructResponse_PropertyDependencies)(nil), "pulumirpc.ConstructResponse.PropertyDependencies")
@@ -1686,112 +1849,123 @@ func init() {
 func init() { proto.RegisterFile("provider.proto", fileDescriptor_c6a9f3c02af3d1c8) }
 
 var fileDescriptor_c6a9f3c02af3d1c8 = []byte{
-	// 1670 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xcd, 0x72, 0x1b, 0x45,
-	0x10, 0xf6, 0x4a, 0xb2, 0x6c, 0xb5, 0x7e, 0x22, 0x0f, 0xc1, 0x51, 0x14, 0x1f, 0x5c, 0x0b, 0x55,
-	0x98, 0x84, 0xc8, 0xc6, 0x39, 0x40, 0x52, 0x4e, 0x05, 0xdb, 0x92, 0x8d, 0x2b, 0x89, 0x63, 0xd6,
-	0x09, 0x3f, 0xa7, 0x64, 0xb3, 0x1a, 0xc9, 0x8b, 0xa5, 0xdd, 0x65, 0x76, 0x56, 0x29, 0x73, 0xe6,
-	0xc0, 0x05, 0xae, 0x14, 0x0f, 0x01, 0x54, 0xf1, 0x04, 0x3c, 0x08, 0x1c, 0x79, 0x00, 0x8a, 0x17,
-	0xa0, 0xe6, 0x6f, 0x3d, 0x23, 0xad, 0x7f, 0x49, 0xc1, 0x6d, 0x7b, 0xba, 0xa7, 0xa7, 0xfb, 0x9b,
-	0x9e, 0xfe, 0x59, 0xa8, 0x45, 0x24, 0x1c, 0xf9, 0x5d, 0x4c, 0x5a, 0x11, 0x09, 0x69, 0x88, 0x4a,
-	0x51, 0x32, 0x48, 0x86, 0x3e, 0x89, 0xbc, 0x66, 0x25, 0x1a, 0x24, 0x7d, 0x3f, 0x10, 0x8c, 0xe6,
-	0x8d, 0x7e, 0x18, 0xf6, 0x07, 0x78, 0x99, 0x53, 0x2f, 0x93, 0xde, 0x32, 0x1e, 0x46, 0xf4, 0x48,
-	0x32, 0x17, 0xc6, 0x99, 0x31, 0x25, 0x89, 0x47, 0x05, 0xd7, 0x7e, 0x0f, 0xea, 0xdb, 0x98, 0xee,
-	0x7b, 0x07, 0x78, 0xe8, 0x3a, 0xf8, 0xab, 0x04, 0xc7, 0x14, 0x35, 0x60, 0x66, 0x84, 0x49, 0xec,
-	0x87, 0x41, 0xc3, 0x5a, 0xb4, 0x96, 0xa6, 0x1d, 0x45, 0xda, 0xb7, 0x60, 0x4e, 0x93, 0x8e, 0xa3,
-	0x30, 0x88, 0x31, 0x9a, 0x87, 0x62, 0xcc, 0x57, 0xb8, 0x74, 0xc9, 0x91, 0x94, 0xfd, 0x43, 0x0e,
-	0xea, 0x9b, 0x61, 0xd0, 0xf3, 0xfb, 0x09, 0xc1, 0x4a, 0xf7, 0xc7, 0x50, 0x1a, 0xb9, 0xc4, 0x77,
-	0x5f, 0x0e, 0x70, 0xdc, 0xb0, 0x16, 0xf3, 0x4b, 0xe5, 0xd5, 0x9b, 0xad, 0xd4, 0xaf, 0xd6, 0xb8,
-	0x7c, 0xeb, 0x53, 0x25, 0xdc, 0x09, 0x28, 0x39, 0x72, 0x8e, 0x37, 0xa3, 0x5b, 0x50, 0x70, 0x49,
-	0x3f, 0x6e, 0xe4, 0x16, 0xad, 0xa5, 0xf2, 0xea, 0xb5, 0x96, 0x70, 0xb3, 0xa5, 0xdc, 0x6c, 0xed,
-	0x73, 0x37, 0x1d, 0x2e, 0x84, 0xde, 0x86, 0xaa, 0xeb, 0x79, 0x38, 0xa2, 0xfb, 0xd8, 0x23, 0x98,
-	0xc6, 0x8d, 0xfc, 0xa2, 0xb5, 0x34, 0xeb, 0x98, 0x8b, 0x68, 0x09, 0xae, 0x88, 0x05, 0x07, 0xc7,
-	0x61, 0x42, 0x3c, 0x1c, 0x37, 0x0a, 0x5c, 0x6e, 0x7c, 0xb9, 0xb9, 0x06, 0x35, 0xd3, 0x32, 0x54,
-	0x87, 0xfc, 0x21, 0x3e, 0x92, 0x10, 0xb0, 0x4f, 0x74, 0x15, 0xa6, 0x47, 0xee, 0x20, 0xc1, 0xdc,
-	0xc2, 0x92, 0x23, 0x88, 0x7b, 0xb9, 0x0f, 0x2d, 0xfb, 0x3b, 0x0b, 0xe6, 0x34, 0x4f, 0x25, 0x8e,
-	0x13, 0x36, 0x5a, 0x27, 0xd8, 0x18, 0x27, 0x51, 0x14, 0x12, 0x1a, 0xef, 0x11, 0x3c, 0xf2, 0xf1,
-	0x2b, 0xae, 0x7f, 0xd6, 0x19, 0x5f, 0xce, 0xf2, 0x26, 0x9f, 0xe9, 0x8d, 0xfd, 0xab, 0x05, 0xd7,
-	0x53, 0x7b, 0x3a, 0x84, 0x84, 0xe4, 0xb1, 0x1f, 0xc7, 0x7e, 0xd0, 0x7f, 0x88, 0x8f, 0x62, 0xf4,
-	0x09, 0x94, 0x87, 0xc7, 0xa4, 0xbc, 0xb4, 0xe5, 0xac, 0x4b, 0x1b, 0xdf, 0xda, 0x3a, 0xfe, 0x76,
-	0x74, 0x1d, 0xcd, 0x0d, 0x80, 0x63, 0x16, 0x42, 0x50, 0x08, 0xdc, 0x21, 0x96, 0xd8, 0xf1, 0x6f,
-	0xb4, 0x08, 0xe5, 0x2e, 0x8e, 0x3d, 0xe2, 0x47, 0x94, 0xc5, 0xa1, 0x80, 0x50, 0x5f, 0xb2, 0x7f,
-	0xb6, 0xa0, 0xba, 0x13, 0x8c, 0xc2, 0xc3, 0x34, 0xb6, 0xea, 0x90, 0xa7, 0xe1, 0xa1, 0xba, 0x02,
-	0x1a, 0x1e, 0x5e, 0x2c, 0x46, 0x9a, 0x30, 0xab, 0x1e, 0x1c, 0x07, 0xaa, 0xe4, 0xa4, 0xb4, 0xfe,
-	0x24, 0x0a, 0x9c, 0xa5, 0xc8, 0x2c, 0x94, 0xa7, 0xb3, 0x51, 0x1e, 0x41, 0x4d, 0xd9, 0x2b, 0x6f,
-	0x7c, 0x19, 0x8a, 0x04, 0xd3, 0x84, 0x88, 0x77, 0x76, 0x8a, 0x81, 0x52, 0x0c, 0xdd, 0x81, 0xd9,
-	0x9e, 0xeb, 0x0f, 0x12, 0x82, 0x99, 0x4f, 0x79, 0xbe, 0x45, 0xbb, 0x87, 0x03, 0xec, 0x1d, 0x6e,
-	0x09, 0xbe, 0x93, 0x0a, 0xda, 0x5f, 0x43, 0x85, 0x73, 0x34, 0x98, 0xd4, 0x91, 0x25, 0x87, 0x7d,
-	0x32, 0x98, 0xc2, 0x41, 0xf7, 0x6c, 0x98, 0x98, 0x10, 0x13, 0x0e, 0xf0, 0x2b, 0x11, 0x4b, 0xa7,
-	0x09, 0x33, 0x21, 0x3b, 0x81, 0xaa, 0x3c, 0xfb, 0xd8, 0x65, 0x3f, 0x88, 0x12, 0x19, 0xdd, 0xa7,
-	0xb9, 0x2c, 0xc4, 0x2e, 0xe7, 0xf2, 0x86, 0x74, 0x59, 0x72, 0xe4, 0xd5, 0x46, 0x98, 0x50, 0xf5,
-	0x42, 0x53, 0x9a, 0xa5, 0x2f, 0x82, 0xdd, 0x38, 0x0d, 0x32, 0x49, 0xd9, 0xbf, 0x58, 0x50, 0x6e,
-	0xfb, 0xbd, 0x9e, 0x82, 0xad, 0x06, 0x39, 0xbf, 0x2b, 0x77, 0xe7, 0xfc, 0xae, 0x82, 0x31, 0x37,
-	0x09, 0x63, 0xfe, 0x22, 0x30, 0x16, 0xce, 0x01, 0x23, 0x4b, 0x0d, 0x7e, 0x3f, 0x08, 0x09, 0xde,
-	0x3c, 0x70, 0x83, 0x3e, 0x0f, 0xb1, 0xfc, 0x52, 0xc9, 0x31, 0x17, 0xed, 0xdf, 0x2c, 0xa8, 0xec,
-	0x49, 0xb7, 0x98, 0xe5, 0x68, 0x05, 0x0a, 0x87, 0x7e, 0x20, 0x8c, 0xae, 0xad, 0x2e, 0x68, 0xb8,
-	0xe9, 0x62, 0xad, 0x87, 0x7e, 0xd0, 0x75, 0xb8, 0x24, 0x5a, 0x80, 0x12, 0xc7, 0x9d, 0xad, 0xcb,
-	0xbc, 0x72, 0xbc, 0x60, 0xbf, 0x80, 0x02, 0x93, 0x45, 0x33, 0x90, 0x5f, 0x6f, 0xb7, 0xeb, 0x53,
-	0xe8, 0x0a, 0x94, 0xd7, 0xdb, 0xed, 0xe7, 0x4e, 0x67, 0xef, 0xd1, 0xfa, 0x66, 0xa7, 0x6e, 0x21,
-	0x80, 0x62, 0xbb, 0xf3, 0xa8, 0xf3, 0xb4, 0x53, 0xcf, 0x21, 0x04, 0x35, 0xf1, 0x9d, 0xf2, 0xf3,
-	0x8c, 0xff, 0x6c, 0xaf, 0xbd, 0xfe, 0xb4, 0x53, 0x2f, 0x30, 0xbe, 0xf8, 0x4e, 0xf9, 0xd3, 0xf6,
-	0x1f, 0x79, 0xa8, 0x08, 0xd0, 0x65, 0xbc, 0x34, 0x61, 0x96, 0xe0, 0x68, 0xe0, 0x7a, 0xb2, 0x5c,
-	0x94, 0x9c, 0x94, 0x66, 0x8f, 0x32, 0xa6, 0xa2, 0x92, 0xe4, 0x38, 0x4b, 0x91, 0x68, 0x05, 0xde,
-	0xe8, 0xe2, 0x01, 0xa6, 0x78, 0x03, 0xf7, 0x42, 0x96, 0x62, 0xf9, 0x0e, 0x99, 0xfe, 0xb2, 0x58,
-	0xe8, 0x3e, 0xcc, 0x78, 0x12, 0xdb, 0x02, 0x47, 0xeb, 0x2d, 0x0d, 0x2d, 0xdd, 0x22, 0x4e, 0x48,
-	0xc4, 0x1d, 0xb5, 0x87, 0xe5, 0xfa, 0xae, 0xdf, 0xeb, 0xa9, 0x8b, 0x11, 0x04, 0x7a, 0x0c, 0x95,
-	0x2e, 0xa6, 0xae, 0x3f, 0xc0, 0x5d, 0x0e, 0x68, 0x91, 0xc7, 0xef, 0xbb, 0x27, 0x6a, 0xd6, 0x64,
-	0x45, 0xb9, 0x33, 0xb6, 0xb3, 0x54, 0x73, 0xe0, 0xc6, 0xba, 0x54, 0x63, 0x46, 0xa4, 0x9a, 0xb1,
-	0xe5, 0xe6, 0xe7, 0x30, 0x37, 0xa1, 0x2c, 0xa3, 0x42, 0xdd, 0xd6, 0x2b, 0x94, 0xf9, 0xb0, 0xf4,
-	0x00, 0xd1, 0x4b, 0xd7, 0x7d, 0xf1, 0x28, 0x24, 0x00, 0xa8, 0x0e, 0x95, 0xf6, 0xce, 0xd6, 0xd6,
-	0xf3, 0x67, 0xbb, 0x0f, 0x77, 0x9f, 0x7c, 0xb6, 0x5b, 0x9f, 0x42, 0x55, 0x28, 0xf1, 0x95, 0xdd,
-	0x27, 0xbb, 0x2c, 0x20, 0x14, 0xb9, 0xff, 0xe4, 0x71, 0xa7, 0x9e, 0xb3, 0xbf, 0xb7, 0xa0, 0xba,
-	0x49, 0xb0, 0x4b, 0xf1, 0xc9, 0xd9, 0xe8, 0x03, 0x00, 0xf9, 0x38, 0x7d, 0x7c, 0x66, 0x4e, 0xd2,
-	0x44, 0x59, 0x3c, 0x50, 0x7f, 0x88, 0xc3, 0x84, 0xf2, 0x9b, 0xb6, 0x1c, 0x45, 0x32, 0x4e, 0x24,
-	0x8b, 0xa5, 0x28, 0xe8, 0x8a, 0xb4, 0xbf, 0x80, 0x9a, 0xb2, 0x47, 0x46, 0xdc, 0xf8, 0x3b, 0xbf,
-	0xac, 0x39, 0xf6, 0x8f, 0x16, 0x94, 0x1d, 0xec, 0x76, 0xcf, 0x9f, 0x40, 0xcc, 0xa3, 0xf2, 0xe7,
-	0xf7, 0xfc, 0x38, 0xab, 0x16, 0xce, 0x95, 0x55, 0xed, 0x6f, 0x2d, 0xa8, 0x08, 0xdb, 0x5e, 0xb3,
-	0xd7, 0x9a, 0x29, 0xf9, 0xf3, 0x99, 0xf2, 0xa7, 0x05, 0xd5, 0x67, 0x51, 0x57, 0x0b, 0x89, 0xff,
-	0x33, 0xd3, 0x6a, 0x31, 0x34, 0x6d, 0xc6, 0xd0, 0x44, 0x0e, 0x2e, 0x66, 0xe4, 0x60, 0x3d, 0xd2,
-	0x66, 0xcc, 0x48, 0xdb, 0x81, 0x9a, 0x72, 0x53, 0x62, 0x6e, 0x62, 0x6c, 0x9d, 0x3f, 0xb2, 0xbe,
-	0xb1, 0xa0, 0xda, 0xe6, 0x49, 0xec, 0x3f, 0x88, 0x2d, 0x0d, 0x91, 0x82, 0x81, 0x88, 0xfd, 0x77,
-	0x91, 0x37, 0xf8, 0x62, 0x9e, 0xd0, 0x86, 0x87, 0x88, 0x84, 0x5f, 0x62, 0x8f, 0x4a, 0x73, 0x14,
-	0xc9, 0x72, 0x64, 0x4c, 0x5d, 0xef, 0x50, 0xf5, 0xc3, 0x9c, 0x40, 0x0f, 0xa0, 0xe8, 0xf1, 0xfe,
-	0xb1, 0x91, 0xe7, 0xd9, 0xf1, 0x1d, 0xb3, 0xb1, 0x34, 0x94, 0xcb, 0x4e, 0x53, 0xe4, 0x46, 0xb9,
-	0x8d, 0xd5, 0xef, 0x2e, 0x39, 0x72, 0x92, 0x40, 0x3e, 0x6d, 0x49, 0xf1, 0x9a, 0xef, 0x12, 0x77,
-	0x30, 0xc0, 0x03, 0x7e, 0x95, 0xd3, 0x4e, 0x4a, 0xb3, 0x4c, 0x3a, 0x0c, 0x03, 0x9f, 0x86, 0xa4,
-	0x13, 0x74, 0xa3, 0xd0, 0x0f, 0x68, 0xa3, 0xc8, 0x8d, 0x1a, 0x5f, 0x66, 0xbd, 0x29, 0x3d, 0x8a,
-	0x30, 0xbf, 0xcc, 0x92, 0xc3, 0xbf, 0xd3, 0x7e, 0x75, 0x56, 0xeb, 0x57, 0xe7, 0xa1, 0x18, 0xb9,
-	0x04, 0x07, 0xb4, 0x51, 0x12, 0x5d, 0x84, 0xa0, 0xb4, 0xe7, 0x00, 0xe7, 0xeb, 0x77, 0x5e, 0xc0,
-	0x9c, 0x28, 0xb8, 0x38, 0xc2, 0x41, 0x17, 0x07, 0x1e, 0xbb, 0xae, 0x32, 0x87, 0x66, 0xf5, 0x34,
-	0x68, 0x76, 0xc6, 0x37, 0x09, 0x94, 0x26, 0x95, 0xc9, 0x1b, 0xa2, 0xec, 0x86, 0x2a, 0x2a, 0x44,
-	0x39, 0xc9, 0x86, 0x33, 0xd5, 0xf1, 0xc6, 0x8d, 0x6a, 0xd6, 0x70, 0x66, 0x9e, 0xb9, 0xa7, 0x84,
-	0xe5, 0x70, 0x96, 0x6e, 0x66, 0x67, 0xb8, 0x03, 0xdf, 0x8d, 0x71, 0xdc, 0xa8, 0x89, 0xd2, 0x2c,
-	0x49, 0x64, 0xb3, 0x9a, 0xa8, 0xb9, 0x76, 0x85, 0xb3, 0x8d, 0xb5, 0xe6, 0x4d, 0xb8, 0x9a, 0xd6,
-	0x1f, 0xdd, 0x72, 0x04, 0x85, 0x84, 0x04, 0xaa, 0x11, 0xe0, 0xdf, 0xcd, 0xbb, 0x50, 0xd6, 0xa2,
-	0xe2, 0x22, 0x63, 0x58, 0x73, 0x04, 0xf3, 0xd9, 0xa8, 0x65, 0x68, 0xd9, 0x32, 0x4b, 0xe5, 0xca,
-	0x19, 0xb0, 0x4c, 0xd8, 0xae, 0x9f, 0xbb, 0x06, 0x35, 0x13, 0xb9, 0x0b, 0x0d, 0x8f, 0xbf, 0xe7,
-	0xf8, 0xf0, 0xa8, 0x8e, 0x94, 0xb9, 0x64, 0xb2, 0x8c, 0xde, 0xe6, 0xcf, 0x8d, 0xe2, 0xb3, 0x92,
-	0xb7, 0x90, 0x42, 0x2e, 0xcc, 0xf1, 0x0f, 0x23, 0xee, 0xc4, 0x93, 0xbc, 0x93, 0xed, 0xac, 0xec,
-	0x5a, 0xf6, 0xc7, 0x77, 0xc9, 0xc0, 0x9b, 0xd0, 0x76, 0xa1, 0x6b, 0x7d, 0x05, 0xf3, 0xd9, 0x8a,
-	0x33, 0xb0, 0xda, 0x36, 0xef, 0xe6, 0xfd, 0x53, 0xcd, 0x3d, 0xe3, 0x72, 0xec, 0x9f, 0x2c, 0xb8,
-	0xc6, 0xe7, 0x58, 0x35, 0xb8, 0xed, 0x04, 0x3e, 0xdd, 0xe2, 0xad, 0xd4, 0xeb, 0x2b, 0x92, 0x0d,
-	0x98, 0x11, 0x53, 0x86, 0x80, 0xb8, 0xe4, 0x28, 0xf2, 0xc2, 0x95, 0x7c, 0xf5, 0xaf, 0x19, 0xa8,
-	0x2b, 0x53, 0x55, 0x54, 0xb1, 0x87, 0x9c, 0xfe, 0xa7, 0x41, 0x37, 0x34, 0x3c, 0xc6, 0xff, 0xf5,
-	0x34, 0x17, 0xb2, 0x99, 0x02, 0x2c, 0x7b, 0x0a, 0x6d, 0x40, 0x99, 0x4f, 0x52, 0xe2, 0x8d, 0xa1,
-	0x89, 0xd9, 0x4b, 0xe9, 0x69, 0x4c, 0x32, 0x52, 0x1d, 0x0f, 0x00, 0x78, 0xcf, 0x28, 0xf3, 0xf5,
-	0x44, 0xfb, 0x2b, 0x34, 0x5c, 0x3b, 0xa1, 0x2d, 0xb6, 0xa7, 0x98, 0x3b, 0xe9, 0x3f, 0x06, 0xc3,
-	0x9d, 0xf1, 0xdf, 0x45, 0x86, 0x3b, 0x13, 0x7f, 0x58, 0xb8, 0x29, 0x45, 0x31, 0x83, 0x23, 0xdd,
-	0x60, 0xe3, 0x37, 0x42, 0xf3, 0x7a, 0x06, 0x27, 0x55, 0xb0, 0x0d, 0x95, 0x7d, 0x4a, 0xb0, 0x3b,
-	0xfc, 0x57, 0x6a, 0x56, 0x2c, 0xb4, 0x06, 0xd3, 0x1c, 0xa7, 0xcb, 0x41, 0x7a, 0x17, 0x0a, 0x7c,
-	0x24, 0xb8, 0x04, 0x98, 0x0f, 0xa0, 0x28, 0x3a, 0x5e, 0xc3, 0x76, 0xa3, 0x29, 0x37, 0x6c, 0x37,
-	0xdb, 0x63, 0x71, 0x36, 0x6b, 0x1d, 0x8d, 0xb3, 0xb5, 0x3e, 0xd7, 0x38, 0x5b, 0xef, 0x31, 0xc5,
-	0xd9, 0xa2, 0x07, 0x32, 0xce, 0x36, 0xba, 0x3f, 0xe3, 0x6c, 0xb3, 0x61, 0xb2, 0xa7, 0xd0, 0x1a,
-	0x14, 0x45, 0xe3, 0x63, 0x28, 0x30, 0x7a, 0xa1, 0xe6, 0xfc, 0xc4, 0x93, 0xe9, 0x0c, 0x23, 0x7a,
-	0x94, 0xc6, 0x91, 0x48, 0x08, 0xe3, 0x71, 0x64, 0xa4, 0xf0, 0xf1, 0x38, 0x32, 0x73, 0x88, 0x3d,
-	0x85, 0xee, 0x41, 0x71, 0xd3, 0x0d, 0x3c, 0x3c, 0x40, 0x27, 0x9c, 0x76, 0x8a, 0x15, 0x1f, 0x41,
-	0x75, 0x1b, 0xd3, 0x3d, 0xfe, 0x03, 0x77, 0x27, 0xe8, 0x85, 0x27, 0xaa, 0x78, 0x53, 0x9f, 0xc7,
-	0x52, 0x71, 0x7b, 0xea, 0x65, 0x91, 0x0b, 0xde, 0xf9, 0x27, 0x00, 0x00, 0xff, 0xff, 0xac, 0xa3,
-	0xd1, 0x1f, 0x21, 0x16, 0x00, 0x00,
+	// 1847 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x5f, 0x73, 0xdc, 0x48,
+	0x11, 0xb7, 0x76, 0xd7, 0x6b, 0x6f, 0xef, 0x9f, 0xac, 0x27, 0xc1, 0xde, 0xe8, 0xfc, 0xe0, 0x12,
+	0x54, 0x61, 0x72, 0xdc, 0x26, 0x38, 0x0f, 0xdc, 0x5d, 0xe5, 0x2a, 0x97, 0x78, 0xd7, 0xc1, 0x95,
+	0xc4, 0x31, 0x72, 0xc2, 0x1d, 0x4f, 0x77, 0x8a, 0x34, 0x6b, 0x0b, 0x6b, 0x25, 0x31, 0x1a, 0x39,
+	0x65, 0x9e, 0x79, 0xa0, 0xa8, 0x82, 0x57, 0x8a, 0x0f, 0x01, 0x54, 0xf1, 0x4e, 0x15, 0x5f, 0x84,
+	0x47, 0x3e, 0x00, 0xdf, 0x80, 0x9a, 0x7f, 0xda, 0x19, 0xad, 0xd6, 0x5e, 0x87, 0x14, 0xf7, 0xa6,
+	0x9e, 0xee, 0xe9, 0xe9, 0xfe, 0x4d, 0x4f, 0x4f, 0xf7, 0x08, 0x7a, 0x29, 0x49, 0x2e, 0xc2, 0x00,
+	0x93, 0x61, 0x4a, 0x12, 0x9a, 0xa0, 0x56, 0x9a, 0x47, 0xf9, 0x34, 0x24, 0xa9, 0x6f, 0x77, 0xd2,
+	0x28, 0x3f, 0x0d, 0x63, 0xc1, 0xb0, 0x3f, 0x3a, 0x4d, 0x92, 0xd3, 0x08, 0xdf, 0xe7, 0xd4, 0xdb,
+	0x7c, 0x72, 0x1f, 0x4f, 0x53, 0x7a, 0x29, 0x99, 0xdb, 0x65, 0x66, 0x46, 0x49, 0xee, 0x53, 0xc1,
+	0x75, 0x7e, 0x0c, 0xfd, 0x67, 0x98, 0x9e, 0xf8, 0x67, 0x78, 0xea, 0xb9, 0xf8, 0xd7, 0x39, 0xce,
+	0x28, 0x1a, 0xc0, 0xda, 0x05, 0x26, 0x59, 0x98, 0xc4, 0x03, 0x6b, 0xc7, 0xda, 0x5d, 0x75, 0x15,
+	0xe9, 0x7c, 0x0c, 0x1b, 0x9a, 0x74, 0x96, 0x26, 0x71, 0x86, 0xd1, 0x26, 0x34, 0x33, 0x3e, 0xc2,
+	0xa5, 0x5b, 0xae, 0xa4, 0x9c, 0x3f, 0xd5, 0xa0, 0xbf, 0x9f, 0xc4, 0x93, 0xf0, 0x34, 0x27, 0x58,
+	0xe9, 0xfe, 0x19, 0xb4, 0x2e, 0x3c, 0x12, 0x7a, 0x6f, 0x23, 0x9c, 0x0d, 0xac, 0x9d, 0xfa, 0x6e,
+	0x7b, 0xef, 0xde, 0xb0, 0xf0, 0x6b, 0x58, 0x96, 0x1f, 0xfe, 0x42, 0x09, 0x8f, 0x63, 0x4a, 0x2e,
+	0xdd, 0xd9, 0x64, 0xf4, 0x31, 0x34, 0x3c, 0x72, 0x9a, 0x0d, 0x6a, 0x3b, 0xd6, 0x6e, 0x7b, 0x6f,
+	0x6b, 0x28, 0xdc, 0x1c, 0x2a, 0x37, 0x87, 0x27, 0xdc, 0x4d, 0x97, 0x0b, 0xa1, 0x1f, 0x40, 0xd7,
+	0xf3, 0x7d, 0x9c, 0xd2, 0x13, 0xec, 0x13, 0x4c, 0xb3, 0x41, 0x7d, 0xc7, 0xda, 0x5d, 0x77, 0xcd,
+	0x41, 0xb4, 0x0b, 0xb7, 0xc4, 0x80, 0x8b, 0xb3, 0x24, 0x27, 0x3e, 0xce, 0x06, 0x0d, 0x2e, 0x57,
+	0x1e, 0xb6, 0x1f, 0x41, 0xcf, 0xb4, 0x0c, 0xf5, 0xa1, 0x7e, 0x8e, 0x2f, 0x25, 0x04, 0xec, 0x13,
+	0xdd, 0x81, 0xd5, 0x0b, 0x2f, 0xca, 0x31, 0xb7, 0xb0, 0xe5, 0x0a, 0xe2, 0xf3, 0xda, 0xa7, 0x96,
+	0xf3, 0x07, 0x0b, 0x36, 0x34, 0x4f, 0x25, 0x8e, 0x73, 0x36, 0x5a, 0x0b, 0x6c, 0xcc, 0xf2, 0x34,
+	0x4d, 0x08, 0xcd, 0x8e, 0x09, 0xbe, 0x08, 0xf1, 0x3b, 0xae, 0x7f, 0xdd, 0x2d, 0x0f, 0x57, 0x79,
+	0x53, 0xaf, 0xf4, 0xc6, 0xf9, 0xbb, 0x05, 0x77, 0x0b, 0x7b, 0xc6, 0x84, 0x24, 0xe4, 0x65, 0x98,
+	0x65, 0x61, 0x7c, 0xfa, 0x1c, 0x5f, 0x66, 0xe8, 0xe7, 0xd0, 0x9e, 0xce, 0x48, 0xb9, 0x69, 0xf7,
+	0xab, 0x36, 0xad, 0x3c, 0x75, 0x38, 0xfb, 0x76, 0x75, 0x1d, 0xf6, 0x53, 0x80, 0x19, 0x0b, 0x21,
+	0x68, 0xc4, 0xde, 0x14, 0x4b, 0xec, 0xf8, 0x37, 0xda, 0x81, 0x76, 0x80, 0x33, 0x9f, 0x84, 0x29,
+	0x65, 0x71, 0x28, 0x20, 0xd4, 0x87, 0x9c, 0xbf, 0x5a, 0xd0, 0x3d, 0x8c, 0x2f, 0x92, 0xf3, 0x22,
+	0xb6, 0xfa, 0x50, 0xa7, 0xc9, 0xb9, 0xda, 0x02, 0x9a, 0x9c, 0xdf, 0x2c, 0x46, 0x6c, 0x58, 0x57,
+	0x07, 0x8e, 0x03, 0xd5, 0x72, 0x0b, 0x5a, 0x3f, 0x12, 0x0d, 0xce, 0x52, 0x64, 0x15, 0xca, 0xab,
+	0xd5, 0x28, 0x5f, 0x40, 0x4f, 0xd9, 0x2b, 0x77, 0xfc, 0x3e, 0x34, 0x09, 0xa6, 0x39, 0x11, 0xe7,
+	0xec, 0x0a, 0x03, 0xa5, 0x18, 0x7a, 0x08, 0xeb, 0x13, 0x2f, 0x8c, 0x72, 0x82, 0x99, 0x4f, 0x75,
+	0x3e, 0x45, 0xdb, 0x87, 0x33, 0xec, 0x9f, 0x1f, 0x08, 0xbe, 0x5b, 0x08, 0x3a, 0xbf, 0x81, 0x0e,
+	0xe7, 0x68, 0x30, 0xa9, 0x25, 0x5b, 0x2e, 0xfb, 0x64, 0x30, 0x25, 0x51, 0x70, 0x3d, 0x4c, 0x4c,
+	0x88, 0x09, 0xc7, 0xf8, 0x9d, 0x88, 0xa5, 0xab, 0x84, 0x99, 0x90, 0x93, 0x43, 0x57, 0xae, 0x3d,
+	0x73, 0x39, 0x8c, 0xd3, 0x5c, 0x46, 0xf7, 0x55, 0x2e, 0x0b, 0xb1, 0xf7, 0x73, 0xf9, 0x29, 0x74,
+	0x74, 0x8e, 0xdc, 0xda, 0x14, 0x13, 0xaa, 0x4e, 0x68, 0x41, 0xb3, 0xf4, 0x45, 0xb0, 0x97, 0x15,
+	0x41, 0x26, 0x29, 0xe7, 0x6f, 0x16, 0xb4, 0x47, 0xe1, 0x64, 0xa2, 0x60, 0xeb, 0x41, 0x2d, 0x0c,
+	0xe4, 0xec, 0x5a, 0x18, 0x28, 0x18, 0x6b, 0xf3, 0x30, 0xd6, 0x6f, 0x02, 0x63, 0x63, 0x09, 0x18,
+	0x59, 0x6a, 0x08, 0x4f, 0xe3, 0x84, 0xe0, 0xfd, 0x33, 0x2f, 0x3e, 0xe5, 0x21, 0x56, 0xdf, 0x6d,
+	0xb9, 0xe6, 0xa0, 0xf3, 0x4f, 0x0b, 0x3a, 0xc7, 0xd2, 0x2d, 0x66, 0x39, 0x7a, 0x00, 0x8d, 0xf3,
+	0x30, 0x16, 0x46, 0xf7, 0xf6, 0xb6, 0x35, 0xdc, 0x74, 0xb1, 0xe1, 0xf3, 0x30, 0x0e, 0x5c, 0x2e,
+	0x89, 0xb6, 0xa1, 0xc5, 0x71, 0x67, 0xe3, 0x32, 0xaf, 0xcc, 0x06, 0x9c, 0x6f, 0xa1, 0xc1, 0x64,
+	0xd1, 0x1a, 0xd4, 0x9f, 0x8c, 0x46, 0xfd, 0x15, 0x74, 0x0b, 0xda, 0x4f, 0x46, 0xa3, 0x6f, 0xdc,
+	0xf1, 0xf1, 0x8b, 0x27, 0xfb, 0xe3, 0xbe, 0x85, 0x00, 0x9a, 0xa3, 0xf1, 0x8b, 0xf1, 0xeb, 0x71,
+	0xbf, 0x86, 0x10, 0xf4, 0xc4, 0x77, 0xc1, 0xaf, 0x33, 0xfe, 0x9b, 0xe3, 0xd1, 0x93, 0xd7, 0xe3,
+	0x7e, 0x83, 0xf1, 0xc5, 0x77, 0xc1, 0x5f, 0x75, 0xfe, 0x55, 0x87, 0x8e, 0x00, 0x5d, 0xc6, 0x8b,
+	0x0d, 0xeb, 0x04, 0xa7, 0x91, 0xe7, 0xcb, 0xeb, 0xa2, 0xe5, 0x16, 0x34, 0x3b, 0x94, 0x19, 0x15,
+	0x37, 0x49, 0x8d, 0xb3, 0x14, 0x89, 0x1e, 0xc0, 0xed, 0x00, 0x47, 0x98, 0xe2, 0xa7, 0x78, 0x92,
+	0xb0, 0x14, 0xcb, 0x67, 0xc8, 0xf4, 0x57, 0xc5, 0x42, 0x5f, 0xc0, 0x9a, 0x2f, 0xb1, 0x6d, 0x70,
+	0xb4, 0xbe, 0xaf, 0xa1, 0xa5, 0x5b, 0xc4, 0x09, 0x89, 0xb8, 0xab, 0xe6, 0xb0, 0x5c, 0x1f, 0x84,
+	0x93, 0x89, 0xda, 0x18, 0x41, 0xa0, 0x97, 0xd0, 0x09, 0x30, 0xf5, 0xc2, 0x08, 0x07, 0x1c, 0xd0,
+	0x26, 0x8f, 0xdf, 0x1f, 0x2d, 0xd4, 0xac, 0xc9, 0x8a, 0xeb, 0xce, 0x98, 0xce, 0x52, 0xcd, 0x99,
+	0x97, 0xe9, 0x52, 0x83, 0x35, 0x91, 0x6a, 0x4a, 0xc3, 0xf6, 0xd7, 0xb0, 0x31, 0xa7, 0xac, 0xe2,
+	0x86, 0xfa, 0x44, 0xbf, 0xa1, 0xcc, 0x83, 0xa5, 0x07, 0x88, 0x7e, 0x75, 0x7d, 0x01, 0x6d, 0x0d,
+	0x00, 0xd4, 0x87, 0xce, 0xe8, 0xf0, 0xe0, 0xe0, 0x9b, 0x37, 0x47, 0xcf, 0x8f, 0x5e, 0x7d, 0x75,
+	0xd4, 0x5f, 0x41, 0x5d, 0x68, 0xf1, 0x91, 0xa3, 0x57, 0x47, 0x2c, 0x20, 0x14, 0x79, 0xf2, 0xea,
+	0xe5, 0xb8, 0x5f, 0x73, 0xfe, 0x68, 0x41, 0x77, 0x9f, 0x60, 0x8f, 0xe2, 0xc5, 0xd9, 0xe8, 0xa7,
+	0x00, 0xf2, 0x70, 0x86, 0xf8, 0xda, 0x9c, 0xa4, 0x89, 0xb2, 0x78, 0xa0, 0xe1, 0x14, 0x27, 0x39,
+	0xe5, 0x3b, 0x6d, 0xb9, 0x8a, 0x64, 0x9c, 0x54, 0x5e, 0x96, 0xe2, 0x42, 0x57, 0xa4, 0xf3, 0x4b,
+	0xe8, 0x29, 0x7b, 0x64, 0xc4, 0x95, 0xcf, 0xf9, 0xfb, 0x9a, 0xe3, 0xfc, 0xd9, 0x82, 0xb6, 0x8b,
+	0xbd, 0x60, 0xf9, 0x04, 0x62, 0x2e, 0x55, 0x5f, 0xde, 0xf3, 0x59, 0x56, 0x6d, 0x2c, 0x95, 0x55,
+	0x9d, 0xdf, 0x59, 0xd0, 0x11, 0xb6, 0x7d, 0x60, 0xaf, 0x35, 0x53, 0xea, 0xcb, 0x99, 0xf2, 0x6f,
+	0x0b, 0xba, 0x6f, 0xd2, 0x40, 0x0b, 0x89, 0xef, 0x32, 0xd3, 0x6a, 0x31, 0xb4, 0x6a, 0xc6, 0xd0,
+	0x5c, 0x0e, 0x6e, 0x56, 0xe4, 0x60, 0x3d, 0xd2, 0xd6, 0xcc, 0x48, 0x3b, 0x84, 0x9e, 0x72, 0x53,
+	0x62, 0x6e, 0x62, 0x6c, 0x2d, 0x1f, 0x59, 0xbf, 0xb5, 0xa0, 0x3b, 0xe2, 0x49, 0xec, 0xff, 0x10,
+	0x5b, 0x1a, 0x22, 0x0d, 0x03, 0x11, 0xe7, 0x1f, 0x6d, 0x5e, 0xe0, 0x8b, 0x7e, 0x42, 0x6b, 0x1e,
+	0x52, 0x92, 0xfc, 0x0a, 0xfb, 0x54, 0x9a, 0xa3, 0x48, 0x96, 0x23, 0x33, 0xea, 0xf9, 0xe7, 0xaa,
+	0x1e, 0xe6, 0x04, 0x7a, 0x0c, 0x4d, 0x9f, 0xd7, 0x8f, 0x83, 0x3a, 0xcf, 0x8e, 0x3f, 0x34, 0x0b,
+	0x4b, 0x43, 0xb9, 0xac, 0x34, 0x45, 0x6e, 0x94, 0xd3, 0xd8, 0xfd, 0x1d, 0x90, 0x4b, 0x37, 0x8f,
+	0xe5, 0xd1, 0x96, 0x14, 0xbf, 0xf3, 0x3d, 0xe2, 0x45, 0x11, 0x8e, 0xf8, 0x56, 0xae, 0xba, 0x05,
+	0xcd, 0x32, 0xe9, 0x34, 0x89, 0x43, 0x9a, 0x90, 0x71, 0x1c, 0xa4, 0x49, 0x18, 0xd3, 0x41, 0x93,
+	0x1b, 0x55, 0x1e, 0x66, 0xb5, 0x29, 0xbd, 0x4c, 0x31, 0xdf, 0xcc, 0x96, 0xcb, 0xbf, 0x8b, 0x7a,
+	0x75, 0x5d, 0xab, 0x57, 0x37, 0xa1, 0x99, 0x7a, 0x04, 0xc7, 0x74, 0xd0, 0xe2, 0xa3, 0x92, 0xd2,
+	0x8e, 0x03, 0x2c, 0x57, 0xef, 0x7c, 0x0b, 0x1b, 0xfc, 0x6b, 0x84, 0x53, 0x1c, 0x07, 0x38, 0xf6,
+	0xd9, 0x76, 0xb5, 0x39, 0x34, 0x7b, 0x57, 0x41, 0x73, 0x58, 0x9e, 0x24, 0x50, 0x9a, 0x57, 0x26,
+	0x77, 0x88, 0xb2, 0x1d, 0xea, 0xa8, 0x10, 0xe5, 0x24, 0x6b, 0xce, 0x54, 0xc5, 0x9b, 0x0d, 0xba,
+	0x55, 0xcd, 0x99, 0xb9, 0xe6, 0xb1, 0x12, 0x96, 0xcd, 0x59, 0x31, 0x99, 0xad, 0xe1, 0x45, 0xa1,
+	0x97, 0xe1, 0x6c, 0xd0, 0x13, 0x57, 0xb3, 0x24, 0x91, 0xc3, 0xee, 0x44, 0xcd, 0xb5, 0x5b, 0x9c,
+	0x6d, 0x8c, 0xb1, 0xd9, 0x04, 0x4f, 0x08, 0xce, 0xce, 0x06, 0x7d, 0x61, 0xa1, 0x24, 0xd1, 0x57,
+	0xd0, 0x13, 0xdb, 0xfe, 0x12, 0x53, 0x2f, 0xf0, 0xa8, 0x37, 0xd8, 0xa8, 0x6a, 0x47, 0xaa, 0xa2,
+	0x46, 0xcd, 0x10, 0xb6, 0x96, 0xd4, 0x20, 0x17, 0x7a, 0x7e, 0x9e, 0xd1, 0x64, 0xfa, 0x5a, 0x04,
+	0x77, 0x36, 0x40, 0x3b, 0xd6, 0x75, 0xfe, 0xef, 0x1b, 0x33, 0xdc, 0x92, 0x06, 0xf4, 0x29, 0x6c,
+	0x79, 0x41, 0x10, 0xb2, 0x6e, 0xc5, 0x8b, 0x44, 0xff, 0xf6, 0x2a, 0xa7, 0x3c, 0x18, 0x6e, 0x73,
+	0xaf, 0x17, 0xb1, 0x17, 0xd5, 0x2f, 0x77, 0x16, 0xd6, 0x2f, 0xf6, 0x3d, 0xb8, 0x53, 0x5c, 0xd9,
+	0x3a, 0x94, 0x08, 0x1a, 0x39, 0x89, 0x55, 0xed, 0xc4, 0xbf, 0xed, 0x17, 0xd0, 0x33, 0x21, 0x29,
+	0x77, 0xfc, 0x5a, 0x7b, 0xb3, 0x03, 0xed, 0xc8, 0xcb, 0xa8, 0x9b, 0x50, 0x8f, 0xe2, 0x40, 0xf5,
+	0x61, 0xda, 0x90, 0xfd, 0x35, 0xf4, 0x4c, 0x1c, 0xd8, 0x59, 0xf0, 0xf9, 0x9d, 0xaa, 0x1e, 0x04,
+	0x04, 0xc5, 0xc6, 0x73, 0x9e, 0x01, 0x55, 0xa5, 0x2d, 0x28, 0x7e, 0x82, 0xb9, 0x4b, 0xb2, 0xed,
+	0x92, 0x94, 0xfd, 0x19, 0xb4, 0xb5, 0x03, 0x7f, 0x93, 0x0e, 0xdb, 0xbe, 0x80, 0xcd, 0xea, 0x03,
+	0x51, 0xa1, 0xe5, 0xc0, 0xac, 0x82, 0x1e, 0x5c, 0x13, 0xf1, 0x73, 0x18, 0xeb, 0xeb, 0x3e, 0x82,
+	0x9e, 0x79, 0x28, 0x6e, 0x64, 0xf5, 0x14, 0x6e, 0x57, 0xc4, 0x6a, 0x85, 0x8a, 0x2f, 0x4d, 0x93,
+	0xef, 0x2d, 0x1f, 0xfd, 0x7a, 0x2d, 0xf7, 0xfb, 0x3a, 0x6c, 0x68, 0xe2, 0xf2, 0x56, 0x9a, 0x2f,
+	0xc8, 0x3e, 0xe1, 0x89, 0x9b, 0xe2, 0xeb, 0xca, 0x00, 0x21, 0x85, 0x3c, 0xd8, 0xe0, 0x1f, 0x46,
+	0x06, 0x13, 0xc9, 0xfd, 0x61, 0xb5, 0xa1, 0x62, 0xe5, 0xe1, 0x49, 0x79, 0x96, 0x4c, 0x61, 0x73,
+	0xda, 0x58, 0x9b, 0x32, 0x4b, 0x54, 0x0d, 0x1e, 0xda, 0xb3, 0x01, 0x79, 0x19, 0xae, 0xaa, 0xcb,
+	0xf0, 0x46, 0x67, 0xe3, 0x1d, 0x6c, 0x56, 0x9b, 0x51, 0xb1, 0x0b, 0xcf, 0xcc, 0x5d, 0xf8, 0xc9,
+	0x95, 0xce, 0x5d, 0x13, 0x39, 0xce, 0x5f, 0x2c, 0xd8, 0xe2, 0xef, 0x27, 0xea, 0xc1, 0xe0, 0x30,
+	0x0e, 0xe9, 0x01, 0x2f, 0xe1, 0x3f, 0x5c, 0x71, 0xc6, 0x13, 0x2b, 0xeb, 0x6e, 0xc5, 0x86, 0xb4,
+	0x5c, 0x45, 0xde, 0xb8, 0x82, 0xdc, 0xfb, 0xcf, 0x1a, 0xf4, 0x95, 0xa9, 0x2a, 0xe4, 0xd9, 0x05,
+	0x52, 0xbc, 0x0f, 0xa2, 0x8f, 0x34, 0x3c, 0xca, 0x6f, 0x8c, 0xf6, 0x76, 0x35, 0x53, 0x80, 0xe5,
+	0xac, 0xa0, 0xa7, 0xd0, 0xe6, 0x1d, 0xbc, 0x88, 0x5e, 0x34, 0xd7, 0xf3, 0x2b, 0x3d, 0x83, 0x79,
+	0x46, 0xa1, 0xe3, 0x31, 0x00, 0xef, 0x55, 0x64, 0x9d, 0x30, 0xd7, 0x76, 0x09, 0x0d, 0x5b, 0x0b,
+	0xda, 0x31, 0x67, 0x85, 0xb9, 0x53, 0xbc, 0x6d, 0x19, 0xee, 0x94, 0x9f, 0x29, 0xed, 0xed, 0x6a,
+	0xa6, 0x66, 0x4a, 0x53, 0xbc, 0xfd, 0x20, 0xdd, 0x60, 0xe3, 0xf9, 0xca, 0xbe, 0x5b, 0xc1, 0x29,
+	0x14, 0x3c, 0x83, 0xce, 0x09, 0x25, 0xd8, 0x9b, 0xfe, 0x4f, 0x6a, 0x1e, 0x58, 0xe8, 0x11, 0xac,
+	0x72, 0x9c, 0xde, 0x0f, 0xd2, 0xcf, 0xa0, 0xc1, 0x5b, 0xd1, 0xf7, 0x00, 0xf3, 0x31, 0x34, 0x45,
+	0xa7, 0x65, 0xd8, 0x6e, 0x34, 0x83, 0xf6, 0xdd, 0x0a, 0x8e, 0xbe, 0x36, 0x6b, 0x59, 0x8c, 0xb5,
+	0xb5, 0xfe, 0xca, 0xde, 0x9a, 0x1b, 0xd7, 0xd7, 0x16, 0xb5, 0xb7, 0xb1, 0xb6, 0xd1, 0x75, 0xd8,
+	0x77, 0x2b, 0x38, 0x85, 0x82, 0x47, 0xd0, 0x14, 0x05, 0xb7, 0xa1, 0xc0, 0xa8, 0xc1, 0xed, 0xcd,
+	0xb9, 0x23, 0x33, 0x66, 0xcf, 0xf0, 0x45, 0x1c, 0x89, 0x84, 0x50, 0x8e, 0x23, 0x23, 0x59, 0xdb,
+	0xdb, 0xd5, 0xcc, 0xc2, 0x8e, 0xcf, 0xa1, 0xb9, 0xef, 0xc5, 0x3e, 0x8e, 0xd0, 0x82, 0xd5, 0xae,
+	0xb0, 0xe2, 0x4b, 0xe8, 0x3e, 0xc3, 0xf4, 0x98, 0xff, 0x38, 0x38, 0x8c, 0x27, 0xc9, 0x42, 0x15,
+	0xdf, 0xd3, 0xdf, 0x01, 0x0a, 0x71, 0x67, 0xe5, 0x6d, 0x93, 0x0b, 0x3e, 0xfc, 0xef, 0x00, 0x79,
+	0x9f, 0xb4, 0x8f, 0x99, 0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.